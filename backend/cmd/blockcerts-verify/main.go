@@ -0,0 +1,93 @@
+// Command blockcerts-verify checks a Blockcerts v3 certificate exported by BlockCred against a chain
+// RPC endpoint, without contacting the BlockCred API.
+//
+//	go run ./cmd/blockcerts-verify -rpc http://localhost:8545 -issuer 0x53b8... certificate.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"blockcred-backend/internal/blockcerts"
+)
+
+func main() {
+	rpcURL := flag.String("rpc", "http://localhost:8545", "JSON-RPC endpoint of the anchoring chain")
+	issuer := flag.String("issuer", "", "expected anchoring account (defaults to the account named in the proof)")
+	contract := flag.String("contract", "", "certificate contract address for revocation checks (optional)")
+	asJSON := flag.Bool("json", false, "print the result as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <certificate.json | ->\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	raw, err := readInput(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	verifier := &blockcerts.Verifier{RPCURL: *rpcURL, IssuerAddress: *issuer, ContractAddress: *contract}
+	result, err := verifier.Verify(raw)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "not a verifiable Blockcerts document:", err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+	} else {
+		printResult(result)
+	}
+	if !result.Valid {
+		os.Exit(1)
+	}
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func printResult(r *blockcerts.Result) {
+	if r.CertificateID != "" {
+		fmt.Printf("Certificate: %s\n", r.CertificateID)
+	}
+	fmt.Printf("Merkle root: %s\n", r.MerkleRoot)
+	if r.TxHash != "" {
+		fmt.Printf("Anchor tx:   %s (block %d)\n", r.TxHash, r.BlockNumber)
+	}
+	if r.AnchoredAt != nil {
+		fmt.Printf("Anchored at: %s\n", r.AnchoredAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	fmt.Println()
+	for _, c := range r.Checks {
+		mark := "✅"
+		if !c.Passed {
+			mark = "❌"
+		}
+		fmt.Printf("%s %s", mark, c.Name)
+		if c.Detail != "" {
+			fmt.Printf(": %s", c.Detail)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	if r.Valid {
+		fmt.Println("VALID")
+	} else {
+		fmt.Println("INVALID")
+	}
+}
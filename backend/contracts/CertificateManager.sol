@@ -16,6 +16,14 @@ contract CertificateManager {
         uint256 revokedAt;       // Timestamp when revoked
     }
     
+    struct Suspension {
+        bool isSuspended;        // Temporary, reversible hold
+        string reason;
+        address suspendedBy;
+        uint256 suspendedAt;
+        uint256 reinstateAt;     // 0 = until explicitly reinstated
+    }
+    
     // Student ID to Wallet Address mapping
     mapping(string => address) public studentWallets;
     mapping(address => string) public walletStudents;
@@ -36,6 +44,9 @@ contract CertificateManager {
     mapping(address => Issuer) public issuers;
     mapping(address => bool) public isAuthorizedIssuer;
     mapping(string => string[]) public studentCertificates;
+    mapping(string => Suspension) public suspensions;
+    mapping(string => string) public supersededBy;   // old certId => replacing certId
+    mapping(string => string) public supersedes;     // new certId => replaced certId
     
     string[] public allCertificateIds;
     address[] public allIssuers;
@@ -49,6 +60,9 @@ contract CertificateManager {
     event CertificateIssued(string indexed certId, string studentId, string certType, string ipfsCID, address issuer);
     event CertificateRevoked(string indexed certId, address revoker, string reason);
     event CertificateVerified(string indexed certId, bool isValid);
+    event CertificateSuspended(string indexed certId, address suspendedBy, string reason, uint256 reinstateAt);
+    event CertificateReinstated(string indexed certId, address reinstatedBy);
+    event CertificateSuperseded(string indexed oldCertId, string newCertId, address issuer);
 
     // Modifiers
     modifier onlyAdmin() {
@@ -208,6 +222,77 @@ contract CertificateManager {
         emit CertificateRevoked(_certId, msg.sender, _reason);
     }
 
+    function suspendCertificate(string memory _certId, string memory _reason, uint256 _reinstateAt)
+        external
+        onlyAuthorizedIssuer
+        requireCertificateExists(_certId)
+    {
+        require(!certificates[_certId].isRevoked, "Certificate is revoked");
+        require(!_isSuspended(_certId), "Certificate already suspended");
+        require(bytes(_reason).length > 0, "Reason cannot be empty");
+        require(_reinstateAt == 0 || _reinstateAt > block.timestamp, "Reinstate time must be in the future");
+        require(
+            certificates[_certId].issuer == msg.sender || msg.sender == admin,
+            "Only issuer or admin can suspend certificate"
+        );
+
+        suspensions[_certId] = Suspension({
+            isSuspended: true,
+            reason: _reason,
+            suspendedBy: msg.sender,
+            suspendedAt: block.timestamp,
+            reinstateAt: _reinstateAt
+        });
+
+        emit CertificateSuspended(_certId, msg.sender, _reason, _reinstateAt);
+    }
+
+    function reinstateCertificate(string memory _certId)
+        external
+        onlyAuthorizedIssuer
+        requireCertificateExists(_certId)
+    {
+        require(suspensions[_certId].isSuspended, "Certificate is not suspended");
+        require(
+            certificates[_certId].issuer == msg.sender || msg.sender == admin,
+            "Only issuer or admin can reinstate certificate"
+        );
+
+        delete suspensions[_certId];
+
+        emit CertificateReinstated(_certId, msg.sender);
+    }
+
+    function supersedeCertificate(string memory _oldCertId, string memory _newCertId)
+        external
+        onlyAuthorizedIssuer
+        requireCertificateExists(_oldCertId)
+        requireCertificateExists(_newCertId)
+    {
+        require(!certificates[_oldCertId].isRevoked, "Certificate is revoked");
+        require(bytes(supersededBy[_oldCertId]).length == 0, "Certificate already superseded");
+        require(bytes(supersedes[_newCertId]).length == 0, "New certificate already supersedes another");
+        require(
+            keccak256(bytes(certificates[_oldCertId].studentId)) == keccak256(bytes(certificates[_newCertId].studentId)),
+            "Student mismatch"
+        );
+        require(
+            certificates[_oldCertId].issuer == msg.sender || msg.sender == admin,
+            "Only issuer or admin can supersede certificate"
+        );
+
+        supersededBy[_oldCertId] = _newCertId;
+        supersedes[_newCertId] = _oldCertId;
+
+        emit CertificateSuperseded(_oldCertId, _newCertId, msg.sender);
+    }
+
+    // A suspension with a reinstate time lapses on its own once that time has passed
+    function _isSuspended(string memory _certId) internal view returns (bool) {
+        Suspension memory s = suspensions[_certId];
+        return s.isSuspended && (s.reinstateAt == 0 || block.timestamp < s.reinstateAt);
+    }
+
     // Public view functions
     function getCertificate(string memory _certId) 
         external 
@@ -256,7 +341,28 @@ contract CertificateManager {
         view 
         returns (bool) 
     {
-        return certificateExists[_certId] && !certificates[_certId].isRevoked;
+        return certificateExists[_certId] &&
+            !certificates[_certId].isRevoked &&
+            !_isSuspended(_certId) &&
+            bytes(supersededBy[_certId]).length == 0;
+    }
+
+    // Follows the supersession chain to the latest version of a certificate
+    function getCurrentVersion(string memory _certId)
+        external
+        view
+        requireCertificateExists(_certId)
+        returns (string memory)
+    {
+        string memory current = _certId;
+        while (bytes(supersededBy[current]).length > 0) {
+            current = supersededBy[current];
+        }
+        return current;
+    }
+
+    function isSuspended(string memory _certId) external view returns (bool) {
+        return _isSuspended(_certId);
     }
 
     function getIssuerInfo(address _issuerAddress) 
@@ -0,0 +1,186 @@
+package blockcerts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Minimal CBOR (RFC 8949) codec for the values carried in a MerkleProof2019 proofValue:
+// text strings, arrays and maps with text keys. Maps are written in deterministic key order.
+
+const (
+	cborText  = 3
+	cborArray = 4
+	cborMap   = 5
+)
+
+func cborEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cborWrite(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func cborWrite(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case string:
+		cborHeader(buf, cborText, uint64(len(val)))
+		buf.WriteString(val)
+	case []interface{}:
+		cborHeader(buf, cborArray, uint64(len(val)))
+		for _, item := range val {
+			if err := cborWrite(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		type entry struct {
+			key   []byte
+			value interface{}
+		}
+		entries := make([]entry, 0, len(val))
+		for k, item := range val {
+			var key bytes.Buffer
+			cborHeader(&key, cborText, uint64(len(k)))
+			key.WriteString(k)
+			entries = append(entries, entry{key.Bytes(), item})
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+
+		cborHeader(buf, cborMap, uint64(len(val)))
+		for _, e := range entries {
+			buf.Write(e.key)
+			if err := cborWrite(buf, e.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: unsupported type %T", v)
+	}
+	return nil
+}
+
+func cborHeader(buf *bytes.Buffer, major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		buf.WriteByte(m | byte(n))
+	case n <= 0xff:
+		buf.Write([]byte{m | 24, byte(n)})
+	case n <= 0xffff:
+		buf.WriteByte(m | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= 0xffffffff:
+		buf.WriteByte(m | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(m | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+func cborDecode(data []byte) (interface{}, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("cbor: trailing data")
+	}
+	return v, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) value(depth int) (interface{}, error) {
+	if depth > 16 {
+		return nil, fmt.Errorf("cbor: nesting too deep")
+	}
+	major, n, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborText:
+		if uint64(len(d.data)-d.pos) < n {
+			return nil, fmt.Errorf("cbor: truncated string")
+		}
+		s := string(d.data[d.pos : d.pos+int(n)])
+		d.pos += int(n)
+		return s, nil
+	case cborArray:
+		if n > uint64(len(d.data)) {
+			return nil, fmt.Errorf("cbor: array length out of range")
+		}
+		out := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	case cborMap:
+		if n > uint64(len(d.data)) {
+			return nil, fmt.Errorf("cbor: map length out of range")
+		}
+		out := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor: map key is not a string")
+			}
+			if out[k], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cbor: unsupported major type %d", major)
+}
+
+func (d *cborDecoder) header() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, fmt.Errorf("cbor: unexpected end of data")
+	}
+	b := d.data[d.pos]
+	d.pos++
+	major, info := b>>5, b&0x1f
+
+	size := 0
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, fmt.Errorf("cbor: indefinite lengths are not supported")
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, fmt.Errorf("cbor: truncated header")
+	}
+	var n uint64
+	for _, c := range d.data[d.pos : d.pos+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.pos += size
+	return major, n, nil
+}
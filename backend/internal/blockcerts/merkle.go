@@ -0,0 +1,103 @@
+package blockcerts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PathStep is one sibling hash on the way from a leaf to the Merkle root
+type PathStep struct {
+	Left  string `json:"left,omitempty"`
+	Right string `json:"right,omitempty"`
+}
+
+// Tree is a SHA-256 Merkle tree laid out like Chainpoint/merkletools: pairs are hashed as
+// left||right and an unpaired node is promoted unchanged to the next level.
+type Tree struct {
+	levels [][][]byte // levels[0] are the leaves, the last level holds the root
+}
+
+// NewTree builds a tree over the given leaf hashes
+func NewTree(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("merkle tree needs at least one leaf")
+	}
+	level := make([][]byte, len(leaves))
+	copy(level, leaves)
+	t := &Tree{levels: [][][]byte{level}}
+
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashPair(level[i], level[i+1]))
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root returns the hex-encoded Merkle root
+func (t *Tree) Root() string {
+	return hex.EncodeToString(t.levels[len(t.levels)-1][0])
+}
+
+// Path returns the sibling hashes proving leaf index belongs to the tree
+func (t *Tree) Path(index int) []PathStep {
+	path := []PathStep{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			if index%2 == 0 {
+				path = append(path, PathStep{Right: hex.EncodeToString(level[sibling])})
+			} else {
+				path = append(path, PathStep{Left: hex.EncodeToString(level[sibling])})
+			}
+		}
+		index /= 2
+	}
+	return path
+}
+
+// VerifyPath recomputes the root from a target hash and its path
+func VerifyPath(targetHash string, path []PathStep, merkleRoot string) (bool, error) {
+	node, err := hex.DecodeString(targetHash)
+	if err != nil {
+		return false, fmt.Errorf("invalid target hash: %w", err)
+	}
+	for _, step := range path {
+		switch {
+		case step.Left != "":
+			sibling, err := hex.DecodeString(step.Left)
+			if err != nil {
+				return false, fmt.Errorf("invalid path hash: %w", err)
+			}
+			node = hashPair(sibling, node)
+		case step.Right != "":
+			sibling, err := hex.DecodeString(step.Right)
+			if err != nil {
+				return false, fmt.Errorf("invalid path hash: %w", err)
+			}
+			node = hashPair(node, sibling)
+		default:
+			return false, fmt.Errorf("path step has neither left nor right")
+		}
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(merkleRoot, "0x"))
+	if err != nil {
+		return false, fmt.Errorf("invalid merkle root: %w", err)
+	}
+	return bytes.Equal(node, root), nil
+}
+
+func hashPair(left, right []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, left...), right...))
+	return sum[:]
+}
@@ -0,0 +1,172 @@
+package blockcerts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testIssuer = "0x627306090abaB3A6e1400e9345bC60c78a8BEf57"
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("leaf-%d", i)))
+		leaves[i] = sum[:]
+	}
+	return leaves
+}
+
+func TestTreeRootPromotesUnpairedNodes(t *testing.T) {
+	l := testLeaves(5)
+	// ((l0 l1) (l2 l3)) l4: the fifth leaf is carried up unchanged until it has a partner
+	want := hex.EncodeToString(hashPair(hashPair(hashPair(l[0], l[1]), hashPair(l[2], l[3])), l[4]))
+
+	tree, err := NewTree(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.Root(); got != want {
+		t.Fatalf("root = %s, want %s", got, want)
+	}
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	anchor := EthereumAnchor(1337, "0x"+hex.EncodeToString(make([]byte, 32)))
+	for _, n := range []int{1, 2, 3, 5, 7, 8, 9} {
+		leaves := testLeaves(n)
+		tree, err := NewTree(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := tree.Root()
+
+		for i, leaf := range leaves {
+			encoded, err := EncodeProofValue(MerkleProof2019{
+				Path:       tree.Path(i),
+				MerkleRoot: root,
+				TargetHash: hex.EncodeToString(leaf),
+				Anchors:    []string{anchor},
+			})
+			if err != nil {
+				t.Fatalf("n=%d leaf %d: encode: %v", n, i, err)
+			}
+			proof, err := DecodeProofValue(encoded)
+			if err != nil {
+				t.Fatalf("n=%d leaf %d: decode: %v", n, i, err)
+			}
+			if proof.MerkleRoot != root || proof.Anchors[0] != anchor {
+				t.Fatalf("n=%d leaf %d: decoded proof %+v does not match", n, i, proof)
+			}
+
+			ok, err := VerifyPath(proof.TargetHash, proof.Path, "0x"+root)
+			if err != nil || !ok {
+				t.Fatalf("n=%d leaf %d: path does not lead to root (err %v)", n, i, err)
+			}
+
+			// The path of one leaf must not prove any other leaf
+			other := hex.EncodeToString(leaves[(i+1)%n])
+			if ok, _ := VerifyPath(other, proof.Path, root); ok && n > 1 {
+				t.Fatalf("n=%d leaf %d: path also proves leaf %d", n, i, (i+1)%n)
+			}
+		}
+	}
+}
+
+func TestVerifierChecksAnchoredRoot(t *testing.T) {
+	docs := make([]map[string]interface{}, 3)
+	leaves := make([][]byte, len(docs))
+	for i := range docs {
+		docs[i] = map[string]interface{}{
+			"@context":          []interface{}{ContextVC, ContextV3},
+			"type":              []interface{}{"VerifiableCredential", "BlockcertsCredential"},
+			"issuer":            "did:web:example.edu",
+			"credentialSubject": map[string]interface{}{"id": fmt.Sprintf("student-%d", i)},
+		}
+		target, err := TargetHash(docs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves[i], _ = hex.DecodeString(target)
+	}
+	tree, err := NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txHash := "0x" + hex.EncodeToString(make([]byte, 32))
+	for i := range docs {
+		proofValue, err := EncodeProofValue(MerkleProof2019{
+			Path:       tree.Path(i),
+			MerkleRoot: tree.Root(),
+			TargetHash: hex.EncodeToString(leaves[i]),
+			Anchors:    []string{EthereumAnchor(1337, txHash)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs[i]["proof"] = map[string]interface{}{
+			"type":               ProofType,
+			"proofValue":         proofValue,
+			"verificationMethod": KeyIDPrefix + testIssuer,
+		}
+	}
+
+	cases := []struct {
+		name       string
+		anchored   string
+		wantValid  bool
+		failedStep string
+	}{
+		{"anchored root", tree.Root(), true, ""},
+		{"different root", hex.EncodeToString(hashPair(leaves[0], leaves[0])), false, "blockchain anchor"},
+	}
+	for _, tc := range cases {
+		rpc := httptest.NewServer(chainStub(tc.anchored))
+		verifier := &Verifier{RPCURL: rpc.URL}
+
+		for i, doc := range docs {
+			raw, _ := json.Marshal(doc)
+			result, err := verifier.Verify(raw)
+			if err != nil {
+				t.Fatalf("%s: document %d: %v", tc.name, i, err)
+			}
+			if result.Valid != tc.wantValid {
+				t.Fatalf("%s: document %d: valid = %v, checks %+v", tc.name, i, result.Valid, result.Checks)
+			}
+			for _, check := range result.Checks {
+				if check.Passed == (check.Name == tc.failedStep) {
+					t.Fatalf("%s: document %d: check %q passed = %v", tc.name, i, check.Name, check.Passed)
+				}
+			}
+		}
+		rpc.Close()
+	}
+}
+
+// chainStub answers the JSON-RPC calls the verifier makes for a chain holding one anchoring transaction
+func chainStub(merkleRoot string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{}
+		switch req.Method {
+		case "eth_chainId":
+			result = "0x539"
+		case "eth_getTransactionByHash":
+			result = map[string]interface{}{"from": testIssuer, "input": "0x" + merkleRoot, "blockNumber": "0x10"}
+		case "eth_getTransactionReceipt":
+			result = map[string]interface{}{"status": "0x1"}
+		case "eth_getBlockByNumber":
+			result = map[string]interface{}{"number": "0x10", "timestamp": "0x65000000"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}
+}
@@ -0,0 +1,129 @@
+// Package blockcerts builds and verifies Blockcerts v3 documents anchored on an Ethereum-compatible chain.
+//
+// Each certificate document is hashed (SHA-256 over its JCS canonical form, proof removed) into a
+// target hash; a batch of target hashes forms a Merkle tree whose root is written as the data of a
+// single transaction. The MerkleProof2019 proof carries the path from the target hash to that root
+// and a blink URI naming the anchoring transaction.
+package blockcerts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"blockcred-backend/internal/jcs"
+	"blockcred-backend/internal/multibase"
+)
+
+const (
+	ContextVC   = "https://www.w3.org/2018/credentials/v1"
+	ContextV3   = "https://w3id.org/blockcerts/v3"
+	ProofType   = "MerkleProof2019"
+	KeyIDPrefix = "ecdsa-koblitz-pubkey:" // verificationMethod prefix naming the anchoring account
+)
+
+// MerkleProof2019 is the decoded content of a proofValue
+type MerkleProof2019 struct {
+	Path       []PathStep `json:"path"`
+	MerkleRoot string     `json:"merkleRoot"`
+	TargetHash string     `json:"targetHash"`
+	Anchors    []string   `json:"anchors"`
+}
+
+// EncodeProofValue serializes the proof as base58btc multibase over CBOR
+func EncodeProofValue(p MerkleProof2019) (string, error) {
+	path := make([]interface{}, 0, len(p.Path))
+	for _, step := range p.Path {
+		if step.Left != "" {
+			path = append(path, map[string]interface{}{"left": step.Left})
+		} else {
+			path = append(path, map[string]interface{}{"right": step.Right})
+		}
+	}
+	anchors := make([]interface{}, 0, len(p.Anchors))
+	for _, a := range p.Anchors {
+		anchors = append(anchors, a)
+	}
+
+	encoded, err := cborEncode(map[string]interface{}{
+		"path":       path,
+		"merkleRoot": p.MerkleRoot,
+		"targetHash": p.TargetHash,
+		"anchors":    anchors,
+	})
+	if err != nil {
+		return "", err
+	}
+	return multibase.Encode(encoded), nil
+}
+
+// DecodeProofValue parses a proofValue produced by EncodeProofValue
+func DecodeProofValue(s string) (*MerkleProof2019, error) {
+	raw, err := multibase.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proofValue: %w", err)
+	}
+	decoded, err := cborDecode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proofValue: %w", err)
+	}
+	// The decoded value only holds strings, arrays and maps, so a JSON round trip maps it onto the struct
+	asJSON, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	var proof MerkleProof2019
+	if err := json.Unmarshal(asJSON, &proof); err != nil {
+		return nil, fmt.Errorf("invalid proofValue: %w", err)
+	}
+	if proof.MerkleRoot == "" || proof.TargetHash == "" || len(proof.Anchors) == 0 {
+		return nil, fmt.Errorf("proofValue is missing merkleRoot, targetHash or anchors")
+	}
+	return &proof, nil
+}
+
+// TargetHash hashes a document without its proof
+func TargetHash(document interface{}) (string, error) {
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return "", fmt.Errorf("document is not a JSON object: %w", err)
+	}
+	delete(generic, "proof")
+
+	canonical, err := jcs.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// EthereumAnchor formats a transaction as a blink URI, e.g. blink:eth:1337:0xabc...
+func EthereumAnchor(chainID int64, txHash string) string {
+	return fmt.Sprintf("blink:eth:%d:%s", chainID, txHash)
+}
+
+// ParseEthereumAnchor returns the chain ID and transaction hash of an Ethereum blink URI.
+// Named networks (mainnet, sepolia, ...) are reported with chain ID 0.
+func ParseEthereumAnchor(anchor string) (int64, string, error) {
+	parts := strings.Split(anchor, ":")
+	if len(parts) != 4 || parts[0] != "blink" || parts[1] != "eth" {
+		return 0, "", fmt.Errorf("unsupported anchor %q", anchor)
+	}
+	txHash := parts[3]
+	if _, err := hex.DecodeString(strings.TrimPrefix(txHash, "0x")); err != nil || len(txHash) != 66 {
+		return 0, "", fmt.Errorf("invalid transaction hash in anchor %q", anchor)
+	}
+	chainID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		chainID = 0
+	}
+	return chainID, txHash, nil
+}
@@ -0,0 +1,292 @@
+package blockcerts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const verifyCertificateABI = `[{
+	"name": "verifyCertificate",
+	"type": "function",
+	"inputs": [{"name": "_certId", "type": "string"}],
+	"outputs": [{"name": "", "type": "bool"}]
+}]`
+
+// Verifier checks Blockcerts documents using nothing but a JSON-RPC endpoint of the anchoring chain
+type Verifier struct {
+	RPCURL          string
+	IssuerAddress   string // Optional: expected anchoring account; otherwise the document's own claim is used
+	ContractAddress string // Optional: certificate contract to check revocation and suspension
+	HTTPClient      *http.Client
+}
+
+// Check is the outcome of one verification step
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Result reports every check that was run against a document
+type Result struct {
+	Valid         bool       `json:"valid"`
+	CertificateID string     `json:"certificate_id,omitempty"`
+	MerkleRoot    string     `json:"merkle_root,omitempty"`
+	TxHash        string     `json:"tx_hash,omitempty"`
+	BlockNumber   uint64     `json:"block_number,omitempty"`
+	AnchoredAt    *time.Time `json:"anchored_at,omitempty"`
+	IssuerAddress string     `json:"issuer_address,omitempty"`
+	Checks        []Check    `json:"checks"`
+}
+
+func (r *Result) add(name string, err error) bool {
+	check := Check{Name: name, Passed: err == nil}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, check)
+	return err == nil
+}
+
+// Verify runs the Blockcerts checks: document integrity, Merkle inclusion, anchor transaction,
+// issuer account, expiry and, when a contract is configured, on-chain certificate status.
+// An error is only returned when the input is not a Blockcerts document at all.
+func (v *Verifier) Verify(raw []byte) (*Result, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("document is not a JSON object: %w", err)
+	}
+	proofObj, ok := doc["proof"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("document has no proof")
+	}
+	if proofObj["type"] != ProofType {
+		return nil, fmt.Errorf("unsupported proof type %v, expected %s", proofObj["type"], ProofType)
+	}
+	proofValue, _ := proofObj["proofValue"].(string)
+	proof, err := DecodeProofValue(proofValue)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{MerkleRoot: proof.MerkleRoot, Checks: []Check{}}
+	if status, ok := doc["credentialStatus"].(map[string]interface{}); ok {
+		result.CertificateID, _ = status["certificateId"].(string)
+	}
+
+	result.add("document integrity", v.checkTargetHash(doc, proof))
+	result.add("merkle inclusion", v.checkPath(proof))
+
+	from, err := v.checkAnchor(proof, result)
+	if result.add("blockchain anchor", err) {
+		verificationMethod, _ := proofObj["verificationMethod"].(string)
+		result.add("issuer account", v.checkIssuer(from, verificationMethod, result))
+	}
+
+	result.add("expiry", checkExpiry(doc))
+
+	if v.ContractAddress != "" && result.CertificateID != "" {
+		result.add("certificate status", v.checkContract(result.CertificateID))
+	}
+
+	result.Valid = true
+	for _, c := range result.Checks {
+		result.Valid = result.Valid && c.Passed
+	}
+	return result, nil
+}
+
+func (v *Verifier) checkTargetHash(doc map[string]interface{}, proof *MerkleProof2019) error {
+	computed, err := TargetHash(doc)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(computed, proof.TargetHash) {
+		return fmt.Errorf("document hash %s does not match proof target %s", computed, proof.TargetHash)
+	}
+	return nil
+}
+
+func (v *Verifier) checkPath(proof *MerkleProof2019) error {
+	ok, err := VerifyPath(proof.TargetHash, proof.Path, proof.MerkleRoot)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("merkle path does not lead to root %s", proof.MerkleRoot)
+	}
+	return nil
+}
+
+// checkAnchor confirms a mined, successful transaction carries the Merkle root and returns its sender
+func (v *Verifier) checkAnchor(proof *MerkleProof2019, result *Result) (string, error) {
+	var (
+		chainID int64
+		txHash  string
+		err     error
+	)
+	for _, anchor := range proof.Anchors {
+		if chainID, txHash, err = ParseEthereumAnchor(anchor); err == nil {
+			break
+		}
+	}
+	if txHash == "" {
+		return "", fmt.Errorf("no Ethereum anchor in proof: %v", err)
+	}
+	result.TxHash = txHash
+
+	if chainID != 0 {
+		var remote string
+		if err := v.call("eth_chainId", []interface{}{}, &remote); err != nil {
+			return "", err
+		}
+		if remoteID, ok := new(big.Int).SetString(strings.TrimPrefix(remote, "0x"), 16); !ok || remoteID.Int64() != chainID {
+			return "", fmt.Errorf("RPC endpoint serves chain %s, anchor is on chain %d", remote, chainID)
+		}
+	}
+
+	var tx struct {
+		From        string  `json:"from"`
+		Input       string  `json:"input"`
+		BlockNumber *string `json:"blockNumber"`
+	}
+	if err := v.call("eth_getTransactionByHash", []interface{}{txHash}, &tx); err != nil {
+		return "", err
+	}
+	if tx.BlockNumber == nil {
+		return "", fmt.Errorf("transaction %s not found or not yet mined", txHash)
+	}
+	if !strings.EqualFold(strings.TrimPrefix(tx.Input, "0x"), strings.TrimPrefix(proof.MerkleRoot, "0x")) {
+		return "", fmt.Errorf("transaction data does not contain merkle root %s", proof.MerkleRoot)
+	}
+
+	var receipt struct {
+		Status string `json:"status"`
+	}
+	if err := v.call("eth_getTransactionReceipt", []interface{}{txHash}, &receipt); err != nil {
+		return "", err
+	}
+	if receipt.Status != "" && receipt.Status != "0x1" {
+		return "", fmt.Errorf("anchoring transaction failed")
+	}
+
+	var block struct {
+		Number    string `json:"number"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := v.call("eth_getBlockByNumber", []interface{}{*tx.BlockNumber, false}, &block); err != nil {
+		return "", err
+	}
+	if n, ok := new(big.Int).SetString(strings.TrimPrefix(block.Number, "0x"), 16); ok {
+		result.BlockNumber = n.Uint64()
+	}
+	if ts, ok := new(big.Int).SetString(strings.TrimPrefix(block.Timestamp, "0x"), 16); ok {
+		anchoredAt := time.Unix(ts.Int64(), 0).UTC()
+		result.AnchoredAt = &anchoredAt
+	}
+	return tx.From, nil
+}
+
+func (v *Verifier) checkIssuer(from, verificationMethod string, result *Result) error {
+	result.IssuerAddress = common.HexToAddress(from).Hex()
+
+	expected := v.IssuerAddress
+	if expected == "" {
+		expected = strings.TrimPrefix(verificationMethod, KeyIDPrefix)
+	}
+	if !common.IsHexAddress(expected) {
+		return fmt.Errorf("no issuer account to compare against (set an expected issuer address)")
+	}
+	if !strings.EqualFold(common.HexToAddress(expected).Hex(), result.IssuerAddress) {
+		return fmt.Errorf("anchored by %s, expected %s", result.IssuerAddress, common.HexToAddress(expected).Hex())
+	}
+	return nil
+}
+
+func checkExpiry(doc map[string]interface{}) error {
+	for _, field := range []string{"expirationDate", "validUntil"} {
+		if s, ok := doc[field].(string); ok {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", field, err)
+			}
+			if time.Now().After(t) {
+				return fmt.Errorf("certificate expired on %s", t.Format(time.RFC3339))
+			}
+		}
+	}
+	return nil
+}
+
+// checkContract asks the certificate contract whether the certificate is still valid
+func (v *Verifier) checkContract(certID string) error {
+	contractABI, err := abi.JSON(strings.NewReader(verifyCertificateABI))
+	if err != nil {
+		return err
+	}
+	data, err := contractABI.Pack("verifyCertificate", certID)
+	if err != nil {
+		return err
+	}
+
+	var out string
+	call := map[string]interface{}{"to": v.ContractAddress, "data": "0x" + hex.EncodeToString(data)}
+	if err := v.call("eth_call", []interface{}{call, "latest"}, &out); err != nil {
+		return err
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(out, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid eth_call result: %w", err)
+	}
+	values, err := contractABI.Unpack("verifyCertificate", raw)
+	if err != nil || len(values) != 1 {
+		return fmt.Errorf("unexpected verifyCertificate result")
+	}
+	if valid, _ := values[0].(bool); !valid {
+		return fmt.Errorf("certificate %s is revoked, suspended or unknown on-chain", certID)
+	}
+	return nil
+}
+
+func (v *Verifier) call(method string, params []interface{}, out interface{}) error {
+	client := v.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params, "id": 1})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(v.RPCURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var rpc struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		return fmt.Errorf("%s: invalid RPC response: %w", method, err)
+	}
+	if rpc.Error != nil {
+		return fmt.Errorf("%s: %s", method, rpc.Error.Message)
+	}
+	if len(rpc.Result) == 0 || string(rpc.Result) == "null" {
+		return fmt.Errorf("%s: no result", method)
+	}
+	return json.Unmarshal(rpc.Result, out)
+}
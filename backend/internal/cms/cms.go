@@ -0,0 +1,200 @@
+// Package cms builds the CMS SignedData structures (RFC 5652) used by PAdES PDF signatures and
+// RFC 3161 timestamp tokens.
+//
+// Only what those two need is supported: a single signer identified by issuer and serial number,
+// SHA-256 digests, ECDSA or RSA PKCS #1 v1.5 keys, and the ESS signing-certificate-v2 attribute
+// that binds the signature to the signer's certificate.
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Object identifiers used in the structures built here
+var (
+	OIDData                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	OIDSignedData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	OIDTSTInfo              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	OIDContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	OIDMessageDigest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	OIDSigningCertificateV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	OIDTimeStampToken       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+	OIDSHA256               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	OIDECDSAWithSHA256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	OIDSHA256WithRSA        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+)
+
+// Attribute is a CMS attribute with a single, already encoded value
+type Attribute struct {
+	Type  asn1.ObjectIdentifier
+	Value []byte // DER of the attribute value
+}
+
+// Signer describes the signing key and the certificates embedded with the signature
+type Signer struct {
+	Key         crypto.Signer
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate // Intermediates embedded after the signer certificate
+}
+
+// SignedData holds the inputs of one SignedData structure
+type SignedData struct {
+	ContentType asn1.ObjectIdentifier // OIDData for detached signatures over arbitrary bytes
+	Content     []byte                // The signed content
+	Detached    bool                  // Leave the content out of the structure
+	Unsigned    func(signature []byte) ([]Attribute, error)
+}
+
+// Sign builds a DER ContentInfo wrapping SignedData. Signed attributes carry the content type,
+// the SHA-256 message digest and signing-certificate-v2. Unsigned, when set, receives the
+// signature value and returns attributes such as a timestamp token.
+func Sign(sd SignedData, signer Signer) ([]byte, error) {
+	sigAlg, err := signatureAlgorithm(signer.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(sd.Content)
+	certHash := sha256.Sum256(signer.Certificate.Raw)
+	signingCert := Sequence(Sequence(Sequence(
+		OctetString(certHash[:]),
+		Sequence(
+			Sequence(Explicit(4, signer.Certificate.RawIssuer)),
+			Integer(signer.Certificate.SerialNumber),
+		),
+	)))
+	signedAttrs := encodeAttributes([]Attribute{
+		{Type: OIDContentType, Value: OID(sd.ContentType)},
+		{Type: OIDMessageDigest, Value: OctetString(digest[:])},
+		{Type: OIDSigningCertificateV2, Value: signingCert},
+	})
+
+	// The signature covers the attributes encoded as a SET, not with their [0] tag
+	attrDigest := sha256.Sum256(TLV(0x31, signedAttrs))
+	signature, err := signer.Key.Sign(rand.Reader, attrDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	signerInfo := [][]byte{
+		Integer(big.NewInt(1)),
+		Sequence(signer.Certificate.RawIssuer, Integer(signer.Certificate.SerialNumber)),
+		Sequence(OID(OIDSHA256)),
+		TLV(0xA0, signedAttrs),
+		sigAlg,
+		OctetString(signature),
+	}
+	if sd.Unsigned != nil {
+		unsigned, err := sd.Unsigned(signature)
+		if err != nil {
+			return nil, err
+		}
+		if len(unsigned) > 0 {
+			signerInfo = append(signerInfo, TLV(0xA1, encodeAttributes(unsigned)))
+		}
+	}
+
+	encap := [][]byte{OID(sd.ContentType)}
+	if !sd.Detached {
+		encap = append(encap, Explicit(0, OctetString(sd.Content)))
+	}
+	certs := [][]byte{signer.Certificate.Raw}
+	for _, c := range signer.Chain {
+		certs = append(certs, c.Raw)
+	}
+
+	// Version 3 is required when the content is not id-data
+	version := int64(1)
+	if !sd.ContentType.Equal(OIDData) {
+		version = 3
+	}
+	signedData := Sequence(
+		Integer(big.NewInt(version)),
+		Set(Sequence(OID(OIDSHA256))),
+		Sequence(encap...),
+		TLV(0xA0, bytes.Join(certs, nil)),
+		Set(Sequence(signerInfo...)),
+	)
+	return Sequence(OID(OIDSignedData), Explicit(0, signedData)), nil
+}
+
+func signatureAlgorithm(key crypto.Signer) ([]byte, error) {
+	switch key.Public().(type) {
+	case *ecdsa.PublicKey:
+		return Sequence(OID(OIDECDSAWithSHA256)), nil
+	case *rsa.PublicKey:
+		return Sequence(OID(OIDSHA256WithRSA), Null()), nil
+	}
+	return nil, errors.New("signing key must be ECDSA or RSA")
+}
+
+// encodeAttributes returns the contents of a DER SET OF Attribute, sorted as DER requires
+func encodeAttributes(attrs []Attribute) []byte {
+	encoded := make([][]byte, len(attrs))
+	for i, a := range attrs {
+		encoded[i] = Sequence(OID(a.Type), Set(a.Value))
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil)
+}
+
+// TLV encodes a DER element with the given tag byte and contents
+func TLV(tag byte, contents []byte) []byte {
+	n := len(contents)
+	out := []byte{tag}
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, contents...)
+}
+
+// Sequence encodes a SEQUENCE of already encoded elements
+func Sequence(elements ...[]byte) []byte {
+	return TLV(0x30, bytes.Join(elements, nil))
+}
+
+// Set encodes a SET of already encoded elements in the given order
+func Set(elements ...[]byte) []byte {
+	return TLV(0x31, bytes.Join(elements, nil))
+}
+
+// Explicit wraps an element in a constructed context-specific tag
+func Explicit(tag int, element []byte) []byte {
+	return TLV(0xA0|byte(tag), element)
+}
+
+func OctetString(b []byte) []byte {
+	return TLV(0x04, b)
+}
+
+func Integer(n *big.Int) []byte {
+	b, _ := asn1.Marshal(n)
+	return b
+}
+
+func OID(oid asn1.ObjectIdentifier) []byte {
+	b, _ := asn1.Marshal(oid)
+	return b
+}
+
+func Null() []byte {
+	return []byte{0x05, 0x00}
+}
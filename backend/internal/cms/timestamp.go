@@ -0,0 +1,199 @@
+package cms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// TimestampRequest builds an RFC 3161 TimeStampReq for a SHA-256 digest, asking for the TSA certificate
+func TimestampRequest(digest []byte, nonce *big.Int) []byte {
+	return Sequence(
+		Integer(big.NewInt(1)),
+		messageImprint(digest),
+		Integer(nonce),
+		[]byte{0x01, 0x01, 0xFF}, // certReq TRUE
+	)
+}
+
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprintASN1
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          *big.Int              `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional"`
+	Extensions     asn1.RawValue         `asn1:"optional,tag:0"`
+}
+
+type messageImprintASN1 struct {
+	HashAlgorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+	HashedMessage []byte
+}
+
+// ParseTimestampRequest returns the SHA-256 digest and nonce of a TimeStampReq
+func ParseTimestampRequest(der []byte) (digest []byte, nonce *big.Int, err error) {
+	var req timeStampReq
+	if rest, err := asn1.Unmarshal(der, &req); err != nil || len(rest) > 0 {
+		return nil, nil, errors.New("malformed timestamp request")
+	}
+	if !req.MessageImprint.HashAlgorithm.Algorithm.Equal(OIDSHA256) || len(req.MessageImprint.HashedMessage) != sha256.Size {
+		return nil, nil, errors.New("timestamp requests must use SHA-256")
+	}
+	return req.MessageImprint.HashedMessage, req.Nonce, nil
+}
+
+// TSTInfo is the content a timestamp authority signs
+type TSTInfo struct {
+	Policy       asn1.ObjectIdentifier
+	Digest       []byte // SHA-256 message imprint
+	SerialNumber *big.Int
+	GenTime      time.Time
+	Nonce        *big.Int // Echoed from the request when it carried one
+}
+
+// Marshal encodes the TSTInfo with a one-second accuracy
+func (t TSTInfo) Marshal() []byte {
+	fields := [][]byte{
+		Integer(big.NewInt(1)),
+		OID(t.Policy),
+		messageImprint(t.Digest),
+		Integer(t.SerialNumber),
+		TLV(0x18, []byte(t.GenTime.UTC().Format("20060102150405Z"))),
+		Sequence(Integer(big.NewInt(1))),
+	}
+	if t.Nonce != nil {
+		fields = append(fields, Integer(t.Nonce))
+	}
+	return Sequence(fields...)
+}
+
+type tstInfoASN1 struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprintASN1
+	SerialNumber   *big.Int
+	GenTime        time.Time     `asn1:"generalized"`
+	Accuracy       asn1.RawValue `asn1:"optional"`
+	Ordering       bool          `asn1:"optional"`
+	Nonce          *big.Int      `asn1:"optional"`
+	TSA            asn1.RawValue `asn1:"optional,tag:0"`
+	Extensions     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// TimestampResponse wraps a granted timestamp token in a TimeStampResp
+func TimestampResponse(token []byte) []byte {
+	return Sequence(Sequence(Integer(big.NewInt(0))), token)
+}
+
+// TimestampRejection builds a TimeStampResp refusing the request
+func TimestampRejection(reason string) []byte {
+	return Sequence(Sequence(Integer(big.NewInt(2)), Sequence(TLV(0x0C, []byte(reason)))))
+}
+
+type timeStampResp struct {
+	Status struct {
+		Status       int
+		StatusString []asn1.RawValue `asn1:"optional"`
+		FailInfo     asn1.BitString  `asn1:"optional"`
+	}
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// ParseTimestampResponse checks a TimeStampResp against the digest and nonce that were sent and
+// returns its token, a DER ContentInfo, along with the time it asserts
+func ParseTimestampResponse(der, digest []byte, nonce *big.Int) ([]byte, time.Time, error) {
+	var resp timeStampResp
+	if _, err := asn1.Unmarshal(der, &resp); err != nil {
+		return nil, time.Time{}, errors.New("malformed timestamp response")
+	}
+	// 0 is granted and 1 granted with modifications
+	if resp.Status.Status > 1 {
+		reason := ""
+		for _, s := range resp.Status.StatusString {
+			reason += string(s.Bytes)
+		}
+		return nil, time.Time{}, fmt.Errorf("timestamp request rejected (status %d) %s", resp.Status.Status, reason)
+	}
+	token := resp.TimeStampToken.FullBytes
+	info, err := ParseTimestampToken(token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !bytes.Equal(info.Digest, digest) {
+		return nil, time.Time{}, errors.New("timestamp token covers a different digest")
+	}
+	if nonce != nil && (info.Nonce == nil || info.Nonce.Cmp(nonce) != 0) {
+		return nil, time.Time{}, errors.New("timestamp token does not echo the request nonce")
+	}
+	return token, info.GenTime, nil
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedDataASN1 struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo struct {
+		EContentType asn1.ObjectIdentifier
+		EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+	}
+	Certificates asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs         asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos  asn1.RawValue
+}
+
+// ParseTimestampToken extracts the TSTInfo from a timestamp token. It does not check the TSA's signature.
+func ParseTimestampToken(token []byte) (*TSTInfo, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(token, &ci); err != nil || !ci.ContentType.Equal(OIDSignedData) {
+		return nil, errors.New("malformed timestamp token")
+	}
+	var sd signedDataASN1
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil || !sd.EncapContentInfo.EContentType.Equal(OIDTSTInfo) {
+		return nil, errors.New("timestamp token does not carry a TSTInfo")
+	}
+	// A RawValue keeps the explicit [0] wrapper; inside it is the OCTET STRING holding the TSTInfo
+	var content []byte
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &content); err != nil {
+		return nil, errors.New("malformed timestamp token content")
+	}
+	var info tstInfoASN1
+	if _, err := asn1.Unmarshal(content, &info); err != nil {
+		return nil, errors.New("malformed TSTInfo")
+	}
+	return &TSTInfo{
+		Policy:       info.Policy,
+		Digest:       info.MessageImprint.HashedMessage,
+		SerialNumber: info.SerialNumber,
+		GenTime:      info.GenTime,
+		Nonce:        info.Nonce,
+	}, nil
+}
+
+// Certificates returns the certificates embedded in a SignedData ContentInfo
+func Certificates(der []byte) ([]*x509.Certificate, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil || !ci.ContentType.Equal(OIDSignedData) {
+		return nil, errors.New("malformed signed data")
+	}
+	var sd signedDataASN1
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, errors.New("malformed signed data")
+	}
+	return x509.ParseCertificates(sd.Certificates.Bytes)
+}
+
+func messageImprint(digest []byte) []byte {
+	return Sequence(Sequence(OID(OIDSHA256)), OctetString(digest))
+}
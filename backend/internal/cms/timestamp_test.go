@@ -0,0 +1,79 @@
+package cms
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+// testdata/timestamp-response.der is a granted TimeStampResp over fixtureDigest, answering a request
+// that carried fixtureNonce, signed by a self-signed timestamping certificate
+var (
+	fixtureDigest = sha256.Sum256([]byte("blockcred timestamp fixture"))
+	fixtureNonce  = big.NewInt(0x5eed)
+	fixtureTime   = time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+)
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	der, err := os.ReadFile("testdata/timestamp-response.der")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestParseStoredTimestampResponse(t *testing.T) {
+	token, genTime, err := ParseTimestampResponse(readFixture(t), fixtureDigest[:], fixtureNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !genTime.Equal(fixtureTime) {
+		t.Fatalf("genTime = %s, want %s", genTime, fixtureTime)
+	}
+
+	info, err := ParseTimestampToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SerialNumber.Int64() != 42 || info.Nonce.Cmp(fixtureNonce) != 0 {
+		t.Fatalf("TSTInfo serial %s, nonce %s", info.SerialNumber, info.Nonce)
+	}
+
+	certs, err := Certificates(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 {
+		t.Fatalf("token embeds %d certificates, want 1", len(certs))
+	}
+	eku := certs[0].ExtKeyUsage
+	if len(eku) != 1 || eku[0] != x509.ExtKeyUsageTimeStamping {
+		t.Fatalf("TSA certificate extended key usage = %v, want timeStamping only", eku)
+	}
+}
+
+func TestParseTimestampResponseRejectsMismatches(t *testing.T) {
+	der := readFixture(t)
+	other := sha256.Sum256([]byte("another document"))
+
+	cases := []struct {
+		name   string
+		der    []byte
+		digest []byte
+		nonce  *big.Int
+	}{
+		{"different digest", der, other[:], fixtureNonce},
+		{"different nonce", der, fixtureDigest[:], big.NewInt(1)},
+		{"rejected request", TimestampRejection("policy not supported"), fixtureDigest[:], fixtureNonce},
+		{"truncated response", der[:len(der)/2], fixtureDigest[:], fixtureNonce},
+	}
+	for _, tc := range cases {
+		if _, _, err := ParseTimestampResponse(tc.der, tc.digest, tc.nonce); err == nil {
+			t.Errorf("%s: response accepted", tc.name)
+		}
+	}
+}
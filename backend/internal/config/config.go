@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -9,15 +10,64 @@ import (
 type Config struct {
 	Port                string
 	AllowedOrigins      []string
+	TrustedProxies      string // Comma-separated IPs or CIDRs of reverse proxies whose X-Forwarded-For is honoured
 	JWTSecret           string
 	MongoURI            string
 	MongoDatabase       string
 	PinataAPIKey        string
 	PinataAPISecret     string
 	PinataGatewayURL    string
+	PinataAPIURL        string
+	ContentStore        string // Document backend: pinata, kubo, s3 or filesystem
+	KuboAPIURL          string // RPC API of a local Kubo (go-ipfs) node
+	KuboGatewayURL      string
+	S3Endpoint          string // S3-compatible endpoint, e.g. MinIO at http://localhost:9000
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	S3PublicURL         string // Public base URL of the bucket; defaults to the path-style endpoint URL
+	ContentDir          string // Root of the filesystem store used in development
+	ReplicaStores       string // Comma-separated content stores that keep a copy of every file, e.g. "kubo,s3"
+	PinCheckMinutes     int64  // Interval between pin health sweeps; 0 disables them
+	EncryptDocuments    bool   // Encrypt certificate files before they are stored
+	DocumentKEK         string // Hex AES-256 key that wraps the per-certificate document keys
+	DocumentCacheMB     int64  // Memory set aside for verified documents served by the download proxy; 0 disables it
+	CallTimeoutSecs     int64  // Deadline of each attempt at an IPFS or blockchain RPC call
+	CallAttempts        int64  // Attempts per call when failures are retryable
+	BreakerThreshold    int64  // Consecutive failures that open a dependency's circuit
+	BreakerResetSecs    int64  // How long an open circuit fails calls before trying again
 	BlockchainRPCURL    string
 	ContractAddress     string
-	PrivateKey          string
+	PrivateKey          string // Blockchain signing key: hex secp256k1 key, keystore:<path> or pkcs11:<URI>; empty uses node-held accounts
+	ChainID             int64 // EIP-155 chain ID used in did:ethr identifiers
+	ApprovalPolicies    string // e.g. "degree=coe+ssn_main_admin;marksheet=coe"
+	BulkUploadDir       string
+	BulkMaxUploadMB     int64
+	MaxUploadMB         int64  // Largest certificate document accepted by multipart issuance
+	UploadTypes         string // Comma-separated MIME types accepted for certificate documents
+	UploadSpoolDir      string // Where multipart uploads are spooled while they are issued
+	DocumentScanner     string // Malware scanner run before issuance: none or clamav
+	ClamAVAddress       string // host:port of the clamd daemon
+	RejectAction        string // What happens to documents that fail pre-issuance checks: reject or quarantine
+	QuarantineDir       string // Where quarantined documents are kept for review
+	PublicVerifyURL     string // Prefix of the verification link printed on generated certificates
+	PublicAPIURL        string // Externally reachable API base for hosted badge and issuer documents
+	InstitutionName     string
+	InstitutionDID      string
+	VCSigningKey        string // Credential signing key: hex Ed25519 seed, keystore:<path> or pkcs11:<URI>
+	SignPDFs            bool   // Apply the institution's PAdES signature to certificate PDFs
+	PDFSigningCert      string // PEM certificate of the document signing key, followed by any intermediates
+	PDFSigningKey       string // Private key matching PDFSigningCert: PEM path, keystore:<path> or pkcs11:<URI>
+	TimestampURL        string // RFC 3161 timestamp authority: empty for PAdES-B-B, "local" for the built-in stand-in, or a URL
+	DuplicatePolicy     string // What to do when an issuance repeats an active certificate: reject, warn or allow
+	IdempotencyHours    int64  // How long responses to Idempotency-Key requests are kept for replay
+	TokenSigningKey     string // Session JWT signing key: hex Ed25519 seed, PEM path, keystore:<path> or pkcs11:<URI>
+	TokenTTLHours       int64  // Lifetime of session tokens
+	KeystorePassword    string // Decrypts keystore: keys and encrypts keys created by rotation
+	KeystoreDir         string // Where rotated keys that are not on a PKCS #11 token are written
+	PKCS11Module        string // Path of the PKCS #11 module, e.g. /usr/lib/softhsm/libsofthsm2.so
+	PKCS11PIN           string // User PIN of the tokens named by pkcs11: URIs without pin-value
 }
 
 func Load() Config {
@@ -25,14 +75,63 @@ func Load() Config {
 		Port:             getEnv("PORT", "8080"),
 		JWTSecret:        getEnv("JWT_SECRET", "dev-secret"),
 		AllowedOrigins:   parseAllowedOrigins(getEnv("ALLOWED_ORIGINS", "*")),
+		TrustedProxies:   getEnv("TRUSTED_PROXIES", ""),
 		MongoURI:         getEnv("MONGO_URI", "mongodb://localhost:27017"),
 		MongoDatabase:    getEnv("MONGO_DATABASE", "blockcred"),
 		PinataAPIKey:     getEnv("PINATA_API_KEY", ""),
 		PinataAPISecret:  getEnv("PINATA_API_SECRET", ""),
 		PinataGatewayURL: getEnv("PINATA_GATEWAY_URL", "https://gateway.pinata.cloud/ipfs/"),
+		PinataAPIURL:     strings.TrimSuffix(getEnv("PINATA_API_URL", "https://api.pinata.cloud"), "/"),
+		ContentStore:     getEnv("CONTENT_STORE", "pinata"),
+		KuboAPIURL:       strings.TrimSuffix(getEnv("KUBO_API_URL", "http://127.0.0.1:5001"), "/"),
+		KuboGatewayURL:   getEnv("KUBO_GATEWAY_URL", "http://127.0.0.1:8080/ipfs/"),
+		S3Endpoint:       strings.TrimSuffix(getEnv("S3_ENDPOINT", "http://localhost:9000"), "/"),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET", "certificates"),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL:      strings.TrimSuffix(getEnv("S3_PUBLIC_URL", ""), "/"),
+		ContentDir:       getEnv("CONTENT_DIR", "uploads/content"),
+		ReplicaStores:    getEnv("REPLICA_STORES", ""),
+		PinCheckMinutes:  getEnvInt("PIN_CHECK_MINUTES", 60),
+		EncryptDocuments: getEnvBool("ENCRYPT_DOCUMENTS", true),
+		DocumentKEK:      getEnv("DOCUMENT_KEK", ""),
+		DocumentCacheMB:  getEnvInt("DOCUMENT_CACHE_MB", 64),
+		CallTimeoutSecs:  getEnvInt("OUTBOUND_TIMEOUT_SECONDS", 30),
+		CallAttempts:     getEnvInt("OUTBOUND_MAX_ATTEMPTS", 3),
+		BreakerThreshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerResetSecs: getEnvInt("BREAKER_COOLDOWN_SECONDS", 30),
 		BlockchainRPCURL: getEnv("BLOCKCHAIN_RPC_URL", "http://localhost:8545"),
 		ContractAddress:  getEnv("CONTRACT_ADDRESS", ""),
 		PrivateKey:       getEnv("PRIVATE_KEY", ""),
+		ChainID:          getEnvInt("CHAIN_ID", 1337),
+		ApprovalPolicies: getEnv("APPROVAL_POLICIES", "degree=coe+ssn_main_admin"),
+		BulkUploadDir:    getEnv("BULK_UPLOAD_DIR", "uploads/bulk"),
+		BulkMaxUploadMB:  getEnvInt("BULK_MAX_UPLOAD_MB", 500),
+		MaxUploadMB:      getEnvInt("MAX_UPLOAD_MB", 20),
+		UploadTypes:      getEnv("ALLOWED_UPLOAD_TYPES", "application/pdf,image/png,image/jpeg"),
+		UploadSpoolDir:   getEnv("UPLOAD_SPOOL_DIR", "uploads/spool"),
+		DocumentScanner:  getEnv("DOCUMENT_SCANNER", "none"),
+		ClamAVAddress:    getEnv("CLAMAV_ADDRESS", "localhost:3310"),
+		RejectAction:     getEnv("DOCUMENT_REJECT_ACTION", "reject"),
+		QuarantineDir:    getEnv("QUARANTINE_DIR", "uploads/quarantine"),
+		PublicVerifyURL:  getEnv("PUBLIC_VERIFY_URL", "http://localhost:8080/api/certificates/verify/"),
+		PublicAPIURL:     strings.TrimSuffix(getEnv("PUBLIC_API_URL", "http://localhost:8080/api"), "/"),
+		InstitutionName:  getEnv("INSTITUTION_NAME", "SSN College of Engineering"),
+		InstitutionDID:   getEnv("INSTITUTION_DID", "did:web:localhost%3A8080"),
+		VCSigningKey:     getEnv("VC_SIGNING_KEY", ""),
+		SignPDFs:         getEnvBool("SIGN_PDFS", true),
+		PDFSigningCert:   getEnv("PDF_SIGNING_CERT", ""),
+		PDFSigningKey:    getEnv("PDF_SIGNING_KEY", ""),
+		TimestampURL:     getEnv("TSA_URL", ""),
+		DuplicatePolicy:  getEnv("DUPLICATE_POLICY", "reject"),
+		IdempotencyHours: getEnvInt("IDEMPOTENCY_HOURS", 24),
+		TokenSigningKey:  getEnv("TOKEN_SIGNING_KEY", ""),
+		TokenTTLHours:    getEnvInt("TOKEN_TTL_HOURS", 24),
+		KeystorePassword: getEnv("KEYSTORE_PASSWORD", ""),
+		KeystoreDir:      getEnv("KEYSTORE_DIR", "keys"),
+		PKCS11Module:     getEnv("PKCS11_MODULE", ""),
+		PKCS11PIN:        getEnv("PKCS11_PIN", ""),
 	}
 	return cfg
 }
@@ -44,6 +143,24 @@ func getEnv(key, def string) string {
 	return def
 }
 
+func getEnvInt(key string, def int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func getEnvBool(key string, def bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
 func parseAllowedOrigins(origins string) []string {
 	if origins == "" {
 		return []string{"*"}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type AccessRequestHandler struct {
+	Requests *services.AccessRequestService
+}
+
+// Create lets an external verifier ask a student for access to their certificates
+func (h *AccessRequestHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.CreateAccessRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	created, err := h.Requests.Create(user, req, clientIP(r))
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusCreated, true, "access request submitted", created)
+}
+
+// List shows received requests to students and sent requests to verifiers
+func (h *AccessRequestHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	requests, err := h.Requests.List(user)
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "access requests retrieved", requests)
+}
+
+// Decide records the student's approval (with scope and expiry) or denial
+func (h *AccessRequestHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.AccessDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	decided, err := h.Requests.Decide(mux.Vars(r)["id"], user, req, clientIP(r))
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, fmt.Sprintf("access request %s", decided.Status), decided)
+}
+
+// Revoke withdraws the student's consent
+func (h *AccessRequestHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	revoked, err := h.Requests.Revoke(mux.Vars(r)["id"], user, clientIP(r))
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "access revoked", revoked)
+}
+
+// Certificates returns the full certificate details covered by an approved request
+func (h *AccessRequestHandler) Certificates(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	granted, err := h.Requests.Certificates(mux.Vars(r)["id"], user, clientIP(r))
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "certificates retrieved", granted)
+}
+
+// Document streams the stored file of a certificate covered by an approved request
+func (h *AccessRequestHandler) Document(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	vars := mux.Vars(r)
+
+	doc, err := h.Requests.Document(r.Context(), vars["id"], vars["cert_id"], user, clientIP(r))
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	serveDocument(w, r, doc)
+}
+
+// AuditTrail lists every recorded action on a request
+func (h *AccessRequestHandler) AuditTrail(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	entries, err := h.Requests.AuditTrail(mux.Vars(r)["id"], user)
+	if err != nil {
+		httpx.JSON(w, accessErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "audit trail retrieved", entries)
+}
+
+func accessErrorStatus(err error) int {
+	if status := upstreamErrorStatus(err); status != 0 {
+		return status
+	}
+	msg := err.Error()
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "already"), strings.Contains(msg, "only approved"):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrDocumentIntegrity), strings.Contains(msg, "IPFS"):
+		return http.StatusBadGateway
+	case strings.Contains(msg, "required"), strings.Contains(msg, "invalid"), strings.Contains(msg, "was not requested"),
+		strings.Contains(msg, "outside the granted"), strings.Contains(msg, "must be between"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
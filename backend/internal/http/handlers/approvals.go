@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type ApprovalHandler struct {
+	Approvals *services.ApprovalService
+}
+
+func (h *ApprovalHandler) CreateDraft(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.CreateDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	draft, err := h.Approvals.CreateDraft(r.Context(), req, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusCreated, true, "draft created", draft)
+}
+
+func (h *ApprovalHandler) ListMyDrafts(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	drafts, err := h.Approvals.ListMyDrafts(user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve drafts", nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "drafts retrieved", drafts)
+}
+
+func (h *ApprovalHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	draft, err := h.Approvals.GetDraft(mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "draft retrieved", draft)
+}
+
+func (h *ApprovalHandler) SubmitDraft(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	draft, err := h.Approvals.SubmitDraft(r.Context(), mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "draft submitted", draft)
+}
+
+func (h *ApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.ApprovalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	draft, err := h.Approvals.Approve(r.Context(), mux.Vars(r)["id"], user.ID.Hex(), req.Comment)
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	message := "approval recorded"
+	if draft.Status == models.CertStatusIssued {
+		message = "all approvals received, certificate issued"
+	}
+	httpx.JSON(w, http.StatusOK, true, message, draft)
+}
+
+func (h *ApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.ApprovalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	draft, err := h.Approvals.Reject(mux.Vars(r)["id"], user.ID.Hex(), req.Comment)
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "draft rejected", draft)
+}
+
+func (h *ApprovalHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	drafts, err := h.Approvals.Inbox(user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve approvals inbox", nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "approvals inbox retrieved", drafts)
+}
+
+func (h *ApprovalHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.Approvals.ListPolicies()
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve approval policies", nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "approval policies retrieved", policies)
+}
+
+func (h *ApprovalHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req struct {
+		RequiredRoles []models.UserRole `json:"required_roles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	certType := models.CredentialType(mux.Vars(r)["cert_type"])
+	policy, err := h.Approvals.SetPolicy(certType, req.RequiredRoles, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "approval policy updated", policy)
+}
+
+// draftErrorStatus maps approval workflow errors to HTTP status codes
+func draftErrorStatus(err error) int {
+	if errors.Is(err, services.ErrPermissionDenied) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, services.ErrNotEligible) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, services.ErrDuplicateCertificate) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, services.ErrDraftConflict) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, services.ErrDocumentRejected) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, services.ErrUnsupportedDocument) {
+		return http.StatusUnsupportedMediaType
+	}
+	if status := upstreamErrorStatus(err); status != 0 {
+		return status
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type BadgeHandler struct {
+	Badges *services.BadgeService
+}
+
+// ExportBadge downloads a participation certificate as an OpenBadgeCredential; ?format=jwt returns a vc+jwt
+func (h *BadgeHandler) ExportBadge(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	certID := mux.Vars(r)["cert_id"]
+
+	if r.URL.Query().Get("format") == "jwt" {
+		token, err := h.Badges.BadgeJWT(certID, user)
+		if err != nil {
+			httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vc+jwt")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-badge.jwt\"", certID))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(token))
+		return
+	}
+
+	badge, err := h.Badges.BadgeCredential(certID, user)
+	if err != nil {
+		httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vc+ld+json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-badge.json\"", certID))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(badge)
+}
+
+// DownloadBakedBadge returns the badge image with the signed credential embedded (?format=png|svg)
+func (h *BadgeHandler) DownloadBakedBadge(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	certID := mux.Vars(r)["cert_id"]
+	format := badgeImageFormat(r)
+
+	img, contentType, err := h.Badges.BakedBadge(certID, user, format)
+	if err != nil {
+		httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-badge.%s\"", certID, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(img)
+}
+
+// IssuerProfile serves the public Open Badges issuer profile
+func (h *BadgeHandler) IssuerProfile(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.Badges.IssuerProfile())
+}
+
+func (h *BadgeHandler) ListAchievements(w http.ResponseWriter, r *http.Request) {
+	achievements, err := h.Badges.ListAchievements(r.URL.Query().Get("club"))
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "achievements retrieved", achievements)
+}
+
+// GetAchievement serves the public achievement definition referenced from issued badges
+func (h *BadgeHandler) GetAchievement(w http.ResponseWriter, r *http.Request) {
+	achievement, err := h.Badges.Achievement(mux.Vars(r)["id"])
+	if err != nil {
+		httpx.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(achievement)
+}
+
+// GetAchievementImage serves the unbaked badge artwork (?format=png|svg)
+func (h *BadgeHandler) GetAchievementImage(w http.ResponseWriter, r *http.Request) {
+	img, contentType, err := h.Badges.AchievementImage(mux.Vars(r)["id"], badgeImageFormat(r))
+	if err != nil {
+		httpx.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(img)
+}
+
+func badgeImageFormat(r *http.Request) string {
+	if r.URL.Query().Get("format") == "png" {
+		return "png"
+	}
+	return "svg"
+}
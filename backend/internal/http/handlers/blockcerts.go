@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type BlockcertsHandler struct {
+	Blockcerts *services.BlockcertsService
+}
+
+// CreateBatch anchors the listed certificates as one Blockcerts Merkle batch
+func (h *BlockcertsHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.CreateBlockcertsBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	batch, err := h.Blockcerts.CreateBatch(r.Context(), req.CertIDs, userID)
+	if err != nil {
+		status := vcErrorStatus(err)
+		if strings.Contains(err.Error(), "cert_ids") || strings.Contains(err.Error(), "at most") {
+			status = http.StatusBadRequest
+		}
+		if errors.Is(err, services.ErrOnChainUnsupported) {
+			status = http.StatusNotImplemented
+		}
+		httpx.JSON(w, status, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusCreated, true, "blockcerts batch anchored", batch)
+}
+
+// ExportDocument downloads a certificate as a Blockcerts v3 document
+func (h *BlockcertsHandler) ExportDocument(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	certID := mux.Vars(r)["cert_id"]
+
+	doc, err := h.Blockcerts.Document(certID, user)
+	if err != nil {
+		httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-blockcerts.json\"", certID))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(doc)
+}
+
+// IssuerProfile serves the public Blockcerts issuer profile referenced by exported documents
+func (h *BlockcertsHandler) IssuerProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.Blockcerts.IssuerProfile()
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(profile)
+}
+
+// Verify checks a Blockcerts document posted as the request body
+func (h *BlockcertsHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	result, err := h.Blockcerts.Verify(body)
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+	if !result.Valid {
+		httpx.JSON(w, http.StatusOK, false, "blockcerts verification failed", result)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "blockcerts document verified", result)
+}
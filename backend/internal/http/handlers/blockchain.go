@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 
 	httpx "blockcred-backend/internal/http"
@@ -10,6 +11,7 @@ import (
 
 type BlockchainHandler struct {
 	Blockchain services.BlockchainServiceInterface
+	DIDs       *services.DIDService
 }
 
 type RegisterIssuerRequest struct {
@@ -56,12 +58,22 @@ func (h *BlockchainHandler) RegisterIssuer(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	httpx.JSON(w, http.StatusOK, true, "issuer registered successfully", map[string]interface{}{
+	data := map[string]interface{}{
 		"issuer_address": req.IssuerAddress,
 		"name":           req.Name,
 		"role":           req.Role,
 		"institution":    req.Institution,
-	})
+	}
+	if h.DIDs != nil {
+		userID, _ := r.Context().Value("user_id").(string)
+		if issuer, err := h.DIDs.RecordIssuer(req.IssuerAddress, req.Name, req.Role, req.Institution, userID); err != nil {
+			log.Printf("⚠️  Issuer %s registered on-chain but DID mapping failed: %v", req.IssuerAddress, err)
+		} else {
+			data["did"] = issuer.DID
+		}
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "issuer registered successfully", data)
 }
 
 func (h *BlockchainHandler) GetBlockchainStatus(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type BulkHandler struct {
+	Bulk           *services.BulkIssuanceService
+	MaxUploadBytes int64
+}
+
+// Upload accepts multipart fields "manifest" (CSV/XLSX), "documents" (ZIP) and optional "dry_run"
+func (h *BulkHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxUploadBytes)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid multipart upload: "+err.Error(), nil)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	manifestName, manifest, err := readFormFile(r, "manifest")
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+	_, archive, err := readFormFile(r, "documents")
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+	if dryRun {
+		job, err := h.Bulk.Validate(manifestName, manifest, archive, user.ID.Hex())
+		if err != nil {
+			httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+			return
+		}
+		httpx.JSON(w, http.StatusOK, true, "dry run completed", job)
+		return
+	}
+
+	job, err := h.Bulk.Start(manifestName, manifest, archive, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusAccepted, true, "bulk issuance started", job)
+}
+
+func (h *BulkHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	jobs, err := h.Bulk.ListJobs(user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve bulk jobs", nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "bulk jobs retrieved", jobs)
+}
+
+func (h *BulkHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	job, err := h.Bulk.GetJob(mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "bulk job retrieved", job)
+}
+
+func (h *BulkHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	job, err := h.Bulk.Resume(mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusAccepted, true, "bulk issuance resumed", job)
+}
+
+// DownloadResult streams the per-row outcome as a CSV attachment
+func (h *BulkHandler) DownloadResult(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	job, err := h.Bulk.GetJob(mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	data, err := h.Bulk.ResultCSV(job)
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="bulk-%s-result.csv"`, job.ID.Hex()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func readFormFile(r *http.Request, field string) (string, []byte, error) {
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s file is required", field)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s file", field)
+	}
+	return header.Filename, data, nil
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"os"
 
 	httpx "blockcred-backend/internal/http"
 	"blockcred-backend/internal/models"
@@ -13,13 +19,31 @@ import (
 
 type CertificateHandler struct {
 	Certificates *services.CertificateService
+	Approvals    *services.ApprovalService
+	Uploads      *services.UploadReceiver
 }
 
+// IssueCertificate accepts either JSON with base64 file_data, or multipart/form-data with a
+// "metadata" part holding the same JSON without file_data and a "file" part holding the document
 func (h *CertificateHandler) IssueCertificate(w http.ResponseWriter, r *http.Request) {
 	var req models.IssueCertificateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
-		return
+	var upload *services.Upload
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		var err error
+		if req, upload, err = h.receiveUpload(w, r); err != nil {
+			httpx.JSON(w, uploadErrorStatus(err), false, err.Error(), nil)
+			return
+		}
+		defer upload.Remove()
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+			return
+		}
+		if err := h.Uploads.Check(req.FileData); err != nil {
+			httpx.JSON(w, uploadErrorStatus(err), false, err.Error(), nil)
+			return
+		}
 	}
 
 	// Get user from context (set by auth middleware)
@@ -31,21 +55,103 @@ func (h *CertificateHandler) IssueCertificate(w http.ResponseWriter, r *http.Req
 	
 	issuerID := user.ID.Hex()
 
-	certificate, err := h.Certificates.IssueCertificate(req, issuerID)
+	// Credential types under an approval policy are staged as drafts instead
+	if h.Approvals != nil && h.Approvals.RequiresApproval(req.CertType) {
+		// Drafts keep their document until a checker approves it
+		if upload != nil {
+			var err error
+			if req.FileData, err = upload.ReadAll(); err != nil {
+				httpx.JSON(w, http.StatusInternalServerError, false, "failed to read uploaded file", nil)
+				return
+			}
+			if req.FileName == "" {
+				req.FileName = upload.FileName
+			}
+		}
+		draft, err := h.Approvals.CreateDraft(r.Context(), models.CreateDraftRequest{IssueCertificateRequest: req, Submit: true}, issuerID)
+		if err != nil {
+			httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+			return
+		}
+		httpx.JSON(w, http.StatusAccepted, true, "certificate submitted for approval", draft)
+		return
+	}
+
+	var certificate *models.Certificate
+	var err error
+	if upload != nil {
+		certificate, err = h.Certificates.IssueCertificateUpload(r.Context(), req, upload, issuerID)
+	} else {
+		certificate, err = h.Certificates.IssueCertificate(r.Context(), req, issuerID)
+	}
 	if err != nil {
-		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
 		return
 	}
 
-	httpx.JSON(w, http.StatusCreated, true, "certificate issued successfully", map[string]interface{}{
+	message := "certificate issued successfully"
+	if len(certificate.DuplicateOf) > 0 {
+		message = "certificate issued, but it repeats an existing certificate"
+	}
+	httpx.JSON(w, http.StatusCreated, true, message, map[string]interface{}{
 		"certificate_id": certificate.ID,
 		"cert_id":        certificate.CertID,
 		"ipfs_url":       certificate.IPFSURL,
 		"tx_hash":        certificate.TxHash,
 		"block_number":   certificate.BlockNumber,
+		"duplicate_of":   certificate.DuplicateOf,
 	})
 }
 
+// receiveUpload reads the parts of a multipart issuance request in order, spooling the file as it streams in
+func (h *CertificateHandler) receiveUpload(w http.ResponseWriter, r *http.Request) (req models.IssueCertificateRequest, upload *services.Upload, err error) {
+	// Leave room for the metadata part and multipart framing on top of the document itself
+	r.Body = http.MaxBytesReader(w, r.Body, h.Uploads.MaxBytes()+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return req, nil, fmt.Errorf("invalid multipart upload: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			upload.Remove()
+		}
+	}()
+
+	hasMetadata := false
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return req, upload, fmt.Errorf("invalid multipart upload: %w", err)
+		}
+		switch part.FormName() {
+		case "metadata":
+			if err := json.NewDecoder(part).Decode(&req); err != nil {
+				return req, upload, fmt.Errorf("invalid metadata part: %w", err)
+			}
+			hasMetadata = true
+		case "file":
+			if upload != nil {
+				return req, upload, fmt.Errorf("only one file part is allowed")
+			}
+			if upload, err = h.Uploads.Receive(part, part.FileName()); err != nil {
+				return req, nil, err
+			}
+		}
+		part.Close()
+	}
+
+	if !hasMetadata {
+		return req, upload, fmt.Errorf("metadata part is required")
+	}
+	if upload == nil {
+		return req, nil, fmt.Errorf("file part is required")
+	}
+	return req, upload, nil
+}
+
 func (h *CertificateHandler) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	certID, ok := vars["cert_id"]
@@ -59,11 +165,18 @@ func (h *CertificateHandler) VerifyCertificate(w http.ResponseWriter, r *http.Re
 		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
 		return
 	}
+	if user, ok := r.Context().Value("user").(models.User); ok {
+		h.Certificates.DiscloseSuspension(result, user)
+	}
 
 	httpx.JSON(w, http.StatusOK, true, "verification completed", result)
 }
 
 func (h *CertificateHandler) ListCertificates(w http.ResponseWriter, r *http.Request) {
+	if rejectExternalVerifier(w, r) {
+		return
+	}
+
 	certificates, err := h.Certificates.ListCertificates()
 	if err != nil {
 		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve certificates", nil)
@@ -74,6 +187,10 @@ func (h *CertificateHandler) ListCertificates(w http.ResponseWriter, r *http.Req
 }
 
 func (h *CertificateHandler) ListCertificatesByStudent(w http.ResponseWriter, r *http.Request) {
+	if rejectExternalVerifier(w, r) {
+		return
+	}
+
 	vars := mux.Vars(r)
 	studentID, ok := vars["student_id"]
 	if !ok {
@@ -132,6 +249,234 @@ func (h *CertificateHandler) RevokeCertificate(w http.ResponseWriter, r *http.Re
 	httpx.JSON(w, http.StatusOK, true, "certificate revoked successfully", nil)
 }
 
+func (h *CertificateHandler) ReissueCertificate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	certID, ok := vars["cert_id"]
+	if !ok {
+		httpx.JSON(w, http.StatusBadRequest, false, "certificate ID required", nil)
+		return
+	}
+
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.ReissueCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	certificate, err := h.Certificates.ReissueCertificate(r.Context(), certID, req, user.ID.Hex())
+	if errors.Is(err, services.ErrApprovalRequired) && h.Approvals != nil {
+		draft, err := h.Approvals.CreateReissueDraft(r.Context(), certID, req, user.ID.Hex())
+		if err != nil {
+			httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+			return
+		}
+		httpx.JSON(w, http.StatusAccepted, true, "reissue submitted for approval", draft)
+		return
+	}
+	if errors.Is(err, services.ErrSupersedePending) {
+		// The new version exists but is not active yet; retrying completes it rather than issuing again
+		httpx.JSON(w, http.StatusAccepted, false, err.Error(), certificate)
+		return
+	}
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusCreated, true, "certificate reissued successfully", map[string]interface{}{
+		"certificate_id": certificate.ID,
+		"cert_id":        certificate.CertID,
+		"supersedes":     certificate.Supersedes,
+		"ipfs_url":       certificate.IPFSURL,
+		"tx_hash":        certificate.TxHash,
+		"block_number":   certificate.BlockNumber,
+	})
+}
+
+func (h *CertificateHandler) SuspendCertificate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	certID, ok := vars["cert_id"]
+	if !ok {
+		httpx.JSON(w, http.StatusBadRequest, false, "certificate ID required", nil)
+		return
+	}
+
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.SuspendCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	certificate, err := h.Certificates.SuspendCertificate(r.Context(), certID, user.ID.Hex(), req)
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "certificate suspended successfully", certificate)
+}
+
+func (h *CertificateHandler) ReinstateCertificate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	certID, ok := vars["cert_id"]
+	if !ok {
+		httpx.JSON(w, http.StatusBadRequest, false, "certificate ID required", nil)
+		return
+	}
+
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	certificate, err := h.Certificates.ReinstateCertificate(r.Context(), certID, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "certificate reinstated successfully", certificate)
+}
+
+// DownloadDocument proxies the certificate file to users allowed to see the certificate, so clients
+// never depend on a public gateway. The file is checked against the anchored hash before it is sent.
+func (h *CertificateHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	doc, err := h.Certificates.Document(r.Context(), mux.Vars(r)["cert_id"], user)
+	if err != nil {
+		httpx.JSON(w, documentErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	serveDocument(w, r, doc)
+}
+
+// serveDocument writes a verified certificate file, honouring Range and conditional requests
+func serveDocument(w http.ResponseWriter, r *http.Request, doc *services.CertificateDocument) {
+	w.Header().Set("Content-Type", http.DetectContentType(doc.Data))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", doc.CertID))
+	w.Header().Set("X-Document-SHA256", doc.FileHash)
+	// The file behind a certificate never changes, but access to it can be withdrawn
+	w.Header().Set("ETag", fmt.Sprintf("\"%s\"", doc.FileHash))
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeContent(w, r, "", doc.IssuedAt, bytes.NewReader(doc.Data))
+}
+
+// PinStatus lists the providers holding the certificate file and when each was last checked
+func (h *CertificateHandler) PinStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	status, err := h.Certificates.PinStatus(mux.Vars(r)["cert_id"], user)
+	if err != nil {
+		httpx.JSON(w, documentErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "pin status retrieved", status)
+}
+
+// rejectExternalVerifier stops verifier accounts from browsing student records; they go through access requests
+func rejectExternalVerifier(w http.ResponseWriter, r *http.Request) bool {
+	if user, ok := r.Context().Value("user").(models.User); ok && user.Role == models.RoleExternalVerifier {
+		httpx.JSON(w, http.StatusForbidden, false, "external verifiers must request access through /api/access-requests", nil)
+		return true
+	}
+	return false
+}
+
+// certificateErrorStatus maps service errors to HTTP status codes
+func certificateErrorStatus(err error) int {
+	if errors.Is(err, services.ErrPermissionDenied) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, services.ErrApprovalRequired) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, services.ErrNotEligible) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, services.ErrDuplicateCertificate) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, services.ErrDocumentRejected) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, services.ErrUnsupportedDocument) {
+		return http.StatusUnsupportedMediaType
+	}
+	if errors.Is(err, services.ErrOnChainUnsupported) {
+		return http.StatusNotImplemented
+	}
+	if status := upstreamErrorStatus(err); status != 0 {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// uploadErrorStatus maps errors raised while receiving a certificate document
+func uploadErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	var pathErr *os.PathError
+	switch {
+	case errors.Is(err, services.ErrUploadTooLarge), errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrUnsupportedDocument):
+		return http.StatusUnsupportedMediaType
+	case errors.As(err, &pathErr):
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
+
+// documentErrorStatus maps document download errors to HTTP status codes
+func documentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrCertificateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrDocumentIntegrity):
+		return http.StatusBadGateway
+	}
+	if status := upstreamErrorStatus(err); status != 0 {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// upstreamErrorStatus maps failed calls to IPFS or the blockchain node, returning 0 for other errors
+func upstreamErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrUpstreamTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, services.ErrUpstreamFailed):
+		return http.StatusBadGateway
+	}
+	return 0
+}
+
 func (h *CertificateHandler) TestIPFS(w http.ResponseWriter, r *http.Request) {
 	// Test IPFS connection
 	if h.Certificates == nil {
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// ContentHandler serves documents from the filesystem content store, standing in for a gateway in
+// development, moves certificate files between providers and reports on their replication
+type ContentHandler struct {
+	Content    services.ContentStore
+	Migrations *services.ContentMigrationService
+	Pins       *services.PinTracker
+}
+
+func (h *ContentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	data, err := h.Content.Get(r.Context(), mux.Vars(r)["cid"])
+	if err != nil {
+		httpx.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Migrate re-pins every certificate file from another provider into the configured content store
+func (h *ContentHandler) Migrate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.ContentMigrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	report, err := h.Migrations.Migrate(req, user)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		httpx.JSON(w, status, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "content migration finished", report)
+}
+
+// PinHealth reports how well certificate files are replicated across providers
+func (h *ContentHandler) PinHealth(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	health, err := h.Pins.Health(user)
+	if err != nil {
+		httpx.JSON(w, documentErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "pin health retrieved", health)
+}
+
+// SweepPins starts an immediate pin check instead of waiting for the next scheduled one
+func (h *ContentHandler) SweepPins(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	if err := h.Pins.RequestSweep(user); err != nil {
+		httpx.JSON(w, documentErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusAccepted, true, "pin check started", nil)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type DIDHandler struct {
+	DIDs *services.DIDService
+}
+
+// WellKnownDID serves the institution's did:web document at /.well-known/did.json
+func (h *DIDHandler) WellKnownDID(w http.ResponseWriter, r *http.Request) {
+	writeDIDDocument(w, h.DIDs.InstitutionDocument())
+}
+
+// Resolve returns the DID document for the institution's did:web or an issuer's did:ethr
+func (h *DIDHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.DIDs.Resolve(mux.Vars(r)["did"])
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		httpx.JSON(w, status, false, err.Error(), nil)
+		return
+	}
+	writeDIDDocument(w, *doc)
+}
+
+func (h *DIDHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	httpx.JSON(w, http.StatusOK, true, "institution keys retrieved", h.DIDs.Keys())
+}
+
+func (h *DIDHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.RotateKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	key, err := h.DIDs.RotateKey(userID, req.Reason)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		} else if strings.Contains(err.Error(), "required") {
+			status = http.StatusBadRequest
+		}
+		httpx.JSON(w, status, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "institution key rotated", key)
+}
+
+func (h *DIDHandler) ListIssuers(w http.ResponseWriter, r *http.Request) {
+	issuers, err := h.DIDs.ListIssuers()
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "issuer DIDs retrieved", issuers)
+}
+
+func writeDIDDocument(w http.ResponseWriter, doc models.DIDDocument) {
+	w.Header().Set("Content-Type", "application/did+json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(doc)
+}
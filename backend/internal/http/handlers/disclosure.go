@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type DisclosureHandler struct {
+	Disclosures *services.SelectiveDisclosureService
+}
+
+// CreatePresentation lets the student reveal only the chosen certificate fields
+func (h *DisclosureHandler) CreatePresentation(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	certID := mux.Vars(r)["cert_id"]
+
+	var req models.CreatePresentationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	presentation, err := h.Disclosures.CreatePresentation(certID, user, req.Fields)
+	if err != nil {
+		status := vcErrorStatus(err)
+		if strings.Contains(err.Error(), "fields is required") || strings.Contains(err.Error(), "not disclosable") {
+			status = http.StatusBadRequest
+		}
+		httpx.JSON(w, status, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusCreated, true, "presentation created", presentation)
+}
+
+// Verify accepts {"presentation": "..."} or a raw application/sd-jwt body
+func (h *DisclosureHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req models.SDVerifyRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/sd-jwt") {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+			return
+		}
+		req.Presentation = string(body)
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	result, err := h.Disclosures.Verify(req.Presentation)
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+	if !result.Valid {
+		httpx.JSON(w, http.StatusOK, false, "presentation verification failed", result)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "presentation verified", result)
+}
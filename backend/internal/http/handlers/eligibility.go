@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type EligibilityHandler struct {
+	Eligibility *services.EligibilityService
+}
+
+func (h *EligibilityHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.Eligibility.ListRules()
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve eligibility rules", nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "eligibility rules retrieved", rules)
+}
+
+// SetRule configures the degree rule of a department, or the institution default when department is empty
+func (h *EligibilityHandler) SetRule(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.SetEligibilityRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	rule, err := h.Eligibility.SetRule(req, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "eligibility rule updated", rule)
+}
+
+// CheckDegree lists which degree conditions a student meets and which are outstanding
+func (h *EligibilityHandler) CheckDegree(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	result, err := h.Eligibility.Check(mux.Vars(r)["student_id"], user)
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "degree eligibility evaluated", result)
+}
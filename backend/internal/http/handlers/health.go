@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+)
+
+// DependencyHealth reports the circuit breakers guarding IPFS and the blockchain node. It answers 503
+// while any circuit is open, so load balancers and monitors can act on it.
+func DependencyHealth(w http.ResponseWriter, r *http.Request) {
+	statuses := services.DependencyStatuses()
+	for _, status := range statuses {
+		if status.State == models.CircuitOpen {
+			httpx.JSON(w, http.StatusServiceUnavailable, false, status.Name+" is unavailable", statuses)
+			return
+		}
+	}
+	httpx.JSON(w, http.StatusOK, true, "all dependencies available", statuses)
+}
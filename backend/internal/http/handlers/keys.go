@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type KeyHandler struct {
+	Keys *services.KeyManager
+}
+
+// Inventory lists every signing key by purpose, with where it is held and when it was rotated
+func (h *KeyHandler) Inventory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	keys, err := h.Keys.Inventory(userID)
+	if err != nil {
+		httpx.JSON(w, keyErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "signing keys retrieved", keys)
+}
+
+// Rotate replaces the active key of the purpose in the path
+func (h *KeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.RotateKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	key, err := h.Keys.Rotate(mux.Vars(r)["purpose"], userID, req.Reason)
+	if err != nil {
+		httpx.JSON(w, keyErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "signing key rotated", key)
+}
+
+func keyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrKeyNotManaged):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrRotationUnsupported):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
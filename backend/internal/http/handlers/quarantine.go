@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// QuarantineHandler lets administrators review and discard documents that failed pre-issuance checks
+type QuarantineHandler struct {
+	Documents *services.DocumentValidator
+}
+
+func (h *QuarantineHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	docs, err := h.Documents.ListQuarantine(user)
+	if err != nil {
+		httpx.JSON(w, quarantineErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "quarantined documents retrieved", docs)
+}
+
+func (h *QuarantineHandler) Discard(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	if err := h.Documents.DiscardQuarantined(mux.Vars(r)["id"], user); err != nil {
+		httpx.JSON(w, quarantineErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "quarantined document discarded", nil)
+}
+
+func quarantineErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid quarantined document ID"):
+		return http.StatusBadRequest
+	case err.Error() == "quarantined document not found":
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
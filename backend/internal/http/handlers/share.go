@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type ShareHandler struct {
+	Shares *services.ShareService
+}
+
+// CreateLink creates a share link for one or more of the student's certificates
+func (h *ShareHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	created, err := h.Shares.CreateLink(user, req)
+	if err != nil {
+		httpx.JSON(w, shareErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusCreated, true, "share link created", created)
+}
+
+// ListLinks lists the signed-in student's share links
+func (h *ShareHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	links, err := h.Shares.ListLinks(user)
+	if err != nil {
+		httpx.JSON(w, shareErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "share links retrieved", links)
+}
+
+// RevokeLink disables a share link
+func (h *ShareHandler) RevokeLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	link, err := h.Shares.RevokeLink(mux.Vars(r)["id"], user)
+	if err != nil {
+		httpx.JSON(w, shareErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "share link revoked", link)
+}
+
+// AccessLog shows who opened a share link and when
+func (h *ShareHandler) AccessLog(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	entries, err := h.Shares.AccessLog(mux.Vars(r)["id"], user)
+	if err != nil {
+		httpx.JSON(w, shareErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "access log retrieved", entries)
+}
+
+// Open serves the read-only verification view; the password, if any, is sent in X-Share-Password
+func (h *ShareHandler) Open(w http.ResponseWriter, r *http.Request) {
+	accessor := services.ShareAccessor{IP: clientIP(r), UserAgent: r.UserAgent()}
+	if user, ok := r.Context().Value("user").(models.User); ok {
+		accessor.Verifier = &user
+	}
+
+	view, err := h.Shares.Open(mux.Vars(r)["token"], r.Header.Get("X-Share-Password"), accessor)
+	if err != nil {
+		httpx.JSON(w, shareErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "shared certificates retrieved", view)
+}
+
+func shareErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrShareLinkGone):
+		return http.StatusGone
+	case errors.Is(err, services.ErrSharePassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "at most"), strings.Contains(err.Error(), "must be"), strings.Contains(err.Error(), "negative"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// clientIP returns the address resolved by the client IP middleware, falling back to the connection address
+func clientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value("client_ip").(string); ok && ip != "" {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
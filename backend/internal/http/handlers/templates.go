@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	Templates *services.TemplateService
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.CreateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	tpl, err := h.Templates.CreateTemplate(req, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusCreated, true, "template version created", tpl)
+}
+
+// ListTemplates supports optional ?institution= and ?cert_type= filters
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	templates, err := h.Templates.ListTemplates(q.Get("institution"), models.CredentialType(q.Get("cert_type")))
+	if err != nil {
+		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve templates", nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "templates retrieved", templates)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	tpl, err := h.Templates.GetTemplate(mux.Vars(r)["id"])
+	if err != nil {
+		httpx.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "template retrieved", tpl)
+}
+
+func (h *TemplateHandler) ActivateTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	tpl, err := h.Templates.ActivateTemplate(mux.Vars(r)["id"], user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, draftErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+
+	httpx.JSON(w, http.StatusOK, true, "template version activated", tpl)
+}
+
+// PreviewTemplate renders the template as a PDF with the posted sample data
+func (h *TemplateHandler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	var req models.PreviewTemplateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+			return
+		}
+	}
+
+	document, err := h.Templates.Preview(mux.Vars(r)["id"], req)
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "inline; filename=\"preview.pdf\"")
+	w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// GenerateCertificate renders a certificate from a template and issues it
+func (h *TemplateHandler) GenerateCertificate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.GenerateCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+	if req.StudentID == "" || req.CertType == "" {
+		httpx.JSON(w, http.StatusBadRequest, false, "student_id and cert_type are required", nil)
+		return
+	}
+
+	certificate, draft, err := h.Templates.GenerateCertificate(r.Context(), req, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, fmt.Sprintf("failed to generate certificate: %v", err), nil)
+		return
+	}
+	if draft != nil {
+		httpx.JSON(w, http.StatusAccepted, true, "certificate submitted for approval", draft)
+		return
+	}
+
+	httpx.JSON(w, http.StatusCreated, true, "certificate generated and issued", certificate)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type TranscriptHandler struct {
+	Transcripts *services.TranscriptService
+}
+
+// Generate issues a consolidated transcript from the student's semester marksheets
+func (h *TranscriptHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	var req models.GenerateTranscriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+	if req.StudentID == "" {
+		httpx.JSON(w, http.StatusBadRequest, false, "student_id is required", nil)
+		return
+	}
+
+	certificate, draft, err := h.Transcripts.Generate(r.Context(), req, user.ID.Hex())
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, fmt.Sprintf("failed to generate transcript: %v", err), nil)
+		return
+	}
+	if draft != nil {
+		httpx.JSON(w, http.StatusAccepted, true, "transcript submitted for approval", draft)
+		return
+	}
+
+	httpx.JSON(w, http.StatusCreated, true, "transcript generated and issued", certificate)
+}
+
+// Summary previews a student's transcript without issuing it
+func (h *TranscriptHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+
+	summary, err := h.Transcripts.Summary(mux.Vars(r)["student_id"], user)
+	if err != nil {
+		httpx.JSON(w, certificateErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "transcript summary computed", summary)
+}
+
+// Verify checks a transcript together with each marksheet it references
+func (h *TranscriptHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	result, err := h.Transcripts.Verify(mux.Vars(r)["cert_id"])
+	if err != nil {
+		httpx.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "transcript verification completed", result)
+}
@@ -18,6 +18,9 @@ type UserHandler struct {
 }
 
 func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	if rejectExternalVerifier(w, r) {
+		return
+	}
 	list, err := h.Users.List()
 	if err != nil {
 		httpx.JSON(w, http.StatusInternalServerError, false, "failed to retrieve users", nil)
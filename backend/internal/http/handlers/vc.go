@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+type VCHandler struct {
+	VC *services.VCService
+}
+
+// ExportCredential downloads a certificate as a W3C VC; ?format=jwt returns a vc+jwt instead of a Data Integrity proof
+func (h *VCHandler) ExportCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(models.User)
+	if !ok {
+		httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+		return
+	}
+	certID := mux.Vars(r)["cert_id"]
+
+	if r.URL.Query().Get("format") == "jwt" {
+		token, err := h.VC.CredentialJWT(certID, user)
+		if err != nil {
+			httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vc+jwt")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.jwt\"", certID))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(token))
+		return
+	}
+
+	vc, err := h.VC.SignedCredential(certID, user)
+	if err != nil {
+		httpx.JSON(w, vcErrorStatus(err), false, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vc+ld+json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.json\"", certID))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(vc)
+}
+
+// VerifyCredential accepts {"credential": {...}} or {"jwt": "..."}, or a raw vc+jwt body
+func (h *VCHandler) VerifyCredential(w http.ResponseWriter, r *http.Request) {
+	var req models.VCVerifyRequest
+	if ct := r.Header.Get("Content-Type"); strings.HasPrefix(ct, "application/vc+jwt") || strings.HasPrefix(ct, "application/jwt") {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+			return
+		}
+		req.JWT = strings.TrimSpace(string(body))
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, "invalid request", nil)
+		return
+	}
+
+	result, err := h.VC.Verify(req)
+	if err != nil {
+		httpx.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+
+	if !result.Valid {
+		httpx.JSON(w, http.StatusOK, false, "credential verification failed", result)
+		return
+	}
+	httpx.JSON(w, http.StatusOK, true, "credential verified", result)
+}
+
+func vcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrCredentialUnavailable):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -10,12 +10,18 @@ import (
 	"blockcred-backend/internal/store"
 )
 
+// TokenVerifier resolves a session token to the ID of the user it was issued to
+type TokenVerifier interface {
+	Verify(token string) (string, error)
+}
+
 type AuthMiddleware struct {
-	store store.Store
+	store  store.Store
+	tokens TokenVerifier
 }
 
-func NewAuthMiddleware(s store.Store) *AuthMiddleware {
-	return &AuthMiddleware{store: s}
+func NewAuthMiddleware(s store.Store, tokens TokenVerifier) *AuthMiddleware {
+	return &AuthMiddleware{store: s, tokens: tokens}
 }
 
 func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
@@ -34,8 +40,7 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		token := parts[1]
-		
-		// Simple token validation (in production, use proper JWT validation)
+
 		user, err := m.validateToken(token)
 		if err != nil {
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
@@ -45,22 +50,31 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 		// Add user to request context
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, "user", user)
+		ctx = context.WithValue(ctx, "user_id", user.ID.Hex())
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	}
 }
 
-func (m *AuthMiddleware) validateToken(token string) (models.User, error) {
-	// Simple token validation - in production, use proper JWT validation
-	if !strings.HasPrefix(token, "token-") {
-		return models.User{}, errors.New("invalid token format")
+// OptionalAuth attaches the signed-in user when a valid token is sent and lets anonymous requests through
+func (m *AuthMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != "" {
+			if user, err := m.validateToken(token); err == nil {
+				ctx := context.WithValue(r.Context(), "user", user)
+				ctx = context.WithValue(ctx, "user_id", user.ID.Hex())
+				r = r.WithContext(ctx)
+			}
+		}
+		next.ServeHTTP(w, r)
 	}
+}
 
-	// Extract user ID from token (now using ObjectID hex string)
-	userIDHex := strings.TrimPrefix(token, "token-")
-	if userIDHex == "" {
-		return models.User{}, errors.New("invalid token")
+func (m *AuthMiddleware) validateToken(token string) (models.User, error) {
+	userIDHex, err := m.tokens.Verify(token)
+	if err != nil {
+		return models.User{}, err
 	}
 
 	// Find user by ID
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIPMiddleware resolves the address of the client behind the configured reverse proxies and
+// stores it in the request context as "client_ip". X-Forwarded-For is only read when the request
+// arrives from a trusted proxy, so clients cannot choose the address recorded for them.
+type ClientIPMiddleware struct {
+	trusted []*net.IPNet
+}
+
+// NewClientIPMiddleware parses a comma-separated list of proxy IPs or CIDRs
+func NewClientIPMiddleware(trustedProxies string) (*ClientIPMiddleware, error) {
+	m := &ClientIPMiddleware{}
+	for _, entry := range strings.Split(trustedProxies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			m.trusted = append(m.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		m.trusted = append(m.trusted, network)
+	}
+	return m, nil
+}
+
+func (m *ClientIPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "client_ip", m.resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// resolve walks X-Forwarded-For from the nearest hop back while each hop is a trusted proxy;
+// the first address not in the list is the client
+func (m *ClientIPMiddleware) resolve(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	if !m.isTrusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !m.isTrusted(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (m *ClientIPMiddleware) isTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range m.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	httpx "blockcred-backend/internal/http"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware replays the stored response when a client retries a request with the same Idempotency-Key
+type IdempotencyMiddleware struct {
+	store    store.Store
+	ttl      time.Duration
+	maxBytes int64 // Largest request body spooled for hashing
+}
+
+func NewIdempotencyMiddleware(s store.Store, ttl time.Duration, maxBytes int64) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: s, ttl: ttl, maxBytes: maxBytes}
+}
+
+// Idempotent must run inside RequireAuth, since keys are scoped to the signed-in user.
+// Requests without the header are passed through unchanged.
+func (m *IdempotencyMiddleware) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			httpx.JSON(w, http.StatusBadRequest, false, fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength), nil)
+			return
+		}
+		userID, ok := r.Context().Value("user_id").(string)
+		if !ok {
+			httpx.JSON(w, http.StatusUnauthorized, false, "user not authenticated", nil)
+			return
+		}
+
+		// The body is spooled to disk while it is hashed, so large uploads are not held in memory
+		body, err := os.CreateTemp("", "idempotent-body-*")
+		if err != nil {
+			httpx.JSON(w, http.StatusInternalServerError, false, "failed to buffer request body", nil)
+			return
+		}
+		defer os.Remove(body.Name())
+		defer body.Close()
+		hasher := sha256.New()
+		hasher.Write([]byte(r.Method + "\n" + r.URL.Path + "\n"))
+		if _, err := io.Copy(io.MultiWriter(body, hasher), http.MaxBytesReader(w, r.Body, m.maxBytes)); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				httpx.JSON(w, http.StatusRequestEntityTooLarge, false, fmt.Sprintf("request body exceeds %d bytes", m.maxBytes), nil)
+				return
+			}
+			httpx.JSON(w, http.StatusBadRequest, false, "failed to read request body", nil)
+			return
+		}
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			httpx.JSON(w, http.StatusInternalServerError, false, "failed to buffer request body", nil)
+			return
+		}
+		r.Body = body
+		requestHash := hex.EncodeToString(hasher.Sum(nil))
+
+		if existing, err := m.store.GetIdempotencyRecord(userID, key); err == nil {
+			m.replay(w, existing, requestHash)
+			return
+		}
+
+		now := time.Now()
+		rec, err := m.store.CreateIdempotencyRecord(models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		})
+		if err != nil {
+			// Another request with the same key got there first
+			httpx.JSON(w, http.StatusConflict, false, "a request with this Idempotency-Key is already in progress", nil)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		// Server errors are not remembered, so the client can retry them with the same key
+		if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+			if err := m.store.DeleteIdempotencyRecord(rec.ID.Hex()); err != nil {
+				fmt.Printf("⚠️  Warning: Failed to release idempotency key: %v\n", err)
+			}
+			return
+		}
+		rec.Completed = true
+		rec.StatusCode = recorder.status
+		rec.ContentType = recorder.Header().Get("Content-Type")
+		rec.Body = recorder.body.Bytes()
+		if _, err := m.store.UpdateIdempotencyRecord(rec.ID.Hex(), rec); err != nil {
+			fmt.Printf("⚠️  Warning: Failed to store idempotent response: %v\n", err)
+		}
+	}
+}
+
+func (m *IdempotencyMiddleware) replay(w http.ResponseWriter, rec models.IdempotencyRecord, requestHash string) {
+	switch {
+	case rec.RequestHash != requestHash:
+		httpx.JSON(w, http.StatusUnprocessableEntity, false, "Idempotency-Key was already used for a different request", nil)
+	case !rec.Completed:
+		httpx.JSON(w, http.StatusConflict, false, "a request with this Idempotency-Key is already in progress", nil)
+	default:
+		if rec.ContentType != "" {
+			w.Header().Set("Content-Type", rec.ContentType)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(rec.StatusCode)
+		w.Write(rec.Body)
+	}
+}
+
+// responseRecorder passes the response through while keeping a copy for replay
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
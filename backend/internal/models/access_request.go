@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccessRequestStatus tracks a verifier's request through the student's consent decision
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending  AccessRequestStatus = "pending"
+	AccessRequestApproved AccessRequestStatus = "approved"
+	AccessRequestDenied   AccessRequestStatus = "denied"
+	AccessRequestRevoked  AccessRequestStatus = "revoked"
+)
+
+// AccessRequest asks a student for consent to view their certificates of the listed types
+type AccessRequest struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	VerifierID     string              `bson:"verifier_id" json:"verifier_id"`
+	VerifierName   string              `bson:"verifier_name" json:"verifier_name"`
+	VerifierEmail  string              `bson:"verifier_email" json:"verifier_email"`
+	StudentID      string              `bson:"student_id" json:"student_id"`
+	CertTypes      []CredentialType    `bson:"cert_types" json:"cert_types"`
+	Purpose        string              `bson:"purpose" json:"purpose"`
+	Status         AccessRequestStatus `bson:"status" json:"status"`
+	GrantedTypes   []CredentialType    `bson:"granted_types,omitempty" json:"granted_types,omitempty"`       // Scope approved by the student
+	GrantedCertIDs []string            `bson:"granted_cert_ids,omitempty" json:"granted_cert_ids,omitempty"` // Optional narrowing to specific certificates
+	ExpiresAt      *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	DecisionNote   string              `bson:"decision_note,omitempty" json:"decision_note,omitempty"`
+	DecidedAt      *time.Time          `bson:"decided_at,omitempty" json:"decided_at,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// AccessAuditAction names an event in an access request's audit trail
+type AccessAuditAction string
+
+const (
+	AuditAccessRequested  AccessAuditAction = "requested"
+	AuditAccessApproved   AccessAuditAction = "approved"
+	AuditAccessDenied     AccessAuditAction = "denied"
+	AuditAccessRevoked    AccessAuditAction = "revoked"
+	AuditAccessViewed     AccessAuditAction = "viewed_certificates"
+	AuditAccessDownloaded AccessAuditAction = "downloaded_document"
+	AuditAccessRefused    AccessAuditAction = "access_refused"
+)
+
+// AccessAuditEntry records one action taken on an access request
+type AccessAuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RequestID string             `bson:"request_id" json:"request_id"`
+	ActorID   string             `bson:"actor_id" json:"actor_id"`
+	ActorRole UserRole           `bson:"actor_role" json:"actor_role"`
+	Action    AccessAuditAction  `bson:"action" json:"action"`
+	CertIDs   []string           `bson:"cert_ids,omitempty" json:"cert_ids,omitempty"`
+	Detail    string             `bson:"detail,omitempty" json:"detail,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	At        time.Time          `bson:"at" json:"at"`
+}
+
+// CreateAccessRequestRequest represents a verifier's request for a student's certificates
+type CreateAccessRequestRequest struct {
+	StudentID string           `json:"student_id"`
+	CertTypes []CredentialType `json:"cert_types"`
+	Purpose   string           `json:"purpose"`
+}
+
+// AccessDecisionRequest represents the student's answer to an access request
+type AccessDecisionRequest struct {
+	Approve       bool             `json:"approve"`
+	CertTypes     []CredentialType `json:"cert_types,omitempty"` // Defaults to every requested type
+	CertIDs       []string         `json:"cert_ids,omitempty"`
+	ExpiresInDays int              `json:"expires_in_days,omitempty"`
+	Note          string           `json:"note,omitempty"`
+}
+
+// GrantedCertificates is what a verifier receives under an approved access request
+type GrantedCertificates struct {
+	Request      AccessRequest `json:"request"`
+	Certificates []Certificate `json:"certificates"`
+}
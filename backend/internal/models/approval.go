@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApprovalPolicy lists the roles that must sign off before a credential type is issued
+type ApprovalPolicy struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CertType      CredentialType     `bson:"cert_type" json:"cert_type"`
+	RequiredRoles []UserRole         `bson:"required_roles" json:"required_roles"` // One approval per role, in any order
+	UpdatedBy     string             `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ApprovalDecisionType is the outcome recorded by a checker
+type ApprovalDecisionType string
+
+const (
+	DecisionApproved ApprovalDecisionType = "approved"
+	DecisionRejected ApprovalDecisionType = "rejected"
+)
+
+// ApprovalDecision is a single checker's sign-off or rejection of a draft
+type ApprovalDecision struct {
+	ApproverID   string               `bson:"approver_id" json:"approver_id"`
+	ApproverName string               `bson:"approver_name" json:"approver_name"`
+	Role         UserRole             `bson:"role" json:"role"`
+	Decision     ApprovalDecisionType `bson:"decision" json:"decision"`
+	Comment      string               `bson:"comment,omitempty" json:"comment,omitempty"`
+	DecidedAt    time.Time            `bson:"decided_at" json:"decided_at"`
+}
+
+// CertificateDraft holds an issuance request until its approval policy is satisfied.
+// Nothing is uploaded to IPFS or anchored on-chain while a draft is pending.
+type CertificateDraft struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	StudentID     string              `bson:"student_id" json:"student_id"`
+	CertType      CredentialType      `bson:"cert_type" json:"cert_type"`
+	FileData      []byte              `bson:"file_data" json:"-"`
+	FileName      string              `bson:"file_name" json:"file_name"`
+	FileHash      string              `bson:"file_hash" json:"file_hash"` // SHA-256 of FileData, shown to checkers
+	Metadata      CertificateMetadata `bson:"metadata" json:"metadata"`
+	MakerID       string              `bson:"maker_id" json:"maker_id"`
+	Status        CertificateStatus   `bson:"status" json:"status"` // draft, pending_approval, approved, rejected, issued
+	RequiredRoles []UserRole          `bson:"required_roles" json:"required_roles"`
+	Decisions     []ApprovalDecision  `bson:"decisions" json:"decisions"`
+	Supersedes    string              `bson:"supersedes,omitempty" json:"supersedes,omitempty"` // Set when the draft reissues a certificate
+	ReissueReason string              `bson:"reissue_reason,omitempty" json:"reissue_reason,omitempty"`
+	CertID        string              `bson:"cert_id,omitempty" json:"cert_id,omitempty"`     // Set once issued, or reserved up front for generated documents
+	IssuedAt      *time.Time          `bson:"issued_at,omitempty" json:"issued_at,omitempty"` // Issuance time the reserved cert ID was derived from
+	SubmittedAt   *time.Time          `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// HasApprovalFor reports whether a role's sign-off has already been recorded
+func (d *CertificateDraft) HasApprovalFor(role UserRole) bool {
+	for _, decision := range d.Decisions {
+		if decision.Role == role && decision.Decision == DecisionApproved {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingRoles returns the required roles that have not yet approved
+func (d *CertificateDraft) PendingRoles() []UserRole {
+	pending := []UserRole{}
+	for _, role := range d.RequiredRoles {
+		if !d.HasApprovalFor(role) {
+			pending = append(pending, role)
+		}
+	}
+	return pending
+}
+
+// CreateDraftRequest represents the request to stage a certificate for approval
+type CreateDraftRequest struct {
+	IssueCertificateRequest
+	Submit bool `json:"submit"` // Submit for approval immediately instead of keeping as draft
+}
+
+// ApprovalDecisionRequest carries a checker's comment
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BadgeAchievement is the Open Badges achievement definition shared by every participant of one club event
+type BadgeAchievement struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClubName    string             `bson:"club_name" json:"club_name"`
+	EventName   string             `bson:"event_name" json:"event_name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Criteria    string             `bson:"criteria" json:"criteria"`
+	CreatedBy   string             `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// OpenBadgeCredential is an Open Badges 3.0 achievement credential
+type OpenBadgeCredential struct {
+	Context           []string             `json:"@context"`
+	ID                string               `json:"id"`
+	Type              []string             `json:"type"`
+	Name              string               `json:"name"`
+	Issuer            OBProfile            `json:"issuer"`
+	ValidFrom         string               `json:"validFrom"`
+	ValidUntil        string               `json:"validUntil,omitempty"`
+	CredentialSubject OBAchievementSubject `json:"credentialSubject"`
+	CredentialStatus  *VCStatus            `json:"credentialStatus,omitempty"`
+	Evidence          []VCEvidence         `json:"evidence,omitempty"`
+	Proof             *DataIntegrityProof  `json:"proof,omitempty"`
+}
+
+// OBProfile describes the issuing institution
+type OBProfile struct {
+	ID          string   `json:"id"`
+	Type        []string `json:"type"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// OBAchievementSubject is the student who earned the achievement
+type OBAchievementSubject struct {
+	ID          string             `json:"id,omitempty"`
+	Type        []string           `json:"type"`
+	Identifier  []OBIdentityObject `json:"identifier,omitempty"`
+	Achievement OBAchievement      `json:"achievement"`
+}
+
+// OBIdentityObject identifies the recipient by a salted hash of their email
+type OBIdentityObject struct {
+	Type         string `json:"type"`
+	IdentityHash string `json:"identityHash"`
+	IdentityType string `json:"identityType"`
+	Hashed       bool   `json:"hashed"`
+	Salt         string `json:"salt,omitempty"`
+}
+
+// OBAchievement is the public achievement definition
+type OBAchievement struct {
+	ID              string     `json:"id"`
+	Type            []string   `json:"type"`
+	AchievementType string     `json:"achievementType,omitempty"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Criteria        OBCriteria `json:"criteria"`
+	Image           *OBImage   `json:"image,omitempty"`
+	Creator         *OBProfile `json:"creator,omitempty"`
+	Tag             []string   `json:"tag,omitempty"`
+}
+
+// OBCriteria describes how the achievement is earned
+type OBCriteria struct {
+	Narrative string `json:"narrative"`
+}
+
+// OBImage references the badge artwork
+type OBImage struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
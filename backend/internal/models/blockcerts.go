@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlockcertsBatch is a set of certificate documents whose Merkle root was anchored in one transaction
+type BlockcertsBatch struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MerkleRoot    string             `bson:"merkle_root" json:"merkle_root"`
+	TxHash        string             `bson:"tx_hash" json:"tx_hash"`
+	BlockNumber   uint64             `bson:"block_number" json:"block_number"`
+	Anchor        string             `bson:"anchor" json:"anchor"` // blink URI, e.g. blink:eth:1337:0x...
+	IssuerAddress string             `bson:"issuer_address" json:"issuer_address"`
+	Entries       []BlockcertsEntry  `bson:"entries" json:"entries"`
+	CreatedBy     string             `bson:"created_by" json:"created_by"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// BlockcertsEntry is one leaf of a batch. The unsigned document is kept verbatim because the
+// target hash commits to its exact content.
+type BlockcertsEntry struct {
+	CertID     string `bson:"cert_id" json:"cert_id"`
+	TargetHash string `bson:"target_hash" json:"target_hash"`
+	Document   string `bson:"document" json:"-"`
+}
+
+// BlockcertsDocument is a Blockcerts v3 certificate
+type BlockcertsDocument struct {
+	Context           []string         `json:"@context"`
+	ID                string           `json:"id"`
+	Type              []string         `json:"type"`
+	Issuer            string           `json:"issuer"` // URL of the Blockcerts issuer profile
+	IssuanceDate      string           `json:"issuanceDate"`
+	ExpirationDate    string           `json:"expirationDate,omitempty"`
+	CredentialSubject VCSubject        `json:"credentialSubject"`
+	CredentialStatus  *VCStatus        `json:"credentialStatus,omitempty"`
+	Evidence          []VCEvidence     `json:"evidence,omitempty"`
+	Proof             *BlockcertsProof `json:"proof,omitempty"`
+}
+
+// BlockcertsProof is a MerkleProof2019 proof
+type BlockcertsProof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	ProofPurpose       string `json:"proofPurpose"`
+	VerificationMethod string `json:"verificationMethod"`
+	ProofValue         string `json:"proofValue"`
+}
+
+// BlockcertsIssuerProfile lists the accounts that anchor the institution's certificates
+type BlockcertsIssuerProfile struct {
+	Context   []string              `json:"@context"`
+	ID        string                `json:"id"`
+	Type      string                `json:"type"`
+	Name      string                `json:"name"`
+	DID       string                `json:"did,omitempty"`
+	PublicKey []BlockcertsPublicKey `json:"publicKey"`
+}
+
+// BlockcertsPublicKey is an anchoring account, e.g. ecdsa-koblitz-pubkey:0xAbC...
+type BlockcertsPublicKey struct {
+	ID      string `json:"id"`
+	Created string `json:"created"`
+}
+
+// CreateBlockcertsBatchRequest represents the request to anchor certificates as a Blockcerts batch
+type CreateBlockcertsBatchRequest struct {
+	CertIDs []string `json:"cert_ids"`
+}
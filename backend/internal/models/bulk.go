@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BulkJobStatus represents the lifecycle of a bulk issuance job
+type BulkJobStatus string
+
+const (
+	BulkJobValidated          BulkJobStatus = "validated" // Dry run only, nothing issued
+	BulkJobQueued             BulkJobStatus = "queued"
+	BulkJobProcessing         BulkJobStatus = "processing"
+	BulkJobCompleted          BulkJobStatus = "completed"
+	BulkJobCompletedWithError BulkJobStatus = "completed_with_errors"
+)
+
+// BulkRowStatus represents the outcome of a single manifest row
+type BulkRowStatus string
+
+const (
+	BulkRowValid      BulkRowStatus = "valid"      // Passed validation, not yet processed
+	BulkRowInvalid    BulkRowStatus = "invalid"    // Failed validation, never processed
+	BulkRowProcessing BulkRowStatus = "processing" // Issuance under the reserved cert ID has started
+	BulkRowIssued     BulkRowStatus = "issued"
+	BulkRowSubmitted  BulkRowStatus = "submitted_for_approval"
+	BulkRowFailed     BulkRowStatus = "failed" // Processing error, retried on resume
+)
+
+// BulkIssuanceRow is one manifest line and its processing result
+type BulkIssuanceRow struct {
+	RowNumber int                 `bson:"row_number" json:"row_number"`
+	StudentID string              `bson:"student_id" json:"student_id"`
+	CertType  CredentialType      `bson:"cert_type" json:"cert_type"`
+	FileName  string              `bson:"file_name" json:"file_name"`
+	Metadata  CertificateMetadata `bson:"metadata" json:"metadata"`
+	Status    BulkRowStatus       `bson:"status" json:"status"`
+	Errors    []string            `bson:"errors,omitempty" json:"errors,omitempty"`
+	CertID    string              `bson:"cert_id,omitempty" json:"cert_id,omitempty"`     // Reserved before issuance starts
+	IssuedAt  *time.Time          `bson:"issued_at,omitempty" json:"issued_at,omitempty"` // Issuance time the cert ID was derived from
+	TxHash    string              `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	DraftID   string              `bson:"draft_id,omitempty" json:"draft_id,omitempty"`
+}
+
+// BulkIssuanceJob tracks a manifest plus document archive through the issuance pipeline.
+// Rows are persisted after each step so an interrupted job can be resumed.
+type BulkIssuanceJob struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IssuerID     string             `bson:"issuer_id" json:"issuer_id"`
+	ManifestName string             `bson:"manifest_name" json:"manifest_name"`
+	ArchivePath  string             `bson:"archive_path" json:"-"` // ZIP of documents kept on disk until the job completes
+	Status       BulkJobStatus      `bson:"status" json:"status"`
+	Rows         []BulkIssuanceRow  `bson:"rows" json:"rows"`
+	Total        int                `bson:"total" json:"total"`
+	Invalid      int                `bson:"invalid" json:"invalid"`
+	Issued       int                `bson:"issued" json:"issued"`
+	Submitted    int                `bson:"submitted" json:"submitted"`
+	Failed       int                `bson:"failed" json:"failed"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+	CompletedAt  *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// Tally recomputes the per-status counters from the rows
+func (j *BulkIssuanceJob) Tally() {
+	j.Total, j.Invalid, j.Issued, j.Submitted, j.Failed = len(j.Rows), 0, 0, 0, 0
+	for _, row := range j.Rows {
+		switch row.Status {
+		case BulkRowInvalid:
+			j.Invalid++
+		case BulkRowIssued:
+			j.Issued++
+		case BulkRowSubmitted:
+			j.Submitted++
+		case BulkRowFailed:
+			j.Failed++
+		}
+	}
+}
@@ -23,20 +23,71 @@ type Certificate struct {
 	VerifiedAt   *time.Time         `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
 	RevokedAt    *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
 	RevokeReason string             `bson:"revoke_reason,omitempty" json:"revoke_reason,omitempty"`
+	Suspension   *CertificateSuspension `bson:"suspension,omitempty" json:"suspension,omitempty"` // Set while the certificate is on hold
+	Supersedes   string             `bson:"supersedes,omitempty" json:"supersedes,omitempty"`       // CertID of the version this one replaces
+	SupersededBy string             `bson:"superseded_by,omitempty" json:"superseded_by,omitempty"` // CertID of the version that replaced this one
+	ReissueReason string            `bson:"reissue_reason,omitempty" json:"reissue_reason,omitempty"`
 	Metadata     CertificateMetadata `bson:"metadata" json:"metadata"`         // Additional certificate data
+	Disclosures  []string           `bson:"disclosures,omitempty" json:"-"`  // Salted SD-JWT disclosures of the metadata fields, held for the student
+	DuplicateOf  []string           `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"` // Active certificates this one repeats, recorded under the warn duplicate policy
+	Encryption   *DocumentEncryption `bson:"encryption,omitempty" json:"encryption,omitempty"` // Set when the stored file is encrypted; FileHash stays over the plaintext
+	SourceHash   string             `bson:"source_hash,omitempty" json:"source_hash,omitempty"` // SHA256 of the document before it was signed, when signing changed it
+	Signature    *DocumentSignature `bson:"signature,omitempty" json:"signature,omitempty"`     // PAdES signature applied to the PDF; FileHash covers the signed file
 	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// DocumentEncryption describes how the stored copy of a certificate file is encrypted
+type DocumentEncryption struct {
+	Algorithm  string `bson:"algorithm" json:"algorithm"` // AES-256-GCM with the nonce prepended to the ciphertext
+	KeyID      string `bson:"key_id" json:"key_id"`       // Fingerprint of the KEK that wrapped the data key
+	WrappedKey []byte `bson:"wrapped_key" json:"-"`       // Per-certificate data key sealed with the KEK
+}
+
+// DocumentSignature describes the institutional PAdES signature applied to a certificate PDF
+type DocumentSignature struct {
+	Level         string     `bson:"level" json:"level"`                                       // PAdES-B-B, or PAdES-B-T when timestamped
+	Signer        string     `bson:"signer" json:"signer"`                                     // Subject of the signing certificate
+	Fingerprint   string     `bson:"fingerprint" json:"fingerprint"`                           // SHA256 of the signing certificate
+	SignedAt      time.Time  `bson:"signed_at" json:"signed_at"`                               // Signing time claimed by the signer
+	TimestampedAt *time.Time `bson:"timestamped_at,omitempty" json:"timestamped_at,omitempty"` // Time asserted by the timestamp authority
+	TSA           string     `bson:"tsa,omitempty" json:"tsa,omitempty"`
+}
+
 // CertificateStatus represents the status of a certificate
 type CertificateStatus string
 
 const (
-	CertStatusIssued   CertificateStatus = "issued"
-	CertStatusVerified CertificateStatus = "verified"
-	CertStatusRevoked  CertificateStatus = "revoked"
+	CertStatusIssued     CertificateStatus = "issued"
+	CertStatusVerified   CertificateStatus = "verified"
+	CertStatusRevoked    CertificateStatus = "revoked"
+	CertStatusSuspended  CertificateStatus = "suspended"
+	CertStatusSuperseded CertificateStatus = "superseded"
+
+	// A reissued version waiting for the version it replaces to be superseded on-chain
+	CertStatusPendingSupersede CertificateStatus = "pending_supersede"
+
+	// Pre-issuance states used by certificate drafts under an approval policy
+	CertStatusDraft           CertificateStatus = "draft"
+	CertStatusPendingApproval CertificateStatus = "pending_approval"
+	CertStatusApproved        CertificateStatus = "approved" // Fully approved and being issued
+	CertStatusRejected        CertificateStatus = "rejected"
 )
 
+// CertificateSuspension records a temporary, reversible hold on a certificate
+type CertificateSuspension struct {
+	Reason      string            `bson:"reason" json:"reason"`
+	SuspendedBy string            `bson:"suspended_by" json:"suspended_by"` // User ID of the suspending actor
+	SuspendedAt time.Time         `bson:"suspended_at" json:"suspended_at"`
+	ReinstateAt *time.Time        `bson:"reinstate_at,omitempty" json:"reinstate_at,omitempty"` // Optional automatic reinstatement
+	PrevStatus  CertificateStatus `bson:"prev_status" json:"prev_status"`                       // Status restored on reinstatement
+}
+
+// IsDue reports whether the suspension should be lifted automatically at the given time
+func (s *CertificateSuspension) IsDue(now time.Time) bool {
+	return s != nil && s.ReinstateAt != nil && !now.Before(*s.ReinstateAt)
+}
+
 // CertificateMetadata contains additional information about the certificate
 type CertificateMetadata struct {
 	StudentName    string    `bson:"student_name" json:"student_name"`
@@ -65,23 +116,46 @@ type IssueCertificateRequest struct {
 	Metadata      CertificateMetadata `json:"metadata" validate:"required"`
 }
 
+// ReissueCertificateRequest represents the request to replace a certificate with an amended version
+type ReissueCertificateRequest struct {
+	Reason   string              `json:"reason" validate:"required"`
+	FileData []byte              `json:"file_data" validate:"required"` // Base64 encoded file
+	FileName string              `json:"file_name" validate:"required"`
+	Metadata CertificateMetadata `json:"metadata" validate:"required"`
+}
+
+// SuspendCertificateRequest represents the request to place a certificate on hold
+type SuspendCertificateRequest struct {
+	Reason      string     `json:"reason" validate:"required"`
+	ReinstateAt *time.Time `json:"reinstate_at,omitempty"`
+}
+
 // VerifyCertificateRequest represents the request to verify a certificate
 type VerifyCertificateRequest struct {
 	CertID string `json:"cert_id" validate:"required"`
 }
 
-// CertificateVerificationResult represents the result of certificate verification
+// CertificateVerificationResult represents the result of certificate verification. It is served without
+// authentication, so it carries only status and anchoring details; holder details are disclosed through
+// selective disclosure presentations or consented access requests. A suspension shows publicly as a flag
+// and reinstatement date; its reason and actor are attached only for the holder, issuer and staff.
 type CertificateVerificationResult struct {
 	IsValid      bool             `json:"is_valid"`
 	CertID       string           `json:"cert_id"`
-	StudentID    string           `json:"student_id"`
-	IssuerID     string           `json:"issuer_id"`
+	StudentID    string           `json:"-"` // Kept for cross-checks such as transcript constituents, never disclosed
+	IssuerID     string           `json:"-"`
 	CertType     CredentialType   `json:"cert_type"`
 	Status       CertificateStatus `json:"status"`
 	IssuedAt     time.Time        `json:"issued_at"`
 	IPFSURL      string           `json:"ipfs_url"`
 	TxHash       string           `json:"tx_hash"`
 	BlockNumber  uint64           `json:"block_number"`
-	Metadata     CertificateMetadata `json:"metadata"`
+	Suspended    bool             `json:"suspended,omitempty"`    // Certificate is temporarily invalid
+	ReinstateAt  *time.Time       `json:"reinstate_at,omitempty"` // Scheduled automatic reinstatement, if any
+	Suspension   *CertificateSuspension `json:"suspension,omitempty"` // Reason and actor, attached only for the holder, issuer and staff
+	SuspensionLapsed bool         `json:"suspension_lapsed,omitempty"` // Reinstatement date has passed but the sweep has not lifted it yet
+	Supersedes   string           `json:"supersedes,omitempty"`
+	SupersededBy string           `json:"superseded_by,omitempty"`
+	CurrentCertID string          `json:"current_cert_id,omitempty"` // Latest version when this certificate has been superseded
 	ErrorMessage string           `json:"error_message,omitempty"`
 }
@@ -0,0 +1,23 @@
+package models
+
+// ContentMigrationRequest names the provider certificate files are copied from into the configured content store
+type ContentMigrationRequest struct {
+	From string `json:"from"` // pinata, kubo, s3 or filesystem, configured from the same environment
+}
+
+// ContentMigrationFailure records a certificate file that could not be carried over
+type ContentMigrationFailure struct {
+	CertID string `json:"cert_id"`
+	CID    string `json:"cid"`
+	Error  string `json:"error"`
+}
+
+// ContentMigrationReport summarises one migration run
+type ContentMigrationReport struct {
+	From           string                    `json:"from"`
+	To             string                    `json:"to"`
+	Checked        int                       `json:"checked"`
+	Copied         int                       `json:"copied"`
+	AlreadyPresent int                       `json:"already_present"`
+	Failed         []ContentMigrationFailure `json:"failed"`
+}
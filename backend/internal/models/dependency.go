@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CircuitState is the state of a dependency's circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Calls go through
+	CircuitOpen     CircuitState = "open"      // Calls fail fast until the cooldown ends
+	CircuitHalfOpen CircuitState = "half_open" // The next call is a trial that closes or reopens the circuit
+)
+
+// DependencyStatus describes the circuit breaker guarding an external service
+type DependencyStatus struct {
+	Name                string       `json:"name"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+}
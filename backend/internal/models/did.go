@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KeyStatus is the lifecycle state of an institution signing key
+type KeyStatus string
+
+const (
+	KeyStatusActive  KeyStatus = "active"  // Signs new credentials
+	KeyStatusRetired KeyStatus = "retired" // Verifies credentials signed before RetiredAt
+)
+
+// InstitutionKey is one Ed25519 verification method of the institution's did:web document
+type InstitutionKey struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	KeyID              string             `bson:"key_id" json:"key_id"` // DID URL, e.g. did:web:example.edu#key-2
+	PublicKeyMultibase string             `bson:"public_key_multibase" json:"public_key_multibase"`
+	SealedSeed         []byte             `bson:"sealed_seed,omitempty" json:"-"` // AES-GCM sealed private seed, discarded on retirement
+	Backend            string             `bson:"backend,omitempty" json:"backend,omitempty"`
+	Reference          string             `bson:"reference,omitempty" json:"reference,omitempty"` // PKCS #11 URI of keys generated on a token
+	Status             KeyStatus          `bson:"status" json:"status"`
+	CreatedBy          string             `bson:"created_by" json:"created_by"`
+	CreatedAt          time.Time          `bson:"created_at" json:"created_at"`
+	RetiredAt          *time.Time         `bson:"retired_at,omitempty" json:"retired_at,omitempty"`
+	RotationReason     string             `bson:"rotation_reason,omitempty" json:"rotation_reason,omitempty"`
+}
+
+// IssuerDID maps an issuer address registered on-chain to its did:ethr identifier
+type IssuerDID struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DID          string             `bson:"did" json:"did"`
+	Address      string             `bson:"address" json:"address"` // EIP-55 checksummed
+	Name         string             `bson:"name" json:"name"`
+	Role         string             `bson:"role" json:"role"`
+	Institution  string             `bson:"institution" json:"institution"`
+	RegisteredBy string             `bson:"registered_by" json:"registered_by"`
+	RegisteredAt time.Time          `bson:"registered_at" json:"registered_at"`
+}
+
+// DIDDocument is a W3C DID Core document
+type DIDDocument struct {
+	Context            []string                `json:"@context"`
+	ID                 string                  `json:"id"`
+	Controller         string                  `json:"controller,omitempty"`
+	VerificationMethod []DIDVerificationMethod `json:"verificationMethod"`
+	Authentication     []string                `json:"authentication,omitempty"`
+	AssertionMethod    []string                `json:"assertionMethod,omitempty"`
+	Service            []DIDService            `json:"service,omitempty"`
+}
+
+// DIDVerificationMethod is a public key or blockchain account listed in a DID document
+type DIDVerificationMethod struct {
+	ID                  string `json:"id"`
+	Type                string `json:"type"`
+	Controller          string `json:"controller"`
+	PublicKeyMultibase  string `json:"publicKeyMultibase,omitempty"`
+	BlockchainAccountID string `json:"blockchainAccountId,omitempty"`
+}
+
+// DIDService is a service endpoint advertised in a DID document
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// RotateKeyRequest represents the request to replace the institution's signing key
+type RotateKeyRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// CreatePresentationRequest names the metadata fields a student chooses to reveal
+type CreatePresentationRequest struct {
+	Fields []string `json:"fields"`
+}
+
+// SDPresentation is an SD-JWT presentation: the issuer-signed JWT followed by the chosen disclosures
+type SDPresentation struct {
+	CertID       string   `json:"cert_id"`
+	Fields       []string `json:"fields"`
+	Presentation string   `json:"presentation"` // <jwt>~<disclosure>~...~
+}
+
+// SDVerifyRequest carries a presentation to verify
+type SDVerifyRequest struct {
+	Presentation string `json:"presentation"`
+}
+
+// SDVerificationResult reports the disclosed fields and how they were checked against the anchored commitment
+type SDVerificationResult struct {
+	Valid            bool                   `json:"valid"`
+	SignatureValid   bool                   `json:"signature_valid"`
+	CertID           string                 `json:"cert_id,omitempty"`
+	CertType         string                 `json:"cert_type,omitempty"`
+	Disclosed        map[string]interface{} `json:"disclosed"`
+	Undisclosed      int                    `json:"undisclosed"`                 // Committed fields the holder kept hidden
+	CommitmentSource string                 `json:"commitment_source,omitempty"` // blockchain or record
+	Status           CertificateStatus      `json:"status,omitempty"`
+	Errors           []string               `json:"errors,omitempty"`
+}
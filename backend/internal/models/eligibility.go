@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DegreeEligibilityRule lists the conditions a student must meet before a degree is issued.
+// A rule with an empty department is the institution-wide default.
+type DegreeEligibilityRule struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Department          string             `bson:"department" json:"department"`
+	RequiredSemesters   int                `bson:"required_semesters" json:"required_semesters"`       // Semesters 1..N must each have a current marksheet; 0 disables the check
+	MinCGPA             float64            `bson:"min_cgpa" json:"min_cgpa"`                           // 0 disables the check
+	NoActiveSuspensions bool               `bson:"no_active_suspensions" json:"no_active_suspensions"` // None of the student's certificates may be on hold
+	NoDuplicateDegree   bool               `bson:"no_duplicate_degree" json:"no_duplicate_degree"`
+	UpdatedBy           string             `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// EligibilityCondition is the outcome of one rule condition for a student
+type EligibilityCondition struct {
+	Condition string `json:"condition"`
+	Met       bool   `json:"met"`
+	Detail    string `json:"detail"`
+}
+
+// DegreeEligibilityResult reports whether a student may be issued a degree and why not
+type DegreeEligibilityResult struct {
+	StudentID  string                 `json:"student_id"`
+	Department string                 `json:"department,omitempty"`
+	Rule       DegreeEligibilityRule  `json:"rule"`
+	Eligible   bool                   `json:"eligible"`
+	Conditions []EligibilityCondition `json:"conditions"`
+	Unmet      []string               `json:"unmet,omitempty"`
+}
+
+// SetEligibilityRuleRequest represents the request to configure a department's degree rule
+type SetEligibilityRuleRequest struct {
+	Department          string  `json:"department"`
+	RequiredSemesters   int     `json:"required_semesters"`
+	MinCGPA             float64 `json:"min_cgpa"`
+	NoActiveSuspensions bool    `json:"no_active_suspensions"`
+	NoDuplicateDegree   bool    `json:"no_duplicate_degree"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdempotencyRecord keeps the response to a request sent with an Idempotency-Key header,
+// so a client retrying after a timeout gets the original result instead of a second issuance
+type IdempotencyRecord struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      string             `bson:"user_id" json:"user_id"`
+	Key         string             `bson:"key" json:"key"`
+	Method      string             `bson:"method" json:"method"`
+	Path        string             `bson:"path" json:"path"`
+	RequestHash string             `bson:"request_hash" json:"request_hash"` // SHA-256 of method, path and body; a reused key must send the same request
+	Completed   bool               `bson:"completed" json:"completed"`       // False while the first request is still being handled
+	StatusCode  int                `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	ContentType string             `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	Body        []byte             `bson:"body,omitempty" json:"-"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expires_at"`
+}
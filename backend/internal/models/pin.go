@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PinStatus is the last observed state of a CID on one content provider
+type PinStatus string
+
+const (
+	PinStatusPinned  PinStatus = "pinned"
+	PinStatusMissing PinStatus = "missing" // The provider answered but does not hold the content
+	PinStatusError   PinStatus = "error"   // The provider could not be checked or refused the content
+)
+
+// PinRecord tracks one CID on one provider
+type PinRecord struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CID            string             `bson:"cid" json:"cid"`
+	CertID         string             `bson:"cert_id" json:"cert_id"`
+	Provider       string             `bson:"provider" json:"provider"`
+	Status         PinStatus          `bson:"status" json:"status"`
+	Size           int64              `bson:"size,omitempty" json:"size,omitempty"`
+	LastError      string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	LastCheckedAt  time.Time          `bson:"last_checked_at" json:"last_checked_at"`
+	LastPinnedAt   *time.Time         `bson:"last_pinned_at,omitempty" json:"last_pinned_at,omitempty"`
+	LastRepairedAt *time.Time         `bson:"last_repaired_at,omitempty" json:"last_repaired_at,omitempty"` // Set when the content was re-pinned from another replica
+}
+
+// CertificatePinStatus reports where a certificate's file is held
+type CertificatePinStatus struct {
+	CertID    string      `json:"cert_id"`
+	CID       string      `json:"cid"`
+	Available bool        `json:"available"` // At least one provider holds the file
+	Replicas  []PinRecord `json:"replicas"`
+}
+
+// ProviderPinHealth aggregates the pin records of one provider
+type ProviderPinHealth struct {
+	Provider      string     `json:"provider"`
+	Pinned        int        `json:"pinned"`
+	Missing       int        `json:"missing"`
+	Errors        int        `json:"errors"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+}
+
+// PinHealth is the aggregate replication view across all tracked CIDs
+type PinHealth struct {
+	Providers       []ProviderPinHealth `json:"providers"`
+	TrackedCIDs     int                 `json:"tracked_cids"`
+	FullyReplicated int                 `json:"fully_replicated"`
+	Degraded        int                 `json:"degraded"`    // Held by some providers but not all
+	Unavailable     int                 `json:"unavailable"` // Held by no provider
+	LastSweepAt     *time.Time          `json:"last_sweep_at,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuarantinedDocument is a document that failed pre-issuance checks and was set aside for review
+// instead of being discarded. The file itself is kept outside the content store.
+type QuarantinedDocument struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FileName    string             `bson:"file_name" json:"file_name"`
+	FileHash    string             `bson:"file_hash" json:"file_hash"` // SHA-256 of the document as uploaded
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	Reason      string             `bson:"reason" json:"reason"`
+	Threat      string             `bson:"threat,omitempty" json:"threat,omitempty"` // Set when the malware scanner flagged the file
+	UploadedBy  string             `bson:"uploaded_by" json:"uploaded_by"`
+	StudentID   string             `bson:"student_id,omitempty" json:"student_id,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
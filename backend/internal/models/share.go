@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLink grants read-only verification access to a set of a student's certificates.
+// Only the SHA-256 of the link token is stored; the token itself is shown once at creation.
+type ShareLink struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TokenHash      string             `bson:"token_hash" json:"-"`
+	StudentID      string             `bson:"student_id" json:"student_id"`
+	OwnerID        string             `bson:"owner_id" json:"owner_id"`
+	CertIDs        []string           `bson:"cert_ids" json:"cert_ids"`
+	Label          string             `bson:"label,omitempty" json:"label,omitempty"`
+	PasswordHash   string             `bson:"password_hash,omitempty" json:"-"`
+	HasPassword    bool               `bson:"has_password" json:"has_password"`
+	ExpiresAt      *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	MaxViews       int                `bson:"max_views,omitempty" json:"max_views,omitempty"` // 0 means unlimited
+	Views          int                `bson:"views" json:"views"`
+	RevokedAt      *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	LastAccessedAt *time.Time         `bson:"last_accessed_at,omitempty" json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ShareAccessOutcome records whether an access through a share link was served
+type ShareAccessOutcome string
+
+const (
+	ShareAccessGranted   ShareAccessOutcome = "granted"
+	ShareAccessExpired   ShareAccessOutcome = "expired"
+	ShareAccessRevoked   ShareAccessOutcome = "revoked"
+	ShareAccessExhausted ShareAccessOutcome = "exhausted"
+	ShareAccessPassword  ShareAccessOutcome = "bad_password"
+)
+
+// ShareAccess is one entry in a share link's access log
+type ShareAccess struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LinkID       string             `bson:"link_id" json:"link_id"`
+	Outcome      ShareAccessOutcome `bson:"outcome" json:"outcome"`
+	IP           string             `bson:"ip" json:"ip"`
+	UserAgent    string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	VerifierID   string             `bson:"verifier_id,omitempty" json:"verifier_id,omitempty"` // Set when the verifier was signed in
+	VerifierName string             `bson:"verifier_name,omitempty" json:"verifier_name,omitempty"`
+	AccessedAt   time.Time          `bson:"accessed_at" json:"accessed_at"`
+}
+
+// CreateShareLinkRequest represents the request to share one or more certificates
+type CreateShareLinkRequest struct {
+	CertIDs        []string   `json:"cert_ids"`
+	Label          string     `json:"label,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	ExpiresInHours int        `json:"expires_in_hours,omitempty"` // Used when expires_at is not given
+	MaxViews       int        `json:"max_views,omitempty"`
+	Password       string     `json:"password,omitempty"`
+}
+
+// CreatedShareLink returns the new link together with its one-time token
+type CreatedShareLink struct {
+	Link  ShareLink `json:"link"`
+	Token string    `json:"token"`
+	URL   string    `json:"url"`
+}
+
+// SharedCertificateView is the read-only view of a certificate served through a share link
+type SharedCertificateView struct {
+	CertID       string                         `json:"cert_id"`
+	CertType     CredentialType                 `json:"cert_type"`
+	Status       CertificateStatus              `json:"status"`
+	IssuedAt     time.Time                      `json:"issued_at"`
+	FileHash     string                         `json:"file_hash"`
+	TxHash       string                         `json:"tx_hash"`
+	BlockNumber  uint64                         `json:"block_number"`
+	IPFSURL      string                         `json:"ipfs_url"`
+	Metadata     CertificateMetadata            `json:"metadata"`
+	Verification *CertificateVerificationResult `json:"verification"`
+}
+
+// SharedView is what a verifier sees when opening a share link
+type SharedView struct {
+	Label          string                  `json:"label,omitempty"`
+	StudentID      string                  `json:"student_id"`
+	ExpiresAt      *time.Time              `json:"expires_at,omitempty"`
+	RemainingViews *int                    `json:"remaining_views,omitempty"`
+	Certificates   []SharedCertificateView `json:"certificates"`
+}
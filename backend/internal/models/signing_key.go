@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Purposes of the institution's signing keys
+const (
+	KeyPurposeBlockchain  = "blockchain"  // Ethereum transactions
+	KeyPurposeCredentials = "credentials" // Verifiable credential proofs
+	KeyPurposeDocuments   = "documents"   // PAdES signatures on certificate PDFs
+	KeyPurposeTokens      = "tokens"      // Session JWTs
+)
+
+// Where signing keys are held
+const (
+	KeyBackendMemory   = "memory"   // Hex or PEM key from configuration, or derived from JWT_SECRET
+	KeyBackendKeystore = "keystore" // Password-encrypted Web3 Secret Storage file
+	KeyBackendPKCS11   = "pkcs11"   // Non-extractable key on a PKCS #11 token or HSM
+)
+
+// SigningKey is one entry of the key inventory. Retired keys keep their public half so signatures
+// and tokens made before the rotation still verify.
+type SigningKey struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Purpose        string             `bson:"purpose" json:"purpose"`
+	KeyID          string             `bson:"key_id" json:"key_id"` // e.g. tokens-2, the JWT kid
+	Backend        string             `bson:"backend" json:"backend"`
+	Reference      string             `bson:"reference,omitempty" json:"reference,omitempty"` // Keystore path or PKCS #11 URI, never a PIN
+	Algorithm      string             `bson:"algorithm" json:"algorithm"`
+	PublicKey      []byte             `bson:"public_key" json:"public_key"`               // PKIX DER; an uncompressed point for secp256k1
+	Fingerprint    string             `bson:"fingerprint" json:"fingerprint"`             // Hex SHA-256 of PublicKey
+	Address        string             `bson:"address,omitempty" json:"address,omitempty"` // Ethereum account of blockchain keys
+	Status         KeyStatus          `bson:"status" json:"status"`
+	CreatedBy      string             `bson:"created_by" json:"created_by"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	RetiredAt      *time.Time         `bson:"retired_at,omitempty" json:"retired_at,omitempty"`
+	RotationReason string             `bson:"rotation_reason,omitempty" json:"rotation_reason,omitempty"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CertificateTemplate is a versioned page layout used to render certificate PDFs on the server.
+// Templates are scoped to an institution and credential type; each change creates a new version.
+type CertificateTemplate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Institution string             `bson:"institution" json:"institution"`
+	CertType    CredentialType     `bson:"cert_type" json:"cert_type"`
+	Version     int                `bson:"version" json:"version"` // Increments per institution and cert type
+	Name        string             `bson:"name" json:"name"`
+	PageSize    string             `bson:"page_size" json:"page_size"` // A4 or Letter
+	Landscape   bool               `bson:"landscape" json:"landscape"`
+	Elements    []TemplateElement  `bson:"elements" json:"elements"`
+	Active      bool               `bson:"active" json:"active"` // At most one active version per institution and cert type
+	CreatedBy   string             `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// TemplateElementType identifies what a template element draws
+type TemplateElementType string
+
+const (
+	TemplateElementText TemplateElementType = "text"
+	TemplateElementLine TemplateElementType = "line"
+	TemplateElementBox  TemplateElementType = "box"
+	TemplateElementQR   TemplateElementType = "qr" // Verification QR code; Width is the side length
+)
+
+// TemplateElement is a single drawing instruction. Coordinates are in points from the top-left corner.
+type TemplateElement struct {
+	Type      TemplateElementType `bson:"type" json:"type"`
+	X         float64             `bson:"x" json:"x"`
+	Y         float64             `bson:"y" json:"y"`
+	Width     float64             `bson:"width,omitempty" json:"width,omitempty"`   // Wrap width for text, extent for lines and boxes
+	Height    float64             `bson:"height,omitempty" json:"height,omitempty"` // Box height or line vertical extent
+	Text      string              `bson:"text,omitempty" json:"text,omitempty"`     // May contain {{placeholders}}
+	FontSize  float64             `bson:"font_size,omitempty" json:"font_size,omitempty"`
+	Bold      bool                `bson:"bold,omitempty" json:"bold,omitempty"`
+	Align     string              `bson:"align,omitempty" json:"align,omitempty"` // left, center or right within Width
+	Color     string              `bson:"color,omitempty" json:"color,omitempty"` // #RRGGBB
+	Fill      bool                `bson:"fill,omitempty" json:"fill,omitempty"`   // Fill boxes instead of stroking them
+	LineWidth float64             `bson:"line_width,omitempty" json:"line_width,omitempty"`
+}
+
+// CreateTemplateRequest represents the request to add a new template version
+type CreateTemplateRequest struct {
+	Institution string            `json:"institution" validate:"required"`
+	CertType    CredentialType    `json:"cert_type" validate:"required"`
+	Name        string            `json:"name"`
+	PageSize    string            `json:"page_size"`
+	Landscape   bool              `json:"landscape"`
+	Elements    []TemplateElement `json:"elements" validate:"required"`
+	Activate    bool              `json:"activate"` // Make this version the active one immediately
+}
+
+// GenerateCertificateRequest represents the request to render and issue a certificate from a template
+type GenerateCertificateRequest struct {
+	StudentID  string              `json:"student_id" validate:"required"`
+	CertType   CredentialType      `json:"cert_type" validate:"required"`
+	TemplateID string              `json:"template_id,omitempty"` // Defaults to the active template for the institution
+	Metadata   CertificateMetadata `json:"metadata" validate:"required"`
+}
+
+// PreviewTemplateRequest represents the request to render a template with sample data
+type PreviewTemplateRequest struct {
+	StudentID string              `json:"student_id"`
+	Metadata  CertificateMetadata `json:"metadata"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// TranscriptEntry is one semester marksheet as it appears on a transcript
+type TranscriptEntry struct {
+	CertID         string            `json:"cert_id"`
+	Semester       string            `json:"semester"`
+	SemesterNumber int               `json:"semester_number,omitempty"`
+	AcademicYear   string            `json:"academic_year,omitempty"`
+	Course         string            `json:"course,omitempty"`
+	Grade          string            `json:"grade,omitempty"`
+	GPA            float64           `json:"gpa"`
+	Credits        float64           `json:"credits,omitempty"`
+	Status         CertificateStatus `json:"status"`
+	IssuedAt       time.Time         `json:"issued_at"`
+}
+
+// TranscriptSummary aggregates a student's current semester marksheets
+type TranscriptSummary struct {
+	StudentID      string            `json:"student_id"`
+	StudentName    string            `json:"student_name"`
+	Department     string            `json:"department,omitempty"`
+	Institution    string            `json:"institution,omitempty"`
+	Entries        []TranscriptEntry `json:"entries"`
+	TotalCredits   float64           `json:"total_credits"`
+	CumulativeCGPA float64           `json:"cumulative_cgpa"`
+	CreditWeighted bool              `json:"credit_weighted"` // False when some marksheets carry no credits and a plain mean was used
+	Warnings       []string          `json:"warnings,omitempty"`
+}
+
+// GenerateTranscriptRequest represents the request to issue a consolidated transcript
+type GenerateTranscriptRequest struct {
+	StudentID string `json:"student_id"`
+}
+
+// TranscriptVerificationResult verifies a transcript together with every marksheet it consolidates
+type TranscriptVerificationResult struct {
+	Valid          bool                            `json:"valid"`
+	Transcript     *CertificateVerificationResult  `json:"transcript"`
+	CumulativeCGPA float64                         `json:"cumulative_cgpa"`
+	TotalCredits   float64                         `json:"total_credits"`
+	Constituents   []CertificateVerificationResult `json:"constituents"`
+	Errors         []string                        `json:"errors,omitempty"`
+}
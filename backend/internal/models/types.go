@@ -24,6 +24,7 @@ const (
 	CredentialTypeNOC           CredentialType = "noc"
 	CredentialTypeParticipation CredentialType = "participation_cert"
 	CredentialTypeNFT           CredentialType = "nft_certificate"
+	CredentialTypeTranscript    CredentialType = "transcript"
 )
 
 // RolePermissions defines what each role can do
@@ -40,6 +41,8 @@ type RolePermissions struct {
 	CanManageUsers         bool `json:"can_manage_users"`
 	CanViewAllCredentials  bool `json:"can_view_all_credentials"`
 	CanApproveStudents     bool `json:"can_approve_students"`
+	CanSuspendCredentials  bool `json:"can_suspend_credentials"`
+	CanManageTemplates     bool `json:"can_manage_templates"`
 }
 
 // GetRolePermissions returns permissions for a given role
@@ -59,6 +62,8 @@ func GetRolePermissions(role UserRole) RolePermissions {
 			CanManageUsers:         true,
 			CanViewAllCredentials:  true,
 			CanApproveStudents:     true,
+			CanSuspendCredentials:  true,
+			CanManageTemplates:     true,
 		}
 	case RoleCOE:
 		return RolePermissions{
@@ -66,6 +71,8 @@ func GetRolePermissions(role UserRole) RolePermissions {
 			CanVerifyCredentials:  true,
 			CanReadOnlyAccess:     true,
 			CanViewAllCredentials: true,
+			CanSuspendCredentials: true,
+			CanManageTemplates:    true,
 		}
 	case RoleDepartmentFaculty:
 		return RolePermissions{
@@ -142,6 +149,10 @@ func CanPerformAction(role UserRole, permission string) bool {
 		return perms.CanViewAllCredentials
 	case "can_approve_students":
 		return perms.CanApproveStudents
+	case "can_suspend_credentials":
+		return perms.CanSuspendCredentials
+	case "can_manage_templates":
+		return perms.CanManageTemplates
 	default:
 		return false
 	}
@@ -0,0 +1,91 @@
+package models
+
+import "encoding/json"
+
+// VerifiableCredential is a W3C Verifiable Credentials Data Model 2.0 view of a certificate
+type VerifiableCredential struct {
+	Context           []string            `json:"@context"`
+	ID                string              `json:"id"`
+	Type              []string            `json:"type"`
+	Issuer            VCIssuer            `json:"issuer"`
+	ValidFrom         string              `json:"validFrom"`
+	ValidUntil        string              `json:"validUntil,omitempty"`
+	CredentialSubject VCSubject           `json:"credentialSubject"`
+	CredentialStatus  *VCStatus           `json:"credentialStatus,omitempty"`
+	Evidence          []VCEvidence        `json:"evidence,omitempty"`
+	Proof             *DataIntegrityProof `json:"proof,omitempty"`
+}
+
+// VCIssuer identifies the issuing institution by DID
+type VCIssuer struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// VCSubject carries the claims taken from CertificateMetadata
+type VCSubject struct {
+	ID          string              `json:"id,omitempty"` // Student wallet as did:ethr when known
+	StudentID   string              `json:"studentId"`
+	Name        string              `json:"name,omitempty"`
+	Email       string              `json:"email,omitempty"`
+	Achievement VCAchievementClaims `json:"achievement"`
+}
+
+// VCAchievementClaims describes what the certificate attests to
+type VCAchievementClaims struct {
+	CertificateType string  `json:"certificateType"`
+	Institution     string  `json:"institution,omitempty"`
+	Department      string  `json:"department,omitempty"`
+	Course          string  `json:"course,omitempty"`
+	Semester        string  `json:"semester,omitempty"`
+	AcademicYear    string  `json:"academicYear,omitempty"`
+	Grade           string  `json:"grade,omitempty"`
+	CGPA            float64 `json:"cgpa,omitempty"`
+	Description     string  `json:"description,omitempty"`
+}
+
+// VCStatus points verifiers at the live certificate status (revocation, suspension, supersession)
+type VCStatus struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	CertificateID string `json:"certificateId"`
+}
+
+// VCEvidence links the credential to its blockchain anchor and stored document
+type VCEvidence struct {
+	Type            []string `json:"type"`
+	TransactionHash string   `json:"transactionHash,omitempty"`
+	BlockNumber     uint64   `json:"blockNumber,omitempty"`
+	DocumentHash    string   `json:"documentHash"` // SHA-256 of the certificate file
+	IPFSCID         string   `json:"ipfsCid,omitempty"`
+	IPFSURL         string   `json:"ipfsUrl,omitempty"`
+}
+
+// DataIntegrityProof is an embedded W3C Data Integrity proof
+type DataIntegrityProof struct {
+	Context            []string `json:"@context,omitempty"`
+	Type               string   `json:"type"`
+	Cryptosuite        string   `json:"cryptosuite"`
+	Created            string   `json:"created"`
+	VerificationMethod string   `json:"verificationMethod"`
+	ProofPurpose       string   `json:"proofPurpose"`
+	ProofValue         string   `json:"proofValue,omitempty"`
+}
+
+// VCVerifyRequest carries either a credential with an embedded proof or a JWT-VC
+type VCVerifyRequest struct {
+	Credential json.RawMessage `json:"credential,omitempty"`
+	JWT        string          `json:"jwt,omitempty"`
+}
+
+// VCVerificationResult reports signature and certificate status checks for a presented credential
+type VCVerificationResult struct {
+	Valid              bool              `json:"valid"`
+	Format             string            `json:"format"` // data_integrity or jwt
+	SignatureValid     bool              `json:"signature_valid"`
+	VerificationMethod string            `json:"verification_method,omitempty"`
+	Issuer             string            `json:"issuer,omitempty"`
+	CertID             string            `json:"cert_id,omitempty"`
+	Status             CertificateStatus `json:"status,omitempty"`
+	Errors             []string          `json:"errors,omitempty"`
+}
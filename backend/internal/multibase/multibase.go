@@ -0,0 +1,74 @@
+// Package multibase implements the base58btc ('z') multibase encoding used by DID keys and
+// Data Integrity proof values.
+package multibase
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes with the Bitcoin alphabet
+func base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		idx := -1
+		for i, a := range base58Alphabet {
+			if a == r {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", r)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	out := n.Bytes()
+	for _, r := range s {
+		if r != rune(base58Alphabet[0]) {
+			break
+		}
+		out = append([]byte{0}, out...)
+	}
+	return out, nil
+}
+
+// Encode returns data as a base58btc multibase string
+func Encode(data []byte) string {
+	return "z" + base58Encode(data)
+}
+
+// Decode parses a base58btc multibase string
+func Decode(s string) ([]byte, error) {
+	if len(s) < 2 || s[0] != 'z' {
+		return nil, fmt.Errorf("value is not base58btc multibase")
+	}
+	return base58Decode(s[1:])
+}
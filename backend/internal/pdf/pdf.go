@@ -0,0 +1,155 @@
+// Package pdf is a minimal PDF 1.4 writer for generated documents.
+//
+// It supports the standard Helvetica fonts, lines, rectangles and filled
+// rectangles, which is all the certificate templates need. Coordinates are in
+// points measured from the top-left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Common page sizes in points
+const (
+	A4Width      = 595.28
+	A4Height     = 841.89
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+)
+
+// Color is an RGB color with components in the range 0-1
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the default drawing color
+var Black = Color{0, 0, 0}
+
+// Document is an in-memory PDF with one or more pages of the same size
+type Document struct {
+	Title  string
+	Width  float64
+	Height float64
+	pages  []*Page
+}
+
+// Page collects the content stream of a single page
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New creates an empty document with the given page size
+func New(width, height float64) *Document {
+	return &Document{Width: width, Height: height}
+}
+
+// AddPage appends a blank page and returns it for drawing
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text draws a single line of text with its baseline at y
+func (p *Page) Text(x, y, size float64, bold bool, color Color, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT %s rg /%s %s Tf %s %s Td (%s) Tj ET\n",
+		color.fill(), font, num(size), num(x), num(p.doc.Height-y), escape(s))
+}
+
+// Line strokes a straight line
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		color.fill(), num(width), num(x1), num(p.doc.Height-y1), num(x2), num(p.doc.Height-y2))
+}
+
+// Rect strokes a rectangle whose top-left corner is at x, y
+func (p *Page) Rect(x, y, w, h, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s %s %s re S\n",
+		color.fill(), num(width), num(x), num(p.doc.Height-y-h), num(w), num(h))
+}
+
+// FillRect fills a rectangle whose top-left corner is at x, y
+func (p *Page) FillRect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		color.fill(), num(x), num(p.doc.Height-y-h), num(w), num(h))
+}
+
+// Bytes serializes the document. Output is deterministic for identical input.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts, 5 info; pages follow in pairs
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (BlockCred) >>", escape(d.Title)))
+
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(d.Width), num(d.Height), firstPage+i*2+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+func (c Color) fill() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// num formats a number compactly with at most two decimals
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// escape encodes s as a WinAnsi PDF string literal body
+func escape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,680 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Values produced by the reader. Strings, numbers, booleans and null are kept verbatim as Raw so
+// that objects copied into an incremental update are written back exactly as they were.
+type (
+	Value interface{}
+	Name  string // Raw name token without the leading slash
+	Raw   string
+	Array []Value
+	Ref   struct{ Num, Gen int }
+)
+
+// Dict is a PDF dictionary that remembers its key order
+type Dict struct {
+	keys   []string
+	values map[string]Value
+}
+
+func NewDict() *Dict {
+	return &Dict{values: make(map[string]Value)}
+}
+
+func (d *Dict) Get(key string) Value {
+	return d.values[key]
+}
+
+func (d *Dict) Set(key string, v Value) {
+	if _, ok := d.values[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+	d.values[key] = v
+}
+
+// Int returns an integer entry, or false when it is missing or not a direct integer
+func (d *Dict) Int(key string) (int, bool) {
+	raw, ok := d.values[key].(Raw)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(raw))
+	return n, err == nil
+}
+
+// xrefEntry locates an object, either at a byte offset or inside an object stream
+type xrefEntry struct {
+	offset int
+	gen    int
+	stream int // Object stream number; 0 for objects stored directly
+	index  int
+}
+
+// Reader gives access to the objects of an existing PDF, as needed to append an incremental update
+type Reader struct {
+	data       []byte
+	xref       map[int]xrefEntry
+	objStreams map[int][]byte
+
+	Trailer    *Dict // Newest trailer, or the dictionary of the newest cross-reference stream
+	StartXRef  int   // Offset of the newest cross-reference section
+	XRefStream bool  // The newest section is a cross-reference stream
+}
+
+var lastStartXRef = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+
+// Open reads the cross-reference sections of a PDF, newest first
+func Open(data []byte) (*Reader, error) {
+	m := lastStartXRef.FindSubmatch(data)
+	if m == nil {
+		return nil, fmt.Errorf("%w: missing startxref", ErrMalformed)
+	}
+	start, _ := strconv.Atoi(string(m[1]))
+	r := &Reader{data: data, xref: make(map[int]xrefEntry), objStreams: make(map[int][]byte), StartXRef: start}
+
+	visited := map[int]bool{}
+	for offset, first := start, true; ; first = false {
+		if offset <= 0 || offset >= len(data) || visited[offset] {
+			return nil, fmt.Errorf("%w: broken cross-reference chain", ErrMalformed)
+		}
+		visited[offset] = true
+
+		trailer, isStream, err := r.readSection(offset)
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			r.Trailer, r.XRefStream = trailer, isStream
+		}
+		// Hybrid files point from the classic trailer to a stream holding compressed objects
+		if stm, ok := trailer.Int("XRefStm"); ok && !visited[stm] {
+			visited[stm] = true
+			if _, _, err := r.readSection(stm); err != nil {
+				return nil, err
+			}
+		}
+		prev, ok := trailer.Int("Prev")
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+	if r.Trailer.Get("Root") == nil {
+		return nil, fmt.Errorf("%w: no document catalog", ErrMalformed)
+	}
+	return r, nil
+}
+
+// readSection records the entries of one cross-reference section without overriding newer ones
+func (r *Reader) readSection(offset int) (*Dict, bool, error) {
+	if bytes.HasPrefix(r.data[offset:], []byte("xref")) {
+		trailer, err := r.readTable(offset + len("xref"))
+		return trailer, false, err
+	}
+	trailer, err := r.readStream(offset)
+	return trailer, true, err
+}
+
+func (r *Reader) readTable(pos int) (*Dict, error) {
+	p := &parser{data: r.data, pos: pos}
+	for {
+		p.skipSpace()
+		if bytes.HasPrefix(r.data[p.pos:], []byte("trailer")) {
+			p.pos += len("trailer")
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			trailer, ok := v.(*Dict)
+			if !ok {
+				return nil, fmt.Errorf("%w: trailer is not a dictionary", ErrMalformed)
+			}
+			return trailer, nil
+		}
+		first, err1 := p.integer()
+		count, err2 := p.integer()
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("%w: bad cross-reference subsection", ErrMalformed)
+		}
+		for i := 0; i < count; i++ {
+			p.skipSpace()
+			if p.pos+18 > len(r.data) {
+				return nil, fmt.Errorf("%w: truncated cross-reference table", ErrMalformed)
+			}
+			entry := string(r.data[p.pos : p.pos+18])
+			p.pos += 18
+			offset, err1 := strconv.Atoi(entry[0:10])
+			gen, err2 := strconv.Atoi(entry[11:16])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("%w: bad cross-reference entry", ErrMalformed)
+			}
+			// Free entries are skipped: hybrid files list their compressed objects as free here
+			if _, seen := r.xref[first+i]; !seen && entry[17] == 'n' {
+				r.xref[first+i] = xrefEntry{offset: offset, gen: gen}
+			}
+		}
+	}
+}
+
+func (r *Reader) readStream(offset int) (*Dict, error) {
+	dict, data, err := r.streamAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	if t, _ := dict.Get("Type").(Name); t != "XRef" {
+		return nil, fmt.Errorf("%w: startxref does not point at a cross-reference section", ErrMalformed)
+	}
+
+	w, ok := dict.Get("W").(Array)
+	if !ok || len(w) != 3 {
+		return nil, fmt.Errorf("%w: cross-reference stream without /W", ErrMalformed)
+	}
+	var widths [3]int
+	for i, v := range w {
+		raw, _ := v.(Raw)
+		widths[i], _ = strconv.Atoi(string(raw))
+	}
+	size, _ := dict.Int("Size")
+	index := []int{0, size}
+	if arr, ok := dict.Get("Index").(Array); ok {
+		index = index[:0]
+		for _, v := range arr {
+			raw, _ := v.(Raw)
+			n, _ := strconv.Atoi(string(raw))
+			index = append(index, n)
+		}
+	}
+
+	rowLen := widths[0] + widths[1] + widths[2]
+	row := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		for num := index[i]; num < index[i]+index[i+1]; num++ {
+			if (row+1)*rowLen > len(data) {
+				return nil, fmt.Errorf("%w: truncated cross-reference stream", ErrMalformed)
+			}
+			fields := data[row*rowLen : (row+1)*rowLen]
+			row++
+			kind := 1 // The type field defaults to 1 when it has zero width
+			if widths[0] > 0 {
+				kind = readField(fields[:widths[0]])
+			}
+			f2 := readField(fields[widths[0] : widths[0]+widths[1]])
+			f3 := readField(fields[widths[0]+widths[1]:])
+			if _, seen := r.xref[num]; seen {
+				continue
+			}
+			switch kind {
+			case 0:
+				r.xref[num] = xrefEntry{offset: -1}
+			case 1:
+				r.xref[num] = xrefEntry{offset: f2, gen: f3}
+			case 2:
+				r.xref[num] = xrefEntry{stream: f2, index: f3}
+			}
+		}
+	}
+	return dict, nil
+}
+
+func readField(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n<<8 | int(c)
+	}
+	return n
+}
+
+// Object returns the value of an indirect object. Stream objects yield their dictionary.
+func (r *Reader) Object(num int) (Value, error) {
+	entry, ok := r.xref[num]
+	if !ok || entry.offset < 0 {
+		return nil, fmt.Errorf("%w: object %d is missing", ErrMalformed, num)
+	}
+	if entry.stream != 0 {
+		return r.compressedObject(num, entry)
+	}
+	p := &parser{data: r.data, pos: entry.offset}
+	if err := p.objectHeader(num); err != nil {
+		return nil, err
+	}
+	return p.value()
+}
+
+// Resolve follows a reference to the object it names; other values are returned as they are
+func (r *Reader) Resolve(v Value) (Value, error) {
+	if ref, ok := v.(Ref); ok {
+		return r.Object(ref.Num)
+	}
+	return v, nil
+}
+
+// NextObjectNumber is the first object number free for new objects
+func (r *Reader) NextObjectNumber() int {
+	next, _ := r.Trailer.Int("Size")
+	for num := range r.xref {
+		if num >= next {
+			next = num + 1
+		}
+	}
+	return next
+}
+
+func (r *Reader) compressedObject(num int, entry xrefEntry) (Value, error) {
+	objects, ok := r.objStreams[entry.stream]
+	var dict *Dict
+	if !ok {
+		stm, ok := r.xref[entry.stream]
+		if !ok || stm.offset <= 0 {
+			return nil, fmt.Errorf("%w: object stream %d is missing", ErrMalformed, entry.stream)
+		}
+		var err error
+		if dict, objects, err = r.streamAt(stm.offset); err != nil {
+			return nil, err
+		}
+		r.objStreams[entry.stream] = objects
+	}
+
+	// The stream starts with pairs of object number and offset relative to /First
+	p := &parser{data: objects}
+	var first int
+	if dict != nil {
+		first, _ = dict.Int("First")
+	} else {
+		first = r.objStreamFirst(entry.stream)
+	}
+	for i := 0; ; i++ {
+		objNum, err1 := p.integer()
+		offset, err2 := p.integer()
+		if err1 != nil || err2 != nil || p.pos > first {
+			return nil, fmt.Errorf("%w: object %d not found in object stream %d", ErrMalformed, num, entry.stream)
+		}
+		if objNum == num {
+			p.pos = first + offset
+			return p.value()
+		}
+	}
+}
+
+func (r *Reader) objStreamFirst(num int) int {
+	p := &parser{data: r.data, pos: r.xref[num].offset}
+	if p.objectHeader(num) != nil {
+		return 0
+	}
+	v, _ := p.value()
+	if dict, ok := v.(*Dict); ok {
+		first, _ := dict.Int("First")
+		return first
+	}
+	return 0
+}
+
+// streamAt reads the stream object at offset and returns its dictionary and decoded data
+func (r *Reader) streamAt(offset int) (*Dict, []byte, error) {
+	p := &parser{data: r.data, pos: offset}
+	if err := p.objectHeader(-1); err != nil {
+		return nil, nil, err
+	}
+	v, err := p.value()
+	if err != nil {
+		return nil, nil, err
+	}
+	dict, ok := v.(*Dict)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: expected a stream at offset %d", ErrMalformed, offset)
+	}
+	p.skipSpace()
+	if !bytes.HasPrefix(r.data[p.pos:], []byte("stream")) {
+		return nil, nil, fmt.Errorf("%w: expected a stream at offset %d", ErrMalformed, offset)
+	}
+	start := p.pos + len("stream")
+	if start < len(r.data) && r.data[start] == '\r' {
+		start++
+	}
+	if start < len(r.data) && r.data[start] == '\n' {
+		start++
+	}
+
+	end := -1
+	if length, ok := dict.Int("Length"); ok && start+length <= len(r.data) &&
+		bytes.Contains(r.data[start+length:min(start+length+16, len(r.data))], []byte("endstream")) {
+		end = start + length
+	} else if i := bytes.Index(r.data[start:], []byte("endstream")); i >= 0 {
+		end = start + i
+	}
+	if end < 0 {
+		return nil, nil, fmt.Errorf("%w: unterminated stream", ErrMalformed)
+	}
+	data, err := decodeStream(dict, r.data[start:end])
+	return dict, data, err
+}
+
+// decodeStream undoes FlateDecode and the PNG predictors that cross-reference streams use
+func decodeStream(dict *Dict, data []byte) ([]byte, error) {
+	switch filter := dict.Get("Filter").(type) {
+	case nil:
+		return data, nil
+	case Name:
+		if filter != "FlateDecode" {
+			return nil, fmt.Errorf("%w: unsupported stream filter /%s", ErrMalformed, filter)
+		}
+	case Array:
+		if len(filter) != 1 || filter[0] != Name("FlateDecode") {
+			return nil, fmt.Errorf("%w: unsupported stream filters", ErrMalformed)
+		}
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unreadable stream", ErrMalformed)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(io.LimitReader(zr, maxObjectStream+1))
+	if (err != nil && err != io.ErrUnexpectedEOF) || len(out) > maxObjectStream {
+		return nil, fmt.Errorf("%w: unreadable stream", ErrMalformed)
+	}
+
+	params, _ := dict.Get("DecodeParms").(*Dict)
+	if params == nil {
+		return out, nil
+	}
+	predictor, _ := params.Int("Predictor")
+	if predictor < 10 {
+		return out, nil
+	}
+	columns, ok := params.Int("Columns")
+	if !ok {
+		columns = 1
+	}
+	return unpredictPNG(out, columns)
+}
+
+// unpredictPNG reverses PNG row filters for one-byte samples
+func unpredictPNG(data []byte, columns int) ([]byte, error) {
+	rowLen := columns + 1
+	if len(data)%rowLen != 0 {
+		return nil, fmt.Errorf("%w: bad predictor data", ErrMalformed)
+	}
+	out := make([]byte, 0, len(data)/rowLen*columns)
+	prev := make([]byte, columns)
+	for i := 0; i < len(data); i += rowLen {
+		filter, row := data[i], append([]byte(nil), data[i+1:i+rowLen]...)
+		for j := range row {
+			var left, upLeft byte
+			if j > 0 {
+				left, upLeft = row[j-1], prev[j-1]
+			}
+			up := prev[j]
+			switch filter {
+			case 1:
+				row[j] += left
+			case 2:
+				row[j] += up
+			case 3:
+				row[j] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[j] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// FirstPage walks the page tree to the first page
+func (r *Reader) FirstPage() (Ref, *Dict, error) {
+	root, err := r.Resolve(r.Trailer.Get("Root"))
+	if err != nil {
+		return Ref{}, nil, err
+	}
+	catalog, ok := root.(*Dict)
+	if !ok {
+		return Ref{}, nil, fmt.Errorf("%w: catalog is not a dictionary", ErrMalformed)
+	}
+	ref, ok := catalog.Get("Pages").(Ref)
+	for depth := 0; ok && depth < 64; depth++ {
+		v, err := r.Object(ref.Num)
+		if err != nil {
+			return Ref{}, nil, err
+		}
+		node, isDict := v.(*Dict)
+		if !isDict {
+			break
+		}
+		if t, _ := node.Get("Type").(Name); t == "Page" {
+			return ref, node, nil
+		}
+		kids, err := r.Resolve(node.Get("Kids"))
+		if err != nil {
+			return Ref{}, nil, err
+		}
+		arr, isArray := kids.(Array)
+		if !isArray || len(arr) == 0 {
+			break
+		}
+		ref, ok = arr[0].(Ref)
+	}
+	return Ref{}, nil, fmt.Errorf("%w: document has no pages", ErrMalformed)
+}
+
+// MediaBox returns a page's media box, which may be inherited from its ancestors
+func (r *Reader) MediaBox(page *Dict) ([4]float64, error) {
+	node := page
+	for depth := 0; node != nil && depth < 64; depth++ {
+		if v, err := r.Resolve(node.Get("MediaBox")); err == nil {
+			if arr, ok := v.(Array); ok && len(arr) == 4 {
+				var box [4]float64
+				for i, n := range arr {
+					raw, _ := n.(Raw)
+					box[i], _ = strconv.ParseFloat(string(raw), 64)
+				}
+				return box, nil
+			}
+		}
+		parent, err := r.Resolve(node.Get("Parent"))
+		if err != nil {
+			break
+		}
+		node, _ = parent.(*Dict)
+	}
+	return [4]float64{}, fmt.Errorf("%w: page has no media box", ErrMalformed)
+}
+
+// parser reads PDF objects from a byte slice
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\r' && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0:
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) integer() (int, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+	return strconv.Atoi(string(p.data[start:p.pos]))
+}
+
+// objectHeader consumes "num gen obj"; num -1 accepts any object number
+func (p *parser) objectHeader(num int) error {
+	n, err1 := p.integer()
+	_, err2 := p.integer()
+	p.skipSpace()
+	if err1 != nil || err2 != nil || !isKeyword(p.data, p.pos, "obj") || (num >= 0 && n != num) {
+		return fmt.Errorf("%w: expected object %d at offset %d", ErrMalformed, num, p.pos)
+	}
+	p.pos += len("obj")
+	return nil
+}
+
+func (p *parser) value() (Value, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+	start := p.pos
+	switch c := p.data[p.pos]; {
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		p.pos += 2
+		dict := NewDict()
+		for {
+			p.skipSpace()
+			if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+				p.pos += 2
+				return dict, nil
+			}
+			key, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(Name)
+			if !ok {
+				return nil, fmt.Errorf("%w: dictionary key is not a name", ErrMalformed)
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			dict.Set(string(name), v)
+		}
+	case c == '<':
+		end := bytes.IndexByte(p.data[p.pos:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated hex string", ErrMalformed)
+		}
+		p.pos += end + 1
+		return Raw(p.data[start:p.pos]), nil
+	case c == '(':
+		p.pos = skipLiteralString(p.data, p.pos)
+		return Raw(p.data[start:p.pos]), nil
+	case c == '[':
+		p.pos++
+		var arr Array
+		for {
+			p.skipSpace()
+			if p.pos < len(p.data) && p.data[p.pos] == ']' {
+				p.pos++
+				return arr, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case c == '/':
+		p.pos++
+		for p.pos < len(p.data) && !isDelimiter(p.data[p.pos]) {
+			p.pos++
+		}
+		return Name(p.data[start+1 : p.pos]), nil
+	case c >= '0' && c <= '9':
+		// A reference is two integers followed by R
+		save := p.pos
+		num, err1 := p.integer()
+		gen, err2 := p.integer()
+		p.skipSpace()
+		if err1 == nil && err2 == nil && isKeyword(p.data, p.pos, "R") {
+			p.pos++
+			return Ref{Num: num, Gen: gen}, nil
+		}
+		p.pos = save
+	}
+
+	for p.pos < len(p.data) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrMalformed, p.data[start], start)
+	}
+	return Raw(p.data[start:p.pos]), nil
+}
+
+// Serialize writes a value in PDF syntax
+func Serialize(v Value) string {
+	var buf bytes.Buffer
+	writeValue(&buf, v)
+	return buf.String()
+}
+
+func writeValue(buf *bytes.Buffer, v Value) {
+	switch v := v.(type) {
+	case *Dict:
+		buf.WriteString("<<")
+		for _, k := range v.keys {
+			buf.WriteString(" /" + k + " ")
+			writeValue(buf, v.values[k])
+		}
+		buf.WriteString(" >>")
+	case Array:
+		buf.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			writeValue(buf, item)
+		}
+		buf.WriteString("]")
+	case Name:
+		buf.WriteString("/" + string(v))
+	case Ref:
+		fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+	case Raw:
+		buf.WriteString(string(v))
+	default:
+		buf.WriteString("null")
+	}
+}
+
+// sortedNums returns the keys of an object-offset map in ascending order
+func sortedNums[V any](m map[int]V) []int {
+	nums := make([]int, 0, len(m))
+	for n := range m {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	return nums
+}
@@ -0,0 +1,299 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Errors returned by Sanitize
+var (
+	ErrMalformed     = errors.New("malformed PDF")
+	ErrEncrypted     = errors.New("encrypted PDFs cannot be inspected")
+	ErrHiddenContent = errors.New("PDF hides active content in a compressed object stream")
+)
+
+// maxObjectStream bounds how far a single compressed object stream is inflated while it is inspected
+const maxObjectStream = 32 << 20
+
+// activeNames are the dictionary keys and action types through which a PDF runs code, opens
+// other files or carries attachments when it is viewed. Triggers such as /OpenAction are left
+// alone: once the actions they point at are disarmed they can only navigate the document.
+var activeNames = map[string]bool{
+	"JavaScript":     true,
+	"JS":             true,
+	"Launch":         true,
+	"SubmitForm":     true,
+	"ImportData":     true,
+	"GoToE":          true,
+	"GoToR":          true,
+	"EmbeddedFiles":  true,
+	"EmbeddedFile":   true,
+	"FileAttachment": true,
+	"RichMedia":      true,
+	"XFA":            true,
+}
+
+var startXRef = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF`)
+
+// Sanitize checks that data is a structurally sound, unencrypted PDF and disarms its active content.
+//
+// Active keys are disarmed by flipping the case of their letters, which readers treat as unknown
+// keys. The file keeps its length and every cross-reference offset stays valid, so no rewrite is
+// needed. Anything after the final %%EOF is dropped. It returns the cleaned document and the
+// names that were disarmed.
+func Sanitize(data []byte) ([]byte, []string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-1.")) && !bytes.HasPrefix(data, []byte("%PDF-2.")) {
+		return nil, nil, fmt.Errorf("%w: missing PDF header", ErrMalformed)
+	}
+	end := bytes.LastIndex(data, []byte("%%EOF"))
+	if end < 0 {
+		return nil, nil, fmt.Errorf("%w: missing %%%%EOF marker", ErrMalformed)
+	}
+	end += len("%%EOF")
+	for end < len(data) && (data[end] == '\r' || data[end] == '\n') {
+		end++
+	}
+
+	out := make([]byte, end)
+	copy(out, data)
+
+	matches := startXRef.FindAllSubmatch(out, -1)
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%w: missing startxref", ErrMalformed)
+	}
+	offset, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil || offset <= 0 || offset >= len(out) {
+		return nil, nil, fmt.Errorf("%w: startxref points outside the file", ErrMalformed)
+	}
+	if !bytes.HasPrefix(out[offset:], []byte("xref")) && !indirectObject.Match(out[offset:min(offset+32, len(out))]) {
+		return nil, nil, fmt.Errorf("%w: startxref does not point at a cross-reference section", ErrMalformed)
+	}
+
+	s := &scanner{data: out, found: make(map[string]bool)}
+	if err := s.scan(true); err != nil {
+		return nil, nil, err
+	}
+	if s.found["Encrypt"] {
+		return nil, nil, ErrEncrypted
+	}
+	if !s.found["Root"] {
+		return nil, nil, fmt.Errorf("%w: no document catalog", ErrMalformed)
+	}
+
+	var disarmed []string
+	for name := range activeNames {
+		if s.found[name] {
+			disarmed = append(disarmed, name)
+		}
+	}
+	sort.Strings(disarmed)
+	return out, disarmed, nil
+}
+
+var (
+	indirectObject = regexp.MustCompile(`^\d+\s+\d+\s+obj`)
+	streamFilter   = regexp.MustCompile(`/Filter\s*(/[A-Za-z0-9]+|\[[^\]]*\])`)
+)
+
+// scanner walks PDF tokens, skipping comments, strings and stream data so that only real names are touched
+type scanner struct {
+	data     []byte
+	found    map[string]bool // Every name seen, active or not
+	objStart int
+}
+
+func (s *scanner) scan(disarm bool) error {
+	data := s.data
+	for i := 0; i < len(data); {
+		switch c := data[i]; {
+		case c == '%':
+			for i < len(data) && data[i] != '\r' && data[i] != '\n' {
+				i++
+			}
+		case c == '(':
+			i = skipLiteralString(data, i)
+		case c == '<' && i+1 < len(data) && data[i+1] == '<':
+			i += 2
+		case c == '<':
+			for i < len(data) && data[i] != '>' {
+				i++
+			}
+			i++
+		case c == '/':
+			next, err := s.name(i, disarm)
+			if err != nil {
+				return err
+			}
+			i = next
+		case isKeyword(data, i, "obj"):
+			s.objStart = i
+			i += len("obj")
+		case isKeyword(data, i, "stream"):
+			next, err := s.stream(i)
+			if err != nil {
+				return err
+			}
+			i = next
+		default:
+			i++
+		}
+	}
+	return nil
+}
+
+// name records the name token at i and disarms it when it is active
+func (s *scanner) name(i int, disarm bool) (int, error) {
+	start := i
+	i++
+	for i < len(s.data) && !isDelimiter(s.data[i]) {
+		i++
+	}
+	decoded := decodeName(s.data[start+1 : i])
+	s.found[decoded] = true
+	if !activeNames[decoded] {
+		return i, nil
+	}
+	if !disarm {
+		return 0, fmt.Errorf("%w: /%s", ErrHiddenContent, decoded)
+	}
+	flipNameCase(s.data[start+1 : i])
+	return i, nil
+}
+
+// stream skips the stream data starting at the keyword at i. Object streams hold ordinary objects
+// in compressed form, so they are inflated and inspected; active content there cannot be disarmed
+// in place and fails the document.
+func (s *scanner) stream(i int) (int, error) {
+	dict := s.data[s.objStart:i]
+	i += len("stream")
+	if i < len(s.data) && s.data[i] == '\r' {
+		i++
+	}
+	if i < len(s.data) && s.data[i] == '\n' {
+		i++
+	}
+	end := bytes.Index(s.data[i:], []byte("endstream"))
+	if end < 0 {
+		return 0, fmt.Errorf("%w: unterminated stream", ErrMalformed)
+	}
+	body := s.data[i : i+end]
+
+	if bytes.Contains(dict, []byte("/ObjStm")) {
+		objects, err := inflateObjectStream(dict, body)
+		if err != nil {
+			return 0, err
+		}
+		inner := &scanner{data: objects, found: s.found}
+		if err := inner.scan(false); err != nil {
+			return 0, err
+		}
+	}
+	return i + end + len("endstream"), nil
+}
+
+func inflateObjectStream(dict, body []byte) ([]byte, error) {
+	filter := streamFilter.FindSubmatch(dict)
+	if filter == nil {
+		return body, nil
+	}
+	if f := bytes.Trim(filter[1], "[] \t\r\n"); !bytes.Equal(f, []byte("/FlateDecode")) || bytes.Contains(dict, []byte("/DecodeParms")) {
+		return nil, fmt.Errorf("%w: object stream uses unsupported filters", ErrMalformed)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unreadable object stream", ErrMalformed)
+	}
+	defer zr.Close()
+	objects, err := io.ReadAll(io.LimitReader(zr, maxObjectStream+1))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: unreadable object stream", ErrMalformed)
+	}
+	if len(objects) > maxObjectStream {
+		return nil, fmt.Errorf("%w: object stream is too large", ErrMalformed)
+	}
+	return objects, nil
+}
+
+// skipLiteralString returns the index after the string starting at i, honouring nesting and escapes
+func skipLiteralString(data []byte, i int) int {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// decodeName resolves #xx escapes in a name token
+func decodeName(raw []byte) string {
+	var sb bytes.Buffer
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				sb.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(raw[i])
+	}
+	return sb.String()
+}
+
+// flipNameCase swaps the case of every letter in a name token, re-encoding escaped letters as escapes
+func flipNameCase(raw []byte) {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				copy(raw[i+1:i+3], fmt.Sprintf("%02X", flipCase(byte(v))))
+				i += 2
+				continue
+			}
+		}
+		raw[i] = flipCase(raw[i])
+	}
+}
+
+func flipCase(c byte) byte {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return c - 'a' + 'A'
+	case c >= 'A' && c <= 'Z':
+		return c - 'A' + 'a'
+	}
+	return c
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// isKeyword reports whether the keyword starts at i as a whole token
+func isKeyword(data []byte, i int, keyword string) bool {
+	if !bytes.HasPrefix(data[i:], []byte(keyword)) {
+		return false
+	}
+	if i > 0 && !isDelimiter(data[i-1]) {
+		return false
+	}
+	after := i + len(keyword)
+	return after == len(data) || isDelimiter(data[after])
+}
@@ -0,0 +1,582 @@
+//go:build cgo
+
+package pkcs11
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+// Cryptoki types, declared here so the build needs no vendor header. Unix modules use the
+// platform's natural alignment.
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+typedef struct { unsigned char major, minor; } CK_VERSION;
+typedef struct { CK_ULONG type; void *pValue; CK_ULONG ulValueLen; } CK_ATTRIBUTE;
+typedef struct { CK_ULONG mechanism; void *pParameter; CK_ULONG ulParameterLen; } CK_MECHANISM;
+typedef struct {
+	unsigned char label[32], manufacturerID[32], model[16], serialNumber[16];
+	CK_ULONG flags, maxSessionCount, sessionCount, maxRwSessionCount, rwSessionCount, maxPinLen, minPinLen;
+	CK_ULONG totalPublicMemory, freePublicMemory, totalPrivateMemory, freePrivateMemory;
+	CK_VERSION hardwareVersion, firmwareVersion;
+	unsigned char utcTime[16];
+} CK_TOKEN_INFO;
+typedef struct {
+	void *CreateMutex, *DestroyMutex, *LockMutex, *UnlockMutex;
+	CK_ULONG flags;
+	void *pReserved;
+} CK_C_INITIALIZE_ARGS;
+
+// CK_FUNCTION_LIST is a version followed by 68 function pointers in the order the standard fixes
+typedef struct { CK_VERSION version; void *fn[68]; } CK_FUNCTION_LIST;
+enum {
+	fnInitialize = 0, fnGetSlotList = 4, fnGetTokenInfo = 6, fnOpenSession = 12, fnLogin = 18,
+	fnGetAttributeValue = 24, fnFindObjectsInit = 26, fnFindObjects = 27, fnFindObjectsFinal = 28,
+	fnSignInit = 42, fnSign = 43, fnGenerateKeyPair = 59,
+};
+
+static CK_RV load(const char *path, void **handle, CK_FUNCTION_LIST **list) {
+	*handle = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (*handle == NULL) return (CK_RV)-1;
+	CK_RV (*get)(CK_FUNCTION_LIST **) = (CK_RV (*)(CK_FUNCTION_LIST **))dlsym(*handle, "C_GetFunctionList");
+	if (get == NULL) return (CK_RV)-2;
+	return get(list);
+}
+
+static CK_RV initialize(CK_FUNCTION_LIST *l) {
+	CK_C_INITIALIZE_ARGS args = {0};
+	args.flags = 2; // CKF_OS_LOCKING_OK
+	return ((CK_RV (*)(void *))l->fn[fnInitialize])(&args);
+}
+
+static CK_RV get_slot_list(CK_FUNCTION_LIST *l, CK_ULONG *slots, CK_ULONG *count) {
+	return ((CK_RV (*)(unsigned char, CK_ULONG *, CK_ULONG *))l->fn[fnGetSlotList])(1, slots, count);
+}
+
+static CK_RV get_token_info(CK_FUNCTION_LIST *l, CK_ULONG slot, CK_TOKEN_INFO *info) {
+	return ((CK_RV (*)(CK_ULONG, CK_TOKEN_INFO *))l->fn[fnGetTokenInfo])(slot, info);
+}
+
+static CK_RV open_session(CK_FUNCTION_LIST *l, CK_ULONG slot, CK_ULONG *session) {
+	// CKF_SERIAL_SESSION | CKF_RW_SESSION; read-write so keys can be generated
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, void *, void *, CK_ULONG *))l->fn[fnOpenSession])(slot, 6, NULL, NULL, session);
+}
+
+static CK_RV login(CK_FUNCTION_LIST *l, CK_ULONG session, unsigned char *pin, CK_ULONG pinLen) {
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, unsigned char *, CK_ULONG))l->fn[fnLogin])(session, 1, pin, pinLen);
+}
+
+static CK_RV get_attribute_value(CK_FUNCTION_LIST *l, CK_ULONG session, CK_ULONG object, CK_ATTRIBUTE *attrs, CK_ULONG count) {
+	return ((CK_RV (*)(CK_ULONG, CK_ULONG, CK_ATTRIBUTE *, CK_ULONG))l->fn[fnGetAttributeValue])(session, object, attrs, count);
+}
+
+static CK_RV find_objects(CK_FUNCTION_LIST *l, CK_ULONG session, CK_ATTRIBUTE *attrs, CK_ULONG count, CK_ULONG *objects, CK_ULONG max, CK_ULONG *found) {
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_ATTRIBUTE *, CK_ULONG))l->fn[fnFindObjectsInit])(session, attrs, count);
+	if (rv != 0) return rv;
+	rv = ((CK_RV (*)(CK_ULONG, CK_ULONG *, CK_ULONG, CK_ULONG *))l->fn[fnFindObjects])(session, objects, max, found);
+	CK_RV final = ((CK_RV (*)(CK_ULONG))l->fn[fnFindObjectsFinal])(session);
+	return rv != 0 ? rv : final;
+}
+
+static CK_RV sign(CK_FUNCTION_LIST *l, CK_ULONG session, CK_MECHANISM *mech, CK_ULONG key,
+		unsigned char *data, CK_ULONG dataLen, unsigned char *sig, CK_ULONG *sigLen) {
+	CK_RV rv = ((CK_RV (*)(CK_ULONG, CK_MECHANISM *, CK_ULONG))l->fn[fnSignInit])(session, mech, key);
+	if (rv != 0) return rv;
+	return ((CK_RV (*)(CK_ULONG, unsigned char *, CK_ULONG, unsigned char *, CK_ULONG *))l->fn[fnSign])(session, data, dataLen, sig, sigLen);
+}
+
+static CK_RV generate_key_pair(CK_FUNCTION_LIST *l, CK_ULONG session, CK_MECHANISM *mech,
+		CK_ATTRIBUTE *pub, CK_ULONG pubCount, CK_ATTRIBUTE *priv, CK_ULONG privCount, CK_ULONG *pubKey, CK_ULONG *privKey) {
+	return ((CK_RV (*)(CK_ULONG, CK_MECHANISM *, CK_ATTRIBUTE *, CK_ULONG, CK_ATTRIBUTE *, CK_ULONG, CK_ULONG *, CK_ULONG *))
+		l->fn[fnGenerateKeyPair])(session, mech, pub, pubCount, priv, privCount, pubKey, privKey);
+}
+*/
+import "C"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"unsafe"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Cryptoki constants used below
+const (
+	ckaClass          = 0x000
+	ckaToken          = 0x001
+	ckaPrivate        = 0x002
+	ckaLabel          = 0x003
+	ckaKeyType        = 0x100
+	ckaID             = 0x102
+	ckaSensitive      = 0x103
+	ckaSign           = 0x108
+	ckaVerify         = 0x10A
+	ckaModulus        = 0x120
+	ckaPublicExponent = 0x122
+	ckaExtractable    = 0x162
+	ckaECParams       = 0x180
+	ckaECPoint        = 0x181
+
+	ckoPublicKey  = 2
+	ckoPrivateKey = 3
+
+	ckkRSA       = 0x00
+	ckkEC        = 0x03
+	ckkECEdwards = 0x40
+
+	ckmRSAPKCS             = 0x0001
+	ckmECKeyPairGen        = 0x1040
+	ckmECDSA               = 0x1041
+	ckmECEdwardsKeyPairGen = 0x1055
+	ckmEdDSA               = 0x1057
+
+	ckrUserAlreadyLoggedIn        = 0x100
+	ckrCryptokiAlreadyInitialized = 0x191
+)
+
+var (
+	oidP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidEd25519   = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+	// DigestInfo prefix of a SHA-256 hash for RSA PKCS #1 v1.5 signatures
+	sha256DigestInfo = []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+)
+
+// Error is a Cryptoki return value other than CKR_OK
+type Error uint64
+
+var errorNames = map[Error]string{
+	0x005: "CKR_GENERAL_ERROR",
+	0x006: "CKR_FUNCTION_FAILED",
+	0x007: "CKR_ARGUMENTS_BAD",
+	0x012: "CKR_ATTRIBUTE_TYPE_INVALID",
+	0x030: "CKR_DEVICE_ERROR",
+	0x032: "CKR_DEVICE_REMOVED",
+	0x063: "CKR_KEY_TYPE_INCONSISTENT",
+	0x068: "CKR_KEY_FUNCTION_NOT_PERMITTED",
+	0x070: "CKR_MECHANISM_INVALID",
+	0x0A0: "CKR_PIN_INCORRECT",
+	0x0A4: "CKR_PIN_LOCKED",
+	0x0B3: "CKR_SESSION_HANDLE_INVALID",
+	0x0D1: "CKR_TEMPLATE_INCONSISTENT",
+	0x0E0: "CKR_TOKEN_NOT_PRESENT",
+	0x101: "CKR_USER_NOT_LOGGED_IN",
+	0x150: "CKR_BUFFER_TOO_SMALL",
+	0x190: "CKR_CRYPTOKI_NOT_INITIALIZED",
+}
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e]; ok {
+		return "PKCS #11 error " + name
+	}
+	return fmt.Sprintf("PKCS #11 error 0x%X", uint64(e))
+}
+
+func check(rv C.CK_RV) error {
+	if rv != 0 {
+		return Error(rv)
+	}
+	return nil
+}
+
+// A module may only be initialized once per process, so modules are shared by path
+var (
+	modulesMu sync.Mutex
+	modules   = map[string]*Module{}
+)
+
+// Module is a loaded PKCS #11 library
+type Module struct {
+	path     string
+	list     *C.CK_FUNCTION_LIST
+	mu       sync.Mutex
+	sessions map[string]*Session // By token label
+}
+
+// Open loads and initializes the module at path
+func Open(path string) (*Module, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if m, ok := modules[path]; ok {
+		return m, nil
+	}
+
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var handle unsafe.Pointer
+	var list *C.CK_FUNCTION_LIST
+	switch rv := C.load(cpath, &handle, &list); rv {
+	case 0:
+	case ^C.CK_RV(0):
+		return nil, fmt.Errorf("failed to load PKCS #11 module: %s", C.GoString(C.dlerror()))
+	case ^C.CK_RV(1):
+		return nil, fmt.Errorf("%s is not a PKCS #11 module", path)
+	default:
+		return nil, fmt.Errorf("failed to load PKCS #11 module: %w", Error(rv))
+	}
+	if rv := C.initialize(list); rv != 0 && rv != ckrCryptokiAlreadyInitialized {
+		return nil, fmt.Errorf("failed to initialize PKCS #11 module: %w", Error(rv))
+	}
+
+	m := &Module{path: path, list: list, sessions: map[string]*Session{}}
+	modules[path] = m
+	return m, nil
+}
+
+// Login opens a session on the token with the given label and logs in as the user. Sessions are
+// reused, since logging in to a token applies to every session the process has on it.
+func (m *Module) Login(token, pin string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[token]; ok {
+		return s, nil
+	}
+
+	slot, err := m.findSlot(token)
+	if err != nil {
+		return nil, err
+	}
+	var handle C.CK_ULONG
+	if err := check(C.open_session(m.list, slot, &handle)); err != nil {
+		return nil, fmt.Errorf("failed to open session on token %q: %w", token, err)
+	}
+	cpin := C.CBytes([]byte(pin))
+	defer C.free(cpin)
+	if rv := C.login(m.list, handle, (*C.uchar)(cpin), C.CK_ULONG(len(pin))); rv != 0 && rv != ckrUserAlreadyLoggedIn {
+		return nil, fmt.Errorf("failed to log in to token %q: %w", token, Error(rv))
+	}
+
+	s := &Session{m: m, handle: handle, Token: token}
+	m.sessions[token] = s
+	return s, nil
+}
+
+func (m *Module) findSlot(token string) (C.CK_ULONG, error) {
+	var count C.CK_ULONG
+	if err := check(C.get_slot_list(m.list, nil, &count)); err != nil {
+		return 0, fmt.Errorf("failed to list PKCS #11 slots: %w", err)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no PKCS #11 token present in %s", m.path)
+	}
+	slots := make([]C.CK_ULONG, count)
+	if err := check(C.get_slot_list(m.list, &slots[0], &count)); err != nil {
+		return 0, fmt.Errorf("failed to list PKCS #11 slots: %w", err)
+	}
+
+	var labels []string
+	for _, slot := range slots[:count] {
+		var info C.CK_TOKEN_INFO
+		if check(C.get_token_info(m.list, slot, &info)) != nil {
+			continue
+		}
+		// Labels are blank padded to 32 bytes
+		label := strings.TrimRight(C.GoStringN((*C.char)(unsafe.Pointer(&info.label[0])), 32), " \x00")
+		if label == token {
+			return slot, nil
+		}
+		labels = append(labels, label)
+	}
+	return 0, fmt.Errorf("no PKCS #11 token labelled %q (found %q)", token, labels)
+}
+
+// Session is a logged-in session on one token. Cryptoki sessions are not safe for concurrent
+// use, so calls are serialized.
+type Session struct {
+	mu     sync.Mutex
+	m      *Module
+	handle C.CK_ULONG
+	Token  string
+}
+
+// FindKey looks up a private key by label and, when id is set, CKA_ID, along with its public key
+func (s *Session) FindKey(label string, id []byte) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match := func(class uint) []attribute {
+		attrs := []attribute{{ckaClass, class}}
+		if label != "" {
+			attrs = append(attrs, attribute{ckaLabel, []byte(label)})
+		}
+		if id != nil {
+			attrs = append(attrs, attribute{ckaID, id})
+		}
+		return attrs
+	}
+	priv, err := s.findOne(match(ckoPrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	pubHandle, err := s.findOne(match(ckoPublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("public key of %q: %w", label, err)
+	}
+	pub, err := s.publicKey(pubHandle)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{s: s, handle: priv, public: pub, Label: label, ID: id}, nil
+}
+
+func (s *Session) findOne(template []attribute) (C.CK_ULONG, error) {
+	attrs, free := newTemplate(template)
+	defer free()
+	var objects [2]C.CK_ULONG
+	var found C.CK_ULONG
+	if err := check(C.find_objects(s.m.list, s.handle, attrs, C.CK_ULONG(len(template)), &objects[0], 2, &found)); err != nil {
+		return 0, fmt.Errorf("failed to search token %q: %w", s.Token, err)
+	}
+	switch found {
+	case 0:
+		return 0, ErrKeyNotFound
+	case 1:
+		return objects[0], nil
+	}
+	return 0, fmt.Errorf("several keys on token %q match; add an id to the URI", s.Token)
+}
+
+// publicKey reads the public half of a key pair from its public key object
+func (s *Session) publicKey(object C.CK_ULONG) (crypto.PublicKey, error) {
+	values, err := s.attributes(object, ckaKeyType)
+	if err != nil {
+		return nil, err
+	}
+	switch keyType := readULong(values[0]); keyType {
+	case ckkRSA:
+		values, err := s.attributes(object, ckaModulus, ckaPublicExponent)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(values[0]), E: int(new(big.Int).SetBytes(values[1]).Int64())}, nil
+	case ckkEC, ckkECEdwards:
+		values, err := s.attributes(object, ckaECParams, ckaECPoint)
+		if err != nil {
+			return nil, err
+		}
+		return parseECPublicKey(values[0], values[1])
+	default:
+		return nil, fmt.Errorf("unsupported PKCS #11 key type 0x%X", keyType)
+	}
+}
+
+// attributes reads attribute values, asking the token for their lengths first
+func (s *Session) attributes(object C.CK_ULONG, types ...uint) ([][]byte, error) {
+	n := len(types)
+	attrs := (*[1 << 20]C.CK_ATTRIBUTE)(C.calloc(C.size_t(n), C.size_t(unsafe.Sizeof(C.CK_ATTRIBUTE{}))))[:n:n]
+	defer C.free(unsafe.Pointer(&attrs[0]))
+	for i, t := range types {
+		attrs[i]._type = C.CK_ULONG(t)
+	}
+	if err := check(C.get_attribute_value(s.m.list, s.handle, object, &attrs[0], C.CK_ULONG(n))); err != nil {
+		return nil, fmt.Errorf("failed to read key attributes: %w", err)
+	}
+	for i := range attrs {
+		attrs[i].pValue = C.malloc(C.size_t(attrs[i].ulValueLen) + 1)
+		defer C.free(attrs[i].pValue)
+	}
+	if err := check(C.get_attribute_value(s.m.list, s.handle, object, &attrs[0], C.CK_ULONG(n))); err != nil {
+		return nil, fmt.Errorf("failed to read key attributes: %w", err)
+	}
+	values := make([][]byte, n)
+	for i := range attrs {
+		values[i] = C.GoBytes(attrs[i].pValue, C.int(attrs[i].ulValueLen))
+	}
+	return values, nil
+}
+
+// GenerateKey creates a persistent, non-extractable key pair on the token
+func (s *Session) GenerateKey(label string, id []byte, keyType KeyType) (*Key, error) {
+	var mechanism C.CK_ULONG = ckmECKeyPairGen
+	var params []byte
+	switch keyType {
+	case KeyP256:
+		params, _ = asn1.Marshal(oidP256)
+	case KeySecp256k1:
+		params, _ = asn1.Marshal(oidSecp256k1)
+	case KeyEd25519:
+		mechanism = ckmECEdwardsKeyPairGen
+		params, _ = asn1.Marshal(oidEd25519)
+	default:
+		return nil, fmt.Errorf("unsupported key type %v", keyType)
+	}
+
+	pubTemplate, freePub := newTemplate([]attribute{
+		{ckaToken, true}, {ckaVerify, true}, {ckaECParams, params}, {ckaLabel, []byte(label)}, {ckaID, id},
+	})
+	defer freePub()
+	privTemplate, freePriv := newTemplate([]attribute{
+		{ckaToken, true}, {ckaPrivate, true}, {ckaSensitive, true}, {ckaExtractable, false}, {ckaSign, true},
+		{ckaLabel, []byte(label)}, {ckaID, id},
+	})
+	defer freePriv()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mech := C.CK_MECHANISM{mechanism: mechanism}
+	var pubHandle, privHandle C.CK_ULONG
+	if err := check(C.generate_key_pair(s.m.list, s.handle, &mech, pubTemplate, 5, privTemplate, 7, &pubHandle, &privHandle)); err != nil {
+		return nil, fmt.Errorf("failed to generate %v key on token %q: %w", keyType, s.Token, err)
+	}
+	pub, err := s.publicKey(pubHandle)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{s: s, handle: privHandle, public: pub, Label: label, ID: id}, nil
+}
+
+// Key is a private key held on a token
+type Key struct {
+	s      *Session
+	handle C.CK_ULONG
+	public crypto.PublicKey
+	Label  string
+	ID     []byte
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return k.public
+}
+
+// Sign follows the crypto.Signer conventions: ECDSA signatures are ASN.1 encoded, Ed25519 signs
+// the message itself and RSA uses PKCS #1 v1.5 over a SHA-256 digest
+func (k *Key) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mechanism C.CK_ULONG
+	data := digest
+	switch k.public.(type) {
+	case *ecdsa.PublicKey:
+		mechanism = ckmECDSA
+	case ed25519.PublicKey:
+		if opts.HashFunc() != 0 {
+			return nil, errors.New("Ed25519 keys sign the message, not a digest")
+		}
+		mechanism = ckmEdDSA
+	case *rsa.PublicKey:
+		if _, pss := opts.(*rsa.PSSOptions); pss || opts.HashFunc() != crypto.SHA256 {
+			return nil, errors.New("RSA keys on PKCS #11 tokens only sign SHA-256 digests with PKCS #1 v1.5")
+		}
+		mechanism = ckmRSAPKCS
+		data = append(append([]byte{}, sha256DigestInfo...), digest...)
+	}
+
+	k.s.mu.Lock()
+	defer k.s.mu.Unlock()
+	mech := C.CK_MECHANISM{mechanism: mechanism}
+	in := C.CBytes(data)
+	defer C.free(in)
+	// 512 bytes covers RSA-4096 and every EC curve
+	out := C.malloc(512)
+	defer C.free(out)
+	outLen := C.CK_ULONG(512)
+	if err := check(C.sign(k.s.m.list, k.s.handle, &mech, k.handle, (*C.uchar)(in), C.CK_ULONG(len(data)), (*C.uchar)(out), &outLen)); err != nil {
+		return nil, fmt.Errorf("failed to sign with %q on token %q: %w", k.Label, k.s.Token, err)
+	}
+	sig := C.GoBytes(out, C.int(outLen))
+
+	if mechanism != ckmECDSA {
+		return sig, nil
+	}
+	// CKM_ECDSA returns r || s, each the size of the curve order
+	if len(sig)%2 != 0 {
+		return nil, fmt.Errorf("malformed ECDSA signature from token %q", k.s.Token)
+	}
+	half := len(sig) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:half]), new(big.Int).SetBytes(sig[half:])})
+}
+
+// parseECPublicKey decodes CKA_EC_PARAMS and CKA_EC_POINT. The point is normally wrapped in an
+// OCTET STRING, though some tokens return it bare.
+func parseECPublicKey(params, point []byte) (crypto.PublicKey, error) {
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) > 0 {
+		raw = point
+	}
+
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		// Ed25519 keys may be identified by the curve name as a PrintableString
+		var name string
+		if _, err := asn1.Unmarshal(params, &name); err != nil || name != "edwards25519" {
+			return nil, fmt.Errorf("unsupported EC parameters on token")
+		}
+		oid = oidEd25519
+	}
+
+	var curve elliptic.Curve
+	switch {
+	case oid.Equal(oidEd25519):
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("malformed Ed25519 public key on token")
+		}
+		return ed25519.PublicKey(raw), nil
+	case oid.Equal(oidP256):
+		curve = elliptic.P256()
+	case oid.Equal(oidSecp256k1):
+		curve = ethcrypto.S256()
+	default:
+		return nil, fmt.Errorf("unsupported curve %v on token", oid)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(raw) != 1+2*size || raw[0] != 4 {
+		return nil, fmt.Errorf("malformed EC point on token")
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(raw[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(raw[1+size:]),
+	}, nil
+}
+
+// attribute is a template entry; values are uint (CK_ULONG), bool (CK_BBOOL) or []byte
+type attribute struct {
+	typ   uint
+	value interface{}
+}
+
+// newTemplate copies attributes into C memory, since cgo may not hand C a Go array of pointers
+func newTemplate(template []attribute) (*C.CK_ATTRIBUTE, func()) {
+	n := len(template)
+	attrs := (*[1 << 20]C.CK_ATTRIBUTE)(C.calloc(C.size_t(n), C.size_t(unsafe.Sizeof(C.CK_ATTRIBUTE{}))))[:n:n]
+	for i, a := range template {
+		attrs[i]._type = C.CK_ULONG(a.typ)
+		switch v := a.value.(type) {
+		case uint:
+			p := (*C.CK_ULONG)(C.malloc(C.size_t(unsafe.Sizeof(C.CK_ULONG(0)))))
+			*p = C.CK_ULONG(v)
+			attrs[i].pValue, attrs[i].ulValueLen = unsafe.Pointer(p), C.CK_ULONG(unsafe.Sizeof(*p))
+		case bool:
+			b := []byte{0}
+			if v {
+				b[0] = 1
+			}
+			attrs[i].pValue, attrs[i].ulValueLen = C.CBytes(b), 1
+		case []byte:
+			attrs[i].pValue, attrs[i].ulValueLen = C.CBytes(v), C.CK_ULONG(len(v))
+		}
+	}
+	return &attrs[0], func() {
+		for i := range attrs {
+			C.free(attrs[i].pValue)
+		}
+		C.free(unsafe.Pointer(&attrs[0]))
+	}
+}
+
+func readULong(b []byte) uint64 {
+	if len(b) != int(unsafe.Sizeof(C.CK_ULONG(0))) {
+		return ^uint64(0)
+	}
+	return uint64(*(*C.CK_ULONG)(unsafe.Pointer(&b[0])))
+}
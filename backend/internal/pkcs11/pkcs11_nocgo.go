@@ -0,0 +1,47 @@
+//go:build !cgo
+
+package pkcs11
+
+import (
+	"crypto"
+	"io"
+)
+
+// Module is a loaded PKCS #11 library
+type Module struct{}
+
+// Open always fails without cgo
+func Open(path string) (*Module, error) {
+	return nil, ErrUnavailable
+}
+
+func (m *Module) Login(token, pin string) (*Session, error) {
+	return nil, ErrUnavailable
+}
+
+// Session is a logged-in session on one token
+type Session struct {
+	Token string
+}
+
+func (s *Session) FindKey(label string, id []byte) (*Key, error) {
+	return nil, ErrUnavailable
+}
+
+func (s *Session) GenerateKey(label string, id []byte, keyType KeyType) (*Key, error) {
+	return nil, ErrUnavailable
+}
+
+// Key is a private key held on a token
+type Key struct {
+	Label string
+	ID    []byte
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return nil
+}
+
+func (k *Key) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return nil, ErrUnavailable
+}
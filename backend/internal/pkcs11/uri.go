@@ -0,0 +1,126 @@
+// Package pkcs11 signs with keys held on PKCS #11 tokens such as HSMs, smart cards and SoftHSM.
+//
+// It loads the vendor module with dlopen and speaks the small part of the Cryptoki API needed to
+// find, generate and use signing keys: EC keys on P-256 and secp256k1, Ed25519 and RSA. The
+// private key never leaves the token; Key implements crypto.Signer on top of C_Sign.
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrUnavailable is returned when the binary was built without cgo
+var ErrUnavailable = errors.New("PKCS #11 support requires a build with cgo enabled")
+
+// ErrKeyNotFound is returned when no private key on the token matches the URI
+var ErrKeyNotFound = errors.New("PKCS #11 key not found")
+
+// URI identifies a key with the subset of RFC 7512 used here
+type URI struct {
+	Token      string // Token label
+	Object     string // Key label
+	ID         []byte // CKA_ID, when keys share a label
+	PIN        string // pin-value query attribute
+	ModulePath string // module-path query attribute
+}
+
+// ParseURI parses a pkcs11: URI such as pkcs11:token=BlockCred;object=vc-key-1?pin-value=1234
+func ParseURI(s string) (URI, error) {
+	if !strings.HasPrefix(s, "pkcs11:") {
+		return URI{}, fmt.Errorf("PKCS #11 URI must start with pkcs11:")
+	}
+	path, query, _ := strings.Cut(strings.TrimPrefix(s, "pkcs11:"), "?")
+
+	var u URI
+	for _, attr := range strings.Split(path, ";") {
+		if attr == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(attr, "=")
+		if !ok {
+			return URI{}, fmt.Errorf("malformed PKCS #11 URI attribute %q", attr)
+		}
+		value, err := url.PathUnescape(raw)
+		if err != nil {
+			return URI{}, fmt.Errorf("malformed PKCS #11 URI attribute %q: %w", attr, err)
+		}
+		switch name {
+		case "token":
+			u.Token = value
+		case "object":
+			u.Object = value
+		case "id":
+			u.ID = []byte(value)
+		}
+	}
+	for _, attr := range strings.Split(query, "&") {
+		name, raw, _ := strings.Cut(attr, "=")
+		value, err := url.QueryUnescape(raw)
+		if err != nil {
+			return URI{}, fmt.Errorf("malformed PKCS #11 URI query %q: %w", attr, err)
+		}
+		switch name {
+		case "pin-value":
+			u.PIN = value
+		case "module-path":
+			u.ModulePath = value
+		}
+	}
+	if u.Token == "" || (u.Object == "" && u.ID == nil) {
+		return URI{}, fmt.Errorf("PKCS #11 URI must name a token and an object or id")
+	}
+	return u, nil
+}
+
+// String formats the URI without its PIN, for logs and the key inventory
+func (u URI) String() string {
+	s := "pkcs11:token=" + escapeURI(u.Token)
+	if u.Object != "" {
+		s += ";object=" + escapeURI(u.Object)
+	}
+	if u.ID != nil {
+		s += ";id=" + escapeURI(string(u.ID))
+	}
+	if u.ModulePath != "" {
+		s += "?module-path=" + url.QueryEscape(u.ModulePath)
+	}
+	return s
+}
+
+// escapeURI percent-encodes everything but the unreserved characters RFC 7512 allows in values
+func escapeURI(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("-._~:[]@!$'()*+,&", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// KeyType selects the algorithm of generated keys
+type KeyType int
+
+const (
+	KeyP256 KeyType = iota
+	KeySecp256k1
+	KeyEd25519
+)
+
+func (t KeyType) String() string {
+	switch t {
+	case KeyP256:
+		return "ECDSA P-256"
+	case KeySecp256k1:
+		return "ECDSA secp256k1"
+	case KeyEd25519:
+		return "Ed25519"
+	}
+	return "unknown"
+}
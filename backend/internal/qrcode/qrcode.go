@@ -0,0 +1,227 @@
+// Package qrcode implements a small QR Code (ISO/IEC 18004) encoder.
+//
+// It supports byte-mode payloads at error correction level M for versions 1-10
+// (up to 213 bytes), which comfortably covers verification URLs.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooLong is returned when the payload does not fit in the largest supported version
+var ErrTooLong = errors.New("qrcode: data too long")
+
+// Code is an encoded QR symbol; Size is the number of modules per side (without quiet zone)
+type Code struct {
+	Size    int
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x, row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// blockSpec describes the level-M block structure of one version
+type blockSpec struct {
+	ecPerBlock int
+	groups     [][2]int // {block count, data codewords per block}
+}
+
+var versionsM = [...]blockSpec{
+	1:  {10, [][2]int{{1, 16}}},
+	2:  {16, [][2]int{{1, 28}}},
+	3:  {26, [][2]int{{1, 44}}},
+	4:  {18, [][2]int{{2, 32}}},
+	5:  {24, [][2]int{{2, 43}}},
+	6:  {16, [][2]int{{4, 27}}},
+	7:  {18, [][2]int{{4, 31}}},
+	8:  {22, [][2]int{{2, 38}, {2, 39}}},
+	9:  {22, [][2]int{{3, 36}, {2, 37}}},
+	10: {26, [][2]int{{4, 43}, {1, 44}}},
+}
+
+var alignmentPositions = [...][]int{
+	1:  nil,
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+func (b blockSpec) dataCodewords() int {
+	n := 0
+	for _, g := range b.groups {
+		n += g[0] * g[1]
+	}
+	return n
+}
+
+// Encode builds the smallest QR code that holds data
+func Encode(data []byte) (*Code, error) {
+	version := 0
+	for v := 1; v < len(versionsM); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= versionsM[v].dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLong, len(data))
+	}
+
+	codewords := addErrorCorrection(encodeData(data, version), versionsM[version])
+
+	q := newSymbol(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			bestMask, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // XOR again to undo
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+
+	return &Code{Size: q.size, modules: q.modules}, nil
+}
+
+// encodeData produces the padded data codewords in byte mode
+func encodeData(data []byte, version int) []byte {
+	capacity := versionsM[version].dataCodewords()
+	var bb bitBuffer
+	bb.append(0x4, 4) // Byte mode
+	if version >= 10 {
+		bb.append(uint32(len(data)), 16)
+	} else {
+		bb.append(uint32(len(data)), 8)
+	}
+	for _, b := range data {
+		bb.append(uint32(b), 8)
+	}
+
+	// Terminator and byte alignment
+	terminator := capacity*8 - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	if rem := len(bb) % 8; rem != 0 {
+		bb.append(0, 8-rem)
+	}
+	for pad := uint32(0xEC); len(bb) < capacity*8; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	return bb.bytes()
+}
+
+// addErrorCorrection splits data into blocks, appends Reed-Solomon codewords and interleaves them
+func addErrorCorrection(data []byte, spec blockSpec) []byte {
+	divisor := rsDivisor(spec.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for _, g := range spec.groups {
+		for i := 0; i < g[0]; i++ {
+			block := data[offset : offset+g[1]]
+			offset += g[1]
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+		}
+	}
+
+	maxData := 0
+	for _, b := range dataBlocks {
+		if len(b) > maxData {
+			maxData = len(b)
+		}
+	}
+
+	result := make([]byte, 0, len(data)+len(dataBlocks)*spec.ecPerBlock)
+	for i := 0; i < maxData; i++ {
+		for _, b := range dataBlocks {
+			if i < len(b) {
+				result = append(result, b[i])
+			}
+		}
+	}
+	for i := 0; i < spec.ecPerBlock; i++ {
+		for _, b := range ecBlocks {
+			result = append(result, b[i])
+		}
+	}
+	return result
+}
+
+// Reed-Solomon over GF(2^8) with the QR primitive polynomial 0x11D
+
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(value uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (value>>uint(i))&1 == 1)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	out := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			out[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return out
+}
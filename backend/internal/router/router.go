@@ -3,6 +3,7 @@ package router
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -10,6 +11,7 @@ import (
 	"blockcred-backend/internal/config"
 	handlerspkg "blockcred-backend/internal/http/handlers"
 	"blockcred-backend/internal/http/middleware"
+	"blockcred-backend/internal/models"
 	"blockcred-backend/internal/services"
 	"blockcred-backend/internal/store"
 )
@@ -28,15 +30,30 @@ func New(cfg config.Config) http.Handler {
 		log.Printf("✅ Connected to MongoDB")
 	}
 
-	authSvc := services.NewAuthService(st)
+	// Signing keys: credential keys live with the DID, the rest in the key manager's inventory
+	institutionKeys, err := services.NewInstitutionKeys(cfg, st)
+	if err != nil {
+		log.Fatalf("❌ Failed to load institution signing key: %v", err)
+	}
+	keyManager, err := services.NewKeyManager(cfg, st, institutionKeys)
+	if err != nil {
+		log.Fatalf("❌ Failed to load signing keys: %v", err)
+	}
+	tokenIssuer := services.NewTokenIssuer(cfg, keyManager.Key(models.KeyPurposeTokens))
+
+	authSvc := services.NewAuthService(st, tokenIssuer)
 	userSvc := services.NewUserService(st)
 	credSvc := services.NewCredentialService(st)
 	
-	// Initialize IPFS and Blockchain services
-	ipfsService := services.NewIPFSService(cfg)
+	// Initialize content storage and Blockchain services
+	contentStore, err := services.NewContentStore(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize content store: %v", err)
+	}
+	log.Printf("✅ Using %s content store", contentStore.Name())
 	// Try Besu blockchain service first, then GoEth, then mock
 	var blockchainService services.BlockchainServiceInterface
-	besuService, err := services.NewBesuBlockchainService(cfg)
+	besuService, err := services.NewBesuBlockchainService(cfg, keyManager.Key(models.KeyPurposeBlockchain))
 	if err != nil {
 		log.Printf("⚠️  Besu blockchain service initialization failed: %v", err)
 		log.Printf("🔄 Trying GoEth blockchain service...")
@@ -61,21 +78,98 @@ func New(cfg config.Config) http.Handler {
 		log.Printf("✅ Using Besu blockchain service")
 	}
 	
-	certSvc := services.NewCertificateService(st, ipfsService, blockchainService)
-	authMiddleware := middleware.NewAuthMiddleware(st)
+	documentCipher, err := services.NewDocumentCipher(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to load document encryption key: %v", err)
+	}
+	pinTracker, err := services.NewPinTracker(cfg, st, contentStore)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure replica stores: %v", err)
+	}
+	if cfg.PinCheckMinutes > 0 {
+		// Periodically confirm every file is still pinned and repair lost pins from a replica
+		go func() {
+			for range time.Tick(time.Duration(cfg.PinCheckMinutes) * time.Minute) {
+				pinTracker.Sweep()
+			}
+		}()
+	}
+	uploadReceiver, err := services.NewUploadReceiver(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure certificate uploads: %v", err)
+	}
+	documentValidator, err := services.NewDocumentValidator(cfg, st)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure document checks: %v", err)
+	}
+	pdfSigner, err := services.NewPDFSigner(cfg, keyManager.Key(models.KeyPurposeDocuments))
+	if err != nil {
+		log.Fatalf("❌ Failed to configure PDF signing: %v", err)
+	}
+	certSvc := services.NewCertificateService(cfg, st, contentStore, documentCipher, documentValidator, pdfSigner, pinTracker, blockchainService)
+	if blockchainService != nil {
+		// Periodically lift suspensions that have reached their reinstatement date and complete
+		// reissues whose supersession did not reach the chain
+		go func() {
+			for range time.Tick(15 * time.Minute) {
+				certSvc.ReinstateDueSuspensions()
+				certSvc.CompletePendingSupersessions()
+			}
+		}()
+	}
+	approvalSvc := services.NewApprovalService(cfg, st, certSvc)
+	bulkSvc := services.NewBulkIssuanceService(cfg, st, certSvc, approvalSvc)
+	templateSvc := services.NewTemplateService(cfg, st, certSvc, approvalSvc)
+	vcSvc := services.NewVCService(cfg, st, certSvc, institutionKeys)
+	didSvc := services.NewDIDService(cfg, st, institutionKeys)
+	badgeSvc := services.NewBadgeService(cfg, st, vcSvc, institutionKeys)
+	blockcertsSvc := services.NewBlockcertsService(cfg, st, vcSvc, blockchainService, institutionKeys)
+	disclosureSvc := services.NewSelectiveDisclosureService(st, vcSvc, blockchainService)
+	shareSvc := services.NewShareService(cfg, st, certSvc)
+	accessSvc := services.NewAccessRequestService(st, certSvc)
+	transcriptSvc := services.NewTranscriptService(cfg, st, certSvc, approvalSvc)
+	eligibilitySvc := services.NewEligibilityService(st)
+	contentMigrationSvc := services.NewContentMigrationService(cfg, st, contentStore)
+	authMiddleware := middleware.NewAuthMiddleware(st, tokenIssuer)
+	// Idempotent bodies may carry the document base64-encoded in JSON, which is 4/3 of its size,
+	// plus 1 MB for the other fields
+	idempotency := middleware.NewIdempotencyMiddleware(st, time.Duration(cfg.IdempotencyHours)*time.Hour, (cfg.MaxUploadMB<<20)*4/3+1<<20)
+	clientIPs, err := middleware.NewClientIPMiddleware(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ Failed to parse TRUSTED_PROXIES: %v", err)
+	}
 
 	auth := &handlerspkg.AuthHandler{Auth: authSvc}
 	users := &handlerspkg.UserHandler{Users: userSvc}
 	credentials := &handlerspkg.CredentialHandler{Credentials: credSvc}
-	certificates := &handlerspkg.CertificateHandler{Certificates: certSvc}
+	certificates := &handlerspkg.CertificateHandler{Certificates: certSvc, Approvals: approvalSvc, Uploads: uploadReceiver}
+	approvals := &handlerspkg.ApprovalHandler{Approvals: approvalSvc}
+	bulk := &handlerspkg.BulkHandler{Bulk: bulkSvc, MaxUploadBytes: cfg.BulkMaxUploadMB << 20}
+	templates := &handlerspkg.TemplateHandler{Templates: templateSvc}
+	vc := &handlerspkg.VCHandler{VC: vcSvc}
+	dids := &handlerspkg.DIDHandler{DIDs: didSvc}
+	signingKeys := &handlerspkg.KeyHandler{Keys: keyManager}
+	badges := &handlerspkg.BadgeHandler{Badges: badgeSvc}
+	blockcertsHandler := &handlerspkg.BlockcertsHandler{Blockcerts: blockcertsSvc}
+	disclosures := &handlerspkg.DisclosureHandler{Disclosures: disclosureSvc}
+	shares := &handlerspkg.ShareHandler{Shares: shareSvc}
+	accessRequests := &handlerspkg.AccessRequestHandler{Requests: accessSvc}
+	transcripts := &handlerspkg.TranscriptHandler{Transcripts: transcriptSvc}
+	eligibility := &handlerspkg.EligibilityHandler{Eligibility: eligibilitySvc}
+	quarantine := &handlerspkg.QuarantineHandler{Documents: documentValidator}
 
 	r := mux.NewRouter()
+	r.Use(clientIPs.Handler)
 
 	// Health check
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	}).Methods("GET")
+	r.HandleFunc("/health/dependencies", handlerspkg.DependencyHealth).Methods("GET")
+
+	// did:web resolution for the institution identifier
+	r.HandleFunc("/.well-known/did.json", dids.WellKnownDID).Methods("GET")
 
 	api := r.PathPrefix("/api").Subrouter()
 
@@ -98,27 +192,126 @@ func New(cfg config.Config) http.Handler {
 	api.HandleFunc("/admin/users/{id}", authMiddleware.RequireAuth(users.DeleteUser)).Methods("DELETE")
 	
 	// Certificate endpoints
-	api.HandleFunc("/certificates/issue", authMiddleware.RequireAuth(certificates.IssueCertificate)).Methods("POST")
-	api.HandleFunc("/certificates/verify/{cert_id}", certificates.VerifyCertificate).Methods("GET")
+	api.HandleFunc("/certificates/issue", authMiddleware.RequireAuth(idempotency.Idempotent(certificates.IssueCertificate))).Methods("POST")
+	api.HandleFunc("/certificates/generate", authMiddleware.RequireAuth(idempotency.Idempotent(templates.GenerateCertificate))).Methods("POST")
+	api.HandleFunc("/certificates/bulk", authMiddleware.RequireAuth(bulk.Upload)).Methods("POST")
+	api.HandleFunc("/certificates/bulk", authMiddleware.RequireAuth(bulk.ListJobs)).Methods("GET")
+	api.HandleFunc("/certificates/bulk/{id}", authMiddleware.RequireAuth(bulk.GetJob)).Methods("GET")
+	api.HandleFunc("/certificates/bulk/{id}/resume", authMiddleware.RequireAuth(bulk.Resume)).Methods("POST")
+	api.HandleFunc("/certificates/bulk/{id}/result", authMiddleware.RequireAuth(bulk.DownloadResult)).Methods("GET")
+	api.HandleFunc("/certificates/verify/{cert_id}", authMiddleware.OptionalAuth(certificates.VerifyCertificate)).Methods("GET")
 	api.HandleFunc("/certificates", authMiddleware.RequireAuth(certificates.ListCertificates)).Methods("GET")
 	api.HandleFunc("/certificates/student/{student_id}", authMiddleware.RequireAuth(certificates.ListCertificatesByStudent)).Methods("GET")
 	api.HandleFunc("/certificates/issuer", authMiddleware.RequireAuth(certificates.ListCertificatesByIssuer)).Methods("GET")
 	api.HandleFunc("/certificates/{cert_id}/revoke", authMiddleware.RequireAuth(certificates.RevokeCertificate)).Methods("POST")
+	api.HandleFunc("/certificates/{cert_id}/reissue", authMiddleware.RequireAuth(idempotency.Idempotent(certificates.ReissueCertificate))).Methods("POST")
+	api.HandleFunc("/certificates/{cert_id}/suspend", authMiddleware.RequireAuth(certificates.SuspendCertificate)).Methods("POST")
+	api.HandleFunc("/certificates/{cert_id}/reinstate", authMiddleware.RequireAuth(certificates.ReinstateCertificate)).Methods("POST")
+	api.HandleFunc("/certificates/{cert_id}/document", authMiddleware.RequireAuth(certificates.DownloadDocument)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/pins", authMiddleware.RequireAuth(certificates.PinStatus)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/vc", authMiddleware.RequireAuth(vc.ExportCredential)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/badge", authMiddleware.RequireAuth(badges.ExportBadge)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/badge/image", authMiddleware.RequireAuth(badges.DownloadBakedBadge)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/blockcerts", authMiddleware.RequireAuth(blockcertsHandler.ExportDocument)).Methods("GET")
+	api.HandleFunc("/certificates/{cert_id}/presentations", authMiddleware.RequireAuth(disclosures.CreatePresentation)).Methods("POST")
 	api.HandleFunc("/certificates/test-ipfs", certificates.TestIPFS).Methods("GET")
 
+	// W3C Verifiable Credentials
+	api.HandleFunc("/vc/verify", vc.VerifyCredential).Methods("POST")
+
+	// Student share links
+	api.HandleFunc("/share-links", authMiddleware.RequireAuth(shares.CreateLink)).Methods("POST")
+	api.HandleFunc("/share-links", authMiddleware.RequireAuth(shares.ListLinks)).Methods("GET")
+	api.HandleFunc("/share-links/{id}/revoke", authMiddleware.RequireAuth(shares.RevokeLink)).Methods("POST")
+	api.HandleFunc("/share-links/{id}/access-log", authMiddleware.RequireAuth(shares.AccessLog)).Methods("GET")
+	api.HandleFunc("/share/{token}", authMiddleware.OptionalAuth(shares.Open)).Methods("GET")
+
+	// Consent-based access for external verifiers
+	api.HandleFunc("/access-requests", authMiddleware.RequireAuth(accessRequests.Create)).Methods("POST")
+	api.HandleFunc("/access-requests", authMiddleware.RequireAuth(accessRequests.List)).Methods("GET")
+	api.HandleFunc("/access-requests/{id}/decision", authMiddleware.RequireAuth(accessRequests.Decide)).Methods("POST")
+	api.HandleFunc("/access-requests/{id}/revoke", authMiddleware.RequireAuth(accessRequests.Revoke)).Methods("POST")
+	api.HandleFunc("/access-requests/{id}/certificates", authMiddleware.RequireAuth(accessRequests.Certificates)).Methods("GET")
+	api.HandleFunc("/access-requests/{id}/certificates/{cert_id}/document", authMiddleware.RequireAuth(accessRequests.Document)).Methods("GET")
+	api.HandleFunc("/access-requests/{id}/audit", authMiddleware.RequireAuth(accessRequests.AuditTrail)).Methods("GET")
+
+	// Selective disclosure (SD-JWT) presentations
+	api.HandleFunc("/sd/verify", disclosures.Verify).Methods("POST")
+
+	// Consolidated transcripts
+	api.HandleFunc("/transcripts", authMiddleware.RequireAuth(idempotency.Idempotent(transcripts.Generate))).Methods("POST")
+	api.HandleFunc("/transcripts/student/{student_id}", authMiddleware.RequireAuth(transcripts.Summary)).Methods("GET")
+	api.HandleFunc("/transcripts/{cert_id}/verify", transcripts.Verify).Methods("GET")
+
+	// Content stores
+	content := &handlerspkg.ContentHandler{Content: contentStore, Migrations: contentMigrationSvc, Pins: pinTracker}
+	// Documents in the development filesystem store have no gateway of their own
+	if _, ok := contentStore.(*services.FilesystemStore); ok {
+		api.HandleFunc("/content/{cid}", content.Get).Methods("GET")
+	}
+	api.HandleFunc("/content/migrate", authMiddleware.RequireAuth(content.Migrate)).Methods("POST")
+	api.HandleFunc("/pins/health", authMiddleware.RequireAuth(content.PinHealth)).Methods("GET")
+	api.HandleFunc("/pins/sweep", authMiddleware.RequireAuth(content.SweepPins)).Methods("POST")
+	api.HandleFunc("/documents/quarantine", authMiddleware.RequireAuth(quarantine.List)).Methods("GET")
+	api.HandleFunc("/documents/quarantine/{id}", authMiddleware.RequireAuth(quarantine.Discard)).Methods("DELETE")
+
+	// Degree eligibility rules
+	api.HandleFunc("/eligibility-rules", authMiddleware.RequireAuth(eligibility.ListRules)).Methods("GET")
+	api.HandleFunc("/eligibility-rules", authMiddleware.RequireAuth(eligibility.SetRule)).Methods("PUT")
+	api.HandleFunc("/eligibility/degree/{student_id}", authMiddleware.RequireAuth(eligibility.CheckDegree)).Methods("GET")
+
+	// Open Badges 3.0 hosted documents (public)
+	api.HandleFunc("/badges/issuer", badges.IssuerProfile).Methods("GET")
+	api.HandleFunc("/badges/achievements", badges.ListAchievements).Methods("GET")
+	api.HandleFunc("/badges/achievements/{id}", badges.GetAchievement).Methods("GET")
+	api.HandleFunc("/badges/achievements/{id}/image", badges.GetAchievementImage).Methods("GET")
+
+	// Blockcerts v3 export and verification
+	api.HandleFunc("/blockcerts/batches", authMiddleware.RequireAuth(blockcertsHandler.CreateBatch)).Methods("POST")
+	api.HandleFunc("/blockcerts/issuer", blockcertsHandler.IssuerProfile).Methods("GET")
+	api.HandleFunc("/blockcerts/verify", blockcertsHandler.Verify).Methods("POST")
+
+	// Decentralized identifiers
+	api.HandleFunc("/did/resolve/{did}", dids.Resolve).Methods("GET")
+	api.HandleFunc("/did/keys", dids.ListKeys).Methods("GET")
+	api.HandleFunc("/did/keys/rotate", authMiddleware.RequireAuth(dids.RotateKey)).Methods("POST")
+	api.HandleFunc("/did/issuers", dids.ListIssuers).Methods("GET")
+
+	// Signing key inventory and rotation (admins)
+	api.HandleFunc("/admin/keys", authMiddleware.RequireAuth(signingKeys.Inventory)).Methods("GET")
+	api.HandleFunc("/admin/keys/{purpose}/rotate", authMiddleware.RequireAuth(signingKeys.Rotate)).Methods("POST")
+
+	// Maker-checker approval endpoints
+	api.HandleFunc("/drafts", authMiddleware.RequireAuth(idempotency.Idempotent(approvals.CreateDraft))).Methods("POST")
+	api.HandleFunc("/drafts", authMiddleware.RequireAuth(approvals.ListMyDrafts)).Methods("GET")
+	api.HandleFunc("/drafts/{id}", authMiddleware.RequireAuth(approvals.GetDraft)).Methods("GET")
+	api.HandleFunc("/drafts/{id}/submit", authMiddleware.RequireAuth(approvals.SubmitDraft)).Methods("POST")
+	api.HandleFunc("/drafts/{id}/approve", authMiddleware.RequireAuth(approvals.Approve)).Methods("POST")
+	api.HandleFunc("/drafts/{id}/reject", authMiddleware.RequireAuth(approvals.Reject)).Methods("POST")
+	api.HandleFunc("/approvals/inbox", authMiddleware.RequireAuth(approvals.Inbox)).Methods("GET")
+	api.HandleFunc("/approval-policies", authMiddleware.RequireAuth(approvals.ListPolicies)).Methods("GET")
+	api.HandleFunc("/approval-policies/{cert_type}", authMiddleware.RequireAuth(approvals.SetPolicy)).Methods("PUT")
+
+	// Certificate template endpoints
+	api.HandleFunc("/templates", authMiddleware.RequireAuth(templates.CreateTemplate)).Methods("POST")
+	api.HandleFunc("/templates", authMiddleware.RequireAuth(templates.ListTemplates)).Methods("GET")
+	api.HandleFunc("/templates/{id}", authMiddleware.RequireAuth(templates.GetTemplate)).Methods("GET")
+	api.HandleFunc("/templates/{id}/activate", authMiddleware.RequireAuth(templates.ActivateTemplate)).Methods("POST")
+	api.HandleFunc("/templates/{id}/preview", authMiddleware.RequireAuth(templates.PreviewTemplate)).Methods("POST")
+
 	// Blockchain endpoints
 	var blockchain *handlerspkg.BlockchainHandler
 	if blockchainService != nil {
 		// Support both Besu and GoEth services
 		if besuSvc, ok := blockchainService.(*services.BesuBlockchainService); ok {
 			// Create a wrapper that implements the same interface
-			blockchain = &handlerspkg.BlockchainHandler{Blockchain: besuSvc}
+			blockchain = &handlerspkg.BlockchainHandler{Blockchain: besuSvc, DIDs: didSvc}
 			api.HandleFunc("/blockchain/status", blockchain.GetBlockchainStatus).Methods("GET")
 			api.HandleFunc("/blockchain/register-issuer", authMiddleware.RequireAuth(blockchain.RegisterIssuer)).Methods("POST")
 			api.HandleFunc("/blockchain/verify-certificate", blockchain.VerifyCertificateOnChain).Methods("GET")
 			api.HandleFunc("/blockchain/certificate", blockchain.GetCertificateFromChain).Methods("GET")
 		} else if goEthSvc, ok := blockchainService.(*services.GoEthBlockchainService); ok {
-			blockchain = &handlerspkg.BlockchainHandler{Blockchain: goEthSvc}
+			blockchain = &handlerspkg.BlockchainHandler{Blockchain: goEthSvc, DIDs: didSvc}
 			api.HandleFunc("/blockchain/status", blockchain.GetBlockchainStatus).Methods("GET")
 			api.HandleFunc("/blockchain/register-issuer", authMiddleware.RequireAuth(blockchain.RegisterIssuer)).Methods("POST")
 			api.HandleFunc("/blockchain/verify-certificate", blockchain.VerifyCertificateOnChain).Methods("GET")
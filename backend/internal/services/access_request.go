@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+const (
+	defaultAccessGrantDays = 30
+	maxAccessGrantDays     = 365
+)
+
+// AccessRequestService lets external verifiers ask students for consent before seeing their certificates.
+// Every step is written to the request's audit trail.
+type AccessRequestService struct {
+	store        store.Store
+	certificates *CertificateService
+}
+
+func NewAccessRequestService(s store.Store, certificates *CertificateService) *AccessRequestService {
+	return &AccessRequestService{
+		store:        s,
+		certificates: certificates,
+	}
+}
+
+// Create submits a verifier's request for a student's certificates of the given types
+func (a *AccessRequestService) Create(verifier models.User, req models.CreateAccessRequestRequest, ip string) (*models.AccessRequest, error) {
+	if verifier.Role != models.RoleExternalVerifier {
+		return nil, fmt.Errorf("%w: only external verifiers can request access", ErrPermissionDenied)
+	}
+	if req.StudentID == "" {
+		return nil, fmt.Errorf("student_id is required")
+	}
+	if len(req.CertTypes) == 0 {
+		return nil, fmt.Errorf("cert_types is required")
+	}
+	if strings.TrimSpace(req.Purpose) == "" {
+		return nil, fmt.Errorf("purpose is required")
+	}
+	if _, err := a.store.GetUserByStudentID(req.StudentID); err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+
+	now := time.Now()
+	created, err := a.store.CreateAccessRequest(models.AccessRequest{
+		VerifierID:    verifier.ID.Hex(),
+		VerifierName:  verifier.Name,
+		VerifierEmail: verifier.Email,
+		StudentID:     req.StudentID,
+		CertTypes:     uniqueCertTypes(req.CertTypes),
+		Purpose:       strings.TrimSpace(req.Purpose),
+		Status:        models.AccessRequestPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save access request: %w", err)
+	}
+	a.audit(created, verifier, models.AuditAccessRequested, nil, created.Purpose, ip)
+	return &created, nil
+}
+
+// List returns the requests the user is party to: received for students, sent for verifiers
+func (a *AccessRequestService) List(user models.User) ([]models.AccessRequest, error) {
+	switch {
+	case user.StudentID != "":
+		return a.store.ListAccessRequestsByStudent(user.StudentID)
+	case user.Role == models.RoleExternalVerifier:
+		return a.store.ListAccessRequestsByVerifier(user.ID.Hex())
+	}
+	return nil, fmt.Errorf("%w: access requests are between students and external verifiers", ErrPermissionDenied)
+}
+
+// Decide records the student's consent or refusal, with the approved scope and expiry
+func (a *AccessRequestService) Decide(id string, student models.User, decision models.AccessDecisionRequest, ip string) (*models.AccessRequest, error) {
+	req, err := a.store.GetAccessRequestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if student.StudentID == "" || student.StudentID != req.StudentID {
+		return nil, fmt.Errorf("%w: only the student can decide on this request", ErrPermissionDenied)
+	}
+	if req.Status != models.AccessRequestPending {
+		return nil, fmt.Errorf("access request is already %s", req.Status)
+	}
+
+	now := time.Now()
+	req.DecidedAt = &now
+	req.DecisionNote = decision.Note
+	if !decision.Approve {
+		req.Status = models.AccessRequestDenied
+		if req, err = a.store.UpdateAccessRequest(id, req); err != nil {
+			return nil, fmt.Errorf("failed to update access request: %w", err)
+		}
+		a.audit(req, student, models.AuditAccessDenied, nil, decision.Note, ip)
+		return &req, nil
+	}
+
+	// The student can narrow the scope but never widen it beyond what was requested
+	granted := req.CertTypes
+	if len(decision.CertTypes) > 0 {
+		granted = uniqueCertTypes(decision.CertTypes)
+		for _, t := range granted {
+			if !containsCertType(req.CertTypes, t) {
+				return nil, fmt.Errorf("cert type %s was not requested", t)
+			}
+		}
+	}
+	certIDs := uniqueStrings(decision.CertIDs)
+	for _, certID := range certIDs {
+		cert, err := a.store.GetCertificateByCertID(certID)
+		if err != nil || cert.StudentID != req.StudentID {
+			return nil, fmt.Errorf("certificate %s not found", certID)
+		}
+		if !containsCertType(granted, cert.CertType) {
+			return nil, fmt.Errorf("certificate %s is outside the granted cert types", certID)
+		}
+	}
+
+	days := decision.ExpiresInDays
+	if days == 0 {
+		days = defaultAccessGrantDays
+	}
+	if days < 0 || days > maxAccessGrantDays {
+		return nil, fmt.Errorf("expires_in_days must be between 1 and %d", maxAccessGrantDays)
+	}
+	expiresAt := now.AddDate(0, 0, days)
+
+	req.Status = models.AccessRequestApproved
+	req.GrantedTypes = granted
+	req.GrantedCertIDs = certIDs
+	req.ExpiresAt = &expiresAt
+	if req, err = a.store.UpdateAccessRequest(id, req); err != nil {
+		return nil, fmt.Errorf("failed to update access request: %w", err)
+	}
+	a.audit(req, student, models.AuditAccessApproved, certIDs, fmt.Sprintf("granted %v until %s", granted, expiresAt.Format(time.RFC3339)), ip)
+	return &req, nil
+}
+
+// Revoke withdraws a previously given consent
+func (a *AccessRequestService) Revoke(id string, student models.User, ip string) (*models.AccessRequest, error) {
+	req, err := a.store.GetAccessRequestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if student.StudentID == "" || student.StudentID != req.StudentID {
+		return nil, fmt.Errorf("%w: only the student can revoke this request", ErrPermissionDenied)
+	}
+	if req.Status != models.AccessRequestApproved {
+		return nil, fmt.Errorf("only approved requests can be revoked (request is %s)", req.Status)
+	}
+
+	req.Status = models.AccessRequestRevoked
+	if req, err = a.store.UpdateAccessRequest(id, req); err != nil {
+		return nil, fmt.Errorf("failed to update access request: %w", err)
+	}
+	a.audit(req, student, models.AuditAccessRevoked, nil, "", ip)
+	return &req, nil
+}
+
+// Certificates returns the full certificate records the student consented to share
+func (a *AccessRequestService) Certificates(id string, verifier models.User, ip string) (*models.GrantedCertificates, error) {
+	req, err := a.grantedRequest(id, verifier, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := a.grantedCertificates(req)
+	if err != nil {
+		return nil, err
+	}
+	certIDs := make([]string, len(certs))
+	for i, cert := range certs {
+		certIDs[i] = cert.CertID
+	}
+	a.audit(req, verifier, models.AuditAccessViewed, certIDs, "", ip)
+	return &models.GrantedCertificates{Request: req, Certificates: certs}, nil
+}
+
+// Document releases the stored file of a granted certificate after checking it against the anchored hash
+func (a *AccessRequestService) Document(ctx context.Context, id, certID string, verifier models.User, ip string) (*CertificateDocument, error) {
+	req, err := a.grantedRequest(id, verifier, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := a.grantedCertificates(req)
+	if err != nil {
+		return nil, err
+	}
+	var cert *models.Certificate
+	for i := range certs {
+		if certs[i].CertID == certID {
+			cert = &certs[i]
+		}
+	}
+	if cert == nil {
+		a.audit(req, verifier, models.AuditAccessRefused, []string{certID}, "certificate is outside the granted scope", ip)
+		return nil, fmt.Errorf("%w: certificate %s is not covered by this consent", ErrPermissionDenied, certID)
+	}
+
+	data, err := a.certificates.fetchDocument(ctx, *cert)
+	if err != nil {
+		return nil, err
+	}
+	a.audit(req, verifier, models.AuditAccessDownloaded, []string{certID}, "", ip)
+	return &CertificateDocument{CertID: certID, FileHash: cert.FileHash, IssuedAt: cert.IssuedAt, Data: data}, nil
+}
+
+// AuditTrail returns every recorded action on a request to its student, its verifier or an administrator
+func (a *AccessRequestService) AuditTrail(id string, user models.User) ([]models.AccessAuditEntry, error) {
+	req, err := a.store.GetAccessRequestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	isParty := (user.StudentID != "" && user.StudentID == req.StudentID) || user.ID.Hex() == req.VerifierID
+	if !isParty && !user.CanPerformAction("can_manage_users") {
+		return nil, fmt.Errorf("%w: not a party to this access request", ErrPermissionDenied)
+	}
+	return a.store.ListAccessAuditEntries(id)
+}
+
+// grantedRequest loads a request and checks the verifier currently holds consent under it
+func (a *AccessRequestService) grantedRequest(id string, verifier models.User, ip string) (models.AccessRequest, error) {
+	req, err := a.store.GetAccessRequestByID(id)
+	if err != nil {
+		return models.AccessRequest{}, err
+	}
+	if verifier.ID.Hex() != req.VerifierID {
+		return models.AccessRequest{}, fmt.Errorf("%w: access request belongs to another verifier", ErrPermissionDenied)
+	}
+
+	var refusal string
+	switch {
+	case req.Status != models.AccessRequestApproved:
+		refusal = fmt.Sprintf("access request is %s", req.Status)
+	case req.ExpiresAt != nil && time.Now().After(*req.ExpiresAt):
+		refusal = fmt.Sprintf("consent expired on %s", req.ExpiresAt.Format(time.RFC3339))
+	}
+	if refusal != "" {
+		a.audit(req, verifier, models.AuditAccessRefused, nil, refusal, ip)
+		return models.AccessRequest{}, fmt.Errorf("%w: %s", ErrPermissionDenied, refusal)
+	}
+	return req, nil
+}
+
+func (a *AccessRequestService) grantedCertificates(req models.AccessRequest) ([]models.Certificate, error) {
+	all, err := a.store.ListCertificatesByStudent(req.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve certificates: %w", err)
+	}
+	certs := []models.Certificate{}
+	for _, cert := range all {
+		if !containsCertType(req.GrantedTypes, cert.CertType) {
+			continue
+		}
+		if len(req.GrantedCertIDs) > 0 && !containsString(req.GrantedCertIDs, cert.CertID) {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func (a *AccessRequestService) audit(req models.AccessRequest, actor models.User, action models.AccessAuditAction, certIDs []string, detail, ip string) {
+	entry := models.AccessAuditEntry{
+		RequestID: req.ID.Hex(),
+		ActorID:   actor.ID.Hex(),
+		ActorRole: actor.Role,
+		Action:    action,
+		CertIDs:   certIDs,
+		Detail:    detail,
+		IP:        ip,
+		At:        time.Now(),
+	}
+	if _, err := a.store.CreateAccessAuditEntry(entry); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to record access audit entry: %v\n", err)
+	}
+}
+
+func uniqueCertTypes(types []models.CredentialType) []models.CredentialType {
+	out := make([]models.CredentialType, 0, len(types))
+	for _, t := range types {
+		if t != "" && !containsCertType(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func containsCertType(types []models.CredentialType, t models.CredentialType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// ErrDraftConflict is returned when another checker decided on a draft first
+var ErrDraftConflict = errors.New("draft was decided by another checker; reload it and try again")
+
+// ApprovalService implements the maker-checker workflow for certificate issuance
+type ApprovalService struct {
+	store        store.Store
+	certificates *CertificateService
+}
+
+func NewApprovalService(cfg config.Config, s store.Store, certificates *CertificateService) *ApprovalService {
+	a := &ApprovalService{
+		store:        s,
+		certificates: certificates,
+	}
+	a.seedPolicies(cfg.ApprovalPolicies)
+	return a
+}
+
+// seedPolicies installs configured policies for credential types that have none yet
+func (a *ApprovalService) seedPolicies(spec string) {
+	policies, err := ParseApprovalPolicies(spec)
+	if err != nil {
+		log.Printf("⚠️  Ignoring APPROVAL_POLICIES: %v", err)
+		return
+	}
+
+	for _, policy := range policies {
+		if _, err := a.store.GetApprovalPolicy(policy.CertType); err == nil {
+			continue // Policies edited through the API take precedence
+		}
+		policy.UpdatedBy = "config"
+		policy.UpdatedAt = time.Now()
+		if _, err := a.store.UpsertApprovalPolicy(policy); err != nil {
+			log.Printf("⚠️  Failed to seed approval policy for %s: %v", policy.CertType, err)
+		}
+	}
+}
+
+// ParseApprovalPolicies parses "degree=coe+ssn_main_admin;marksheet=coe" into policies
+func ParseApprovalPolicies(spec string) ([]models.ApprovalPolicy, error) {
+	policies := []models.ApprovalPolicy{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid policy %q: expected type=role+role", entry)
+		}
+
+		roles := []models.UserRole{}
+		for _, role := range strings.Split(parts[1], "+") {
+			role = strings.TrimSpace(role)
+			if !models.IsValidRole(role) {
+				return nil, fmt.Errorf("invalid role %q in policy %q", role, entry)
+			}
+			roles = append(roles, models.UserRole(role))
+		}
+
+		policies = append(policies, models.ApprovalPolicy{
+			CertType:      models.CredentialType(strings.TrimSpace(parts[0])),
+			RequiredRoles: roles,
+		})
+	}
+	return policies, nil
+}
+
+// ListPolicies returns all configured approval policies
+func (a *ApprovalService) ListPolicies() ([]models.ApprovalPolicy, error) {
+	return a.store.ListApprovalPolicies()
+}
+
+// SetPolicy replaces the approval policy for a credential type; an empty role list disables approval
+func (a *ApprovalService) SetPolicy(certType models.CredentialType, roles []models.UserRole, actorID string) (models.ApprovalPolicy, error) {
+	actor, err := a.store.GetUserByID(actorID)
+	if err != nil {
+		return models.ApprovalPolicy{}, fmt.Errorf("user not found: %w", err)
+	}
+	if !actor.CanPerformAction("can_manage_users") {
+		return models.ApprovalPolicy{}, fmt.Errorf("%w: only administrators can change approval policies", ErrPermissionDenied)
+	}
+
+	seen := map[models.UserRole]bool{}
+	for _, role := range roles {
+		if !models.IsValidRole(string(role)) {
+			return models.ApprovalPolicy{}, fmt.Errorf("invalid role: %s", role)
+		}
+		if seen[role] {
+			return models.ApprovalPolicy{}, fmt.Errorf("duplicate role: %s", role)
+		}
+		seen[role] = true
+	}
+
+	return a.store.UpsertApprovalPolicy(models.ApprovalPolicy{
+		CertType:      certType,
+		RequiredRoles: roles,
+		UpdatedBy:     actorID,
+		UpdatedAt:     time.Now(),
+	})
+}
+
+// RequiresApproval reports whether the credential type must go through the workflow
+func (a *ApprovalService) RequiresApproval(certType models.CredentialType) bool {
+	return a.certificates.requiresApproval(certType)
+}
+
+// CreateDraft stages an issuance request; nothing leaves the database until it is approved
+func (a *ApprovalService) CreateDraft(ctx context.Context, req models.CreateDraftRequest, makerID string) (*models.CertificateDraft, error) {
+	draft, err := a.newDraft(req.IssueCertificateRequest, makerID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := a.store.CreateCertificateDraft(*draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	if req.Submit {
+		return a.SubmitDraft(ctx, created.ID.Hex(), makerID)
+	}
+	return &created, nil
+}
+
+// CreateReissueDraft stages an amended version of an existing certificate for approval
+func (a *ApprovalService) CreateReissueDraft(ctx context.Context, certID string, req models.ReissueCertificateRequest, makerID string) (*models.CertificateDraft, error) {
+	old, err := a.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
+	}
+	if old.Status == models.CertStatusRevoked || old.Status == models.CertStatusSuperseded {
+		return nil, fmt.Errorf("%s certificates cannot be reissued", old.Status)
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("reissue reason is required")
+	}
+
+	draft, err := a.newDraft(models.IssueCertificateRequest{
+		StudentID: old.StudentID,
+		CertType:  old.CertType,
+		FileData:  req.FileData,
+		FileName:  req.FileName,
+		Metadata:  req.Metadata,
+	}, makerID)
+	if err != nil {
+		return nil, err
+	}
+	draft.Supersedes = old.CertID
+	draft.ReissueReason = req.Reason
+
+	created, err := a.store.CreateCertificateDraft(*draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	return a.SubmitDraft(ctx, created.ID.Hex(), makerID)
+}
+
+func (a *ApprovalService) newDraft(req models.IssueCertificateRequest, makerID string) (*models.CertificateDraft, error) {
+	if _, err := a.store.GetUserByStudentID(req.StudentID); err != nil {
+		return nil, fmt.Errorf("student not found: %w", err)
+	}
+
+	maker, err := a.store.GetUserByID(makerID)
+	if err != nil {
+		return nil, fmt.Errorf("issuer not found: %w", err)
+	}
+	if !a.certificates.canIssueCertificate(maker.Role, req.CertType) {
+		return nil, fmt.Errorf("%w: issuer cannot prepare %s certificates", ErrPermissionDenied, req.CertType)
+	}
+
+	if len(req.FileData) == 0 || req.FileName == "" {
+		return nil, fmt.Errorf("file data and file name are required")
+	}
+
+	// Checkers review and hash the cleaned document, so the checks run before the draft is stored
+	fileData, err := a.certificates.validateDocument(req.FileData, documentOrigin{fileName: req.FileName, uploadedBy: makerID, studentID: req.StudentID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &models.CertificateDraft{
+		StudentID: req.StudentID,
+		CertType:  req.CertType,
+		FileData:  fileData,
+		FileName:  req.FileName,
+		FileHash:  a.certificates.computeFileHash(fileData),
+		Metadata:  req.Metadata,
+		MakerID:   makerID,
+		Status:    models.CertStatusDraft,
+		Decisions: []models.ApprovalDecision{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// SubmitDraft moves a draft into pending_approval under the policy in force at submission time
+func (a *ApprovalService) SubmitDraft(ctx context.Context, draftID, makerID string) (*models.CertificateDraft, error) {
+	draft, err := a.store.GetCertificateDraftByID(draftID)
+	if err != nil {
+		return nil, err
+	}
+	if draft.MakerID != makerID {
+		return nil, fmt.Errorf("%w: only the author can submit a draft", ErrPermissionDenied)
+	}
+	if draft.Status != models.CertStatusDraft {
+		return nil, fmt.Errorf("draft is %s, not draft", draft.Status)
+	}
+
+	// Ineligible degrees are turned back before they reach the checkers; issuance checks again
+	if draft.CertType == models.CredentialTypeDegree {
+		if err := a.certificates.checkDegreeEligibility(draft.StudentID, draft.Supersedes); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := a.certificates.checkDuplicates(models.IssueCertificateRequest{
+		StudentID: draft.StudentID,
+		CertType:  draft.CertType,
+		Metadata:  draft.Metadata,
+	}, draft.FileHash, draft.Supersedes); err != nil {
+		return nil, err
+	}
+
+	policy, err := a.store.GetApprovalPolicy(draft.CertType)
+	if err != nil || len(policy.RequiredRoles) == 0 {
+		// No policy: the draft can be issued straight away
+		return a.issue(ctx, draft)
+	}
+
+	now := time.Now()
+	draft.RequiredRoles = policy.RequiredRoles
+	draft.Status = models.CertStatusPendingApproval
+	draft.SubmittedAt = &now
+	draft.UpdatedAt = now
+
+	updated, err := a.store.UpdateCertificateDraft(draftID, draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit draft: %w", err)
+	}
+	return &updated, nil
+}
+
+// Approve records a checker's sign-off and issues the certificate once every required role has approved.
+// Each decision is a conditional transition on the draft, so only the checker who records the final
+// approval issues the certificate.
+func (a *ApprovalService) Approve(ctx context.Context, draftID, approverID, comment string) (*models.CertificateDraft, error) {
+	draft, approver, err := a.loadForDecision(draftID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	previous := draft
+	decided := len(draft.Decisions)
+
+	draft.Decisions = append(draft.Decisions, models.ApprovalDecision{
+		ApproverID:   approverID,
+		ApproverName: approver.Name,
+		Role:         approver.Role,
+		Decision:     models.DecisionApproved,
+		Comment:      comment,
+		DecidedAt:    time.Now(),
+	})
+	draft.UpdatedAt = time.Now()
+
+	if len(draft.PendingRoles()) > 0 {
+		updated, err := a.transition(draftID, decided, draft)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record approval: %w", err)
+		}
+		return &updated, nil
+	}
+
+	draft.Status = models.CertStatusApproved
+	claimed, err := a.transition(draftID, decided, draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	issued, err := a.issue(ctx, claimed)
+	if err != nil {
+		// Hand the draft back so the final approval can be given again
+		if _, restoreErr := a.store.UpdateCertificateDraft(draftID, previous); restoreErr != nil {
+			log.Printf("⚠️  Failed to reopen draft %s after issuance failed: %v", draftID, restoreErr)
+		}
+		return nil, err
+	}
+	return issued, nil
+}
+
+// Reject closes a draft; the maker must start a new one to try again
+func (a *ApprovalService) Reject(draftID, approverID, comment string) (*models.CertificateDraft, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, fmt.Errorf("a comment is required when rejecting")
+	}
+
+	draft, approver, err := a.loadForDecision(draftID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	decided := len(draft.Decisions)
+
+	draft.Decisions = append(draft.Decisions, models.ApprovalDecision{
+		ApproverID:   approverID,
+		ApproverName: approver.Name,
+		Role:         approver.Role,
+		Decision:     models.DecisionRejected,
+		Comment:      comment,
+		DecidedAt:    time.Now(),
+	})
+	draft.Status = models.CertStatusRejected
+	draft.FileData = nil // Rejected documents are not retained
+	draft.UpdatedAt = time.Now()
+
+	updated, err := a.transition(draftID, decided, draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record rejection: %w", err)
+	}
+	return &updated, nil
+}
+
+// transition stores a decision only if the draft is still pending with the decisions it was loaded with
+func (a *ApprovalService) transition(draftID string, decided int, draft models.CertificateDraft) (models.CertificateDraft, error) {
+	updated, err := a.store.TransitionCertificateDraft(draftID, models.CertStatusPendingApproval, decided, draft)
+	if errors.Is(err, store.ErrConflict) {
+		return models.CertificateDraft{}, ErrDraftConflict
+	}
+	return updated, err
+}
+
+// loadForDecision checks that the approver may act on the draft right now
+func (a *ApprovalService) loadForDecision(draftID, approverID string) (models.CertificateDraft, models.User, error) {
+	draft, err := a.store.GetCertificateDraftByID(draftID)
+	if err != nil {
+		return models.CertificateDraft{}, models.User{}, err
+	}
+	approver, err := a.store.GetUserByID(approverID)
+	if err != nil {
+		return models.CertificateDraft{}, models.User{}, fmt.Errorf("user not found: %w", err)
+	}
+
+	if draft.Status != models.CertStatusPendingApproval {
+		return models.CertificateDraft{}, models.User{}, fmt.Errorf("draft is %s, not pending approval", draft.Status)
+	}
+	if !a.canDecide(draft, approver) {
+		return models.CertificateDraft{}, models.User{}, fmt.Errorf("%w: user cannot decide on this draft", ErrPermissionDenied)
+	}
+
+	return draft, approver, nil
+}
+
+// canDecide enforces maker-checker separation: the maker never approves, and each
+// pending role is satisfied by a different user holding that role
+func (a *ApprovalService) canDecide(draft models.CertificateDraft, user models.User) bool {
+	if draft.MakerID == user.ID.Hex() {
+		return false
+	}
+	for _, decision := range draft.Decisions {
+		if decision.ApproverID == user.ID.Hex() {
+			return false
+		}
+	}
+	for _, role := range draft.PendingRoles() {
+		if role == user.Role {
+			return true
+		}
+	}
+	return false
+}
+
+// issue runs the approved draft through the regular issuance pipeline
+func (a *ApprovalService) issue(ctx context.Context, draft models.CertificateDraft) (*models.CertificateDraft, error) {
+	var cert *models.Certificate
+	var err error
+	if draft.Supersedes != "" {
+		old, getErr := a.store.GetCertificateByCertID(draft.Supersedes)
+		if getErr != nil {
+			return nil, fmt.Errorf("certificate not found: %w", getErr)
+		}
+		cert, err = a.certificates.reissueCertificate(ctx, old, models.ReissueCertificateRequest{
+			Reason:   draft.ReissueReason,
+			FileData: draft.FileData,
+			FileName: draft.FileName,
+			Metadata: draft.Metadata,
+		}, draft.MakerID)
+		if errors.Is(err, ErrSupersedePending) {
+			// The new version is issued; the supersession sweep activates it
+			log.Printf("⚠️  %v", err)
+			err = nil
+		}
+	} else {
+		cert, err = a.certificates.issueCertificate(ctx, models.IssueCertificateRequest{
+			StudentID: draft.StudentID,
+			CertType:  draft.CertType,
+			FileData:  draft.FileData,
+			FileName:  draft.FileName,
+			Metadata:  draft.Metadata,
+		}, draft.MakerID, draftIssueOptions(draft))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("approved draft could not be issued: %w", err)
+	}
+
+	draft.Status = models.CertStatusIssued
+	draft.CertID = cert.CertID
+	draft.FileData = nil // The document now lives on IPFS
+	draft.UpdatedAt = time.Now()
+
+	updated, err := a.store.UpdateCertificateDraft(draft.ID.Hex(), draft)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s issued but draft update failed: %w", cert.CertID, err)
+	}
+	return &updated, nil
+}
+
+// draftIssueOptions carries over a cert ID reserved when the draft's document was generated
+func draftIssueOptions(draft models.CertificateDraft) issueOptions {
+	if draft.CertID == "" || draft.IssuedAt == nil {
+		return issueOptions{}
+	}
+	return issueOptions{certID: draft.CertID, issuedAt: *draft.IssuedAt, generated: true}
+}
+
+// GetDraft returns a draft visible to the user: its maker, a checker, or an administrator
+func (a *ApprovalService) GetDraft(draftID, userID string) (*models.CertificateDraft, error) {
+	draft, err := a.store.GetCertificateDraftByID(draftID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if draft.MakerID != userID && !user.CanPerformAction("can_view_all_credentials") && !containsRole(draft.RequiredRoles, user.Role) {
+		return nil, fmt.Errorf("%w: user cannot view this draft", ErrPermissionDenied)
+	}
+	return &draft, nil
+}
+
+// ListMyDrafts returns the drafts authored by a user
+func (a *ApprovalService) ListMyDrafts(makerID string) ([]models.CertificateDraft, error) {
+	drafts, err := a.store.ListCertificateDrafts()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.CertificateDraft{}
+	for _, draft := range drafts {
+		if draft.MakerID == makerID {
+			result = append(result, draft)
+		}
+	}
+	return result, nil
+}
+
+// Inbox returns the drafts awaiting a decision from the user's role
+func (a *ApprovalService) Inbox(userID string) ([]models.CertificateDraft, error) {
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	drafts, err := a.store.ListCertificateDrafts()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.CertificateDraft{}
+	for _, draft := range drafts {
+		if draft.Status == models.CertStatusPendingApproval && a.canDecide(draft, user) {
+			result = append(result, draft)
+		}
+	}
+	return result, nil
+}
+
+func containsRole(roles []models.UserRole, role models.UserRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
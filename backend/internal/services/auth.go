@@ -10,11 +10,12 @@ import (
 )
 
 type AuthService struct {
-	store store.Store
+	store  store.Store
+	tokens *TokenIssuer
 }
 
-func NewAuthService(s store.Store) *AuthService {
-	return &AuthService{store: s}
+func NewAuthService(s store.Store, tokens *TokenIssuer) *AuthService {
+	return &AuthService{store: s, tokens: tokens}
 }
 
 func (a *AuthService) Login(username, password string) (models.User, string, error) {
@@ -28,8 +29,10 @@ func (a *AuthService) Login(username, password string) (models.User, string, err
 			if !u.IsApproved {
 				return models.User{}, "", errors.New("account not approved")
 			}
-			// stub token; replace with real JWT
-			token := fmt.Sprintf("token-%s", u.ID.Hex())
+			token, err := a.tokens.Issue(u.ID.Hex())
+			if err != nil {
+				return models.User{}, "", err
+			}
 			return u, token, nil
 		}
 	}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+const obContext = "https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json"
+
+// BadgeService issues Open Badges 3.0 credentials for club participation certificates
+type BadgeService struct {
+	store  store.Store
+	vc     *VCService
+	keys   *InstitutionKeys
+	apiURL string
+}
+
+func NewBadgeService(cfg config.Config, s store.Store, vc *VCService, keys *InstitutionKeys) *BadgeService {
+	return &BadgeService{
+		store:  s,
+		vc:     vc,
+		keys:   keys,
+		apiURL: cfg.PublicAPIURL,
+	}
+}
+
+// IssuerProfile is the public Open Badges profile of the institution
+func (b *BadgeService) IssuerProfile() models.OBProfile {
+	return models.OBProfile{
+		ID:          b.keys.DID(),
+		Type:        []string{"Profile"},
+		Name:        b.keys.Name(),
+		URL:         b.apiURL + "/badges/issuer",
+		Description: "Issuer of club participation badges, anchored on the " + b.keys.Name() + " credential ledger",
+	}
+}
+
+// Achievement returns the public definition of one club event achievement
+func (b *BadgeService) Achievement(id string) (*models.OBAchievement, error) {
+	achievement, err := b.store.GetBadgeAchievementByID(id)
+	if err != nil {
+		return nil, err
+	}
+	ob := b.achievementDefinition(achievement)
+	return &ob, nil
+}
+
+// ListAchievements returns achievement definitions, optionally for a single club
+func (b *BadgeService) ListAchievements(clubName string) ([]models.OBAchievement, error) {
+	achievements, err := b.store.ListBadgeAchievements(clubName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.OBAchievement, 0, len(achievements))
+	for _, achievement := range achievements {
+		out = append(out, b.achievementDefinition(achievement))
+	}
+	return out, nil
+}
+
+// AchievementImage renders the unbaked badge artwork as "svg" or "png"
+func (b *BadgeService) AchievementImage(id, format string) ([]byte, string, error) {
+	achievement, err := b.store.GetBadgeAchievementByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if format == "png" {
+		img, err := renderBadgePNG(achievement.ClubName)
+		return img, "image/png", err
+	}
+	return renderBadgeSVG(achievement.ClubName, achievement.EventName), "image/svg+xml", nil
+}
+
+// BadgeCredential returns the certificate as an OpenBadgeCredential with a Data Integrity proof
+func (b *BadgeService) BadgeCredential(certID string, user models.User) (*models.OpenBadgeCredential, error) {
+	credential, _, err := b.unsignedCredential(certID, user)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := b.vc.signDataIntegrity(credential, credential.Context)
+	if err != nil {
+		return nil, err
+	}
+	credential.Proof = proof
+	return credential, nil
+}
+
+// BadgeJWT returns the certificate as a VC-JOSE secured OpenBadgeCredential
+func (b *BadgeService) BadgeJWT(certID string, user models.User) (string, error) {
+	credential, _, err := b.unsignedCredential(certID, user)
+	if err != nil {
+		return "", err
+	}
+	return b.vc.signJWT(credential)
+}
+
+// BakedBadge returns the badge image ("svg" or "png") with the signed credential embedded
+func (b *BadgeService) BakedBadge(certID string, user models.User, format string) ([]byte, string, error) {
+	credential, achievement, err := b.unsignedCredential(certID, user)
+	if err != nil {
+		return nil, "", err
+	}
+	proof, err := b.vc.signDataIntegrity(credential, credential.Context)
+	if err != nil {
+		return nil, "", err
+	}
+	credential.Proof = proof
+
+	// Default escaping keeps "]]>" out of the JSON so it is safe inside SVG CDATA
+	payload, err := json.Marshal(credential)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == "png" {
+		img, err := renderBadgePNG(achievement.ClubName)
+		if err != nil {
+			return nil, "", err
+		}
+		baked, err := bakePNG(img, payload)
+		return baked, "image/png", err
+	}
+	baked, err := bakeSVG(renderBadgeSVG(achievement.ClubName, achievement.EventName), payload)
+	return baked, "image/svg+xml", err
+}
+
+func (b *BadgeService) unsignedCredential(certID string, user models.User) (*models.OpenBadgeCredential, models.BadgeAchievement, error) {
+	cert, err := b.vc.exportableCertificate(certID, user)
+	if err != nil {
+		return nil, models.BadgeAchievement{}, err
+	}
+	if cert.CertType != models.CredentialTypeParticipation {
+		return nil, models.BadgeAchievement{}, fmt.Errorf("%w: only participation certificates are issued as badges", ErrCredentialUnavailable)
+	}
+
+	achievement, err := b.achievementFor(cert)
+	if err != nil {
+		return nil, models.BadgeAchievement{}, err
+	}
+
+	subject := models.OBAchievementSubject{
+		ID:          b.vc.subjectDID(cert),
+		Type:        []string{"AchievementSubject"},
+		Achievement: b.achievementDefinition(achievement),
+	}
+	if email := strings.ToLower(strings.TrimSpace(cert.Metadata.StudentEmail)); email != "" {
+		identity, err := hashedEmailIdentity(email)
+		if err != nil {
+			return nil, models.BadgeAchievement{}, err
+		}
+		subject.Identifier = []models.OBIdentityObject{identity}
+	}
+
+	credential := &models.OpenBadgeCredential{
+		Context:           []string{vcContextV2, obContext},
+		ID:                b.vc.verifyURL + cert.CertID + "#badge",
+		Type:              []string{"VerifiableCredential", "OpenBadgeCredential"},
+		Name:              achievement.EventName,
+		Issuer:            b.IssuerProfile(),
+		ValidFrom:         cert.IssuedAt.UTC().Format(time.RFC3339),
+		CredentialSubject: subject,
+		CredentialStatus: &models.VCStatus{
+			ID:            b.vc.verifyURL + cert.CertID,
+			Type:          vcStatusType,
+			CertificateID: cert.CertID,
+		},
+		Evidence: []models.VCEvidence{{
+			Type:            []string{"Evidence", "BlockchainAnchor"},
+			TransactionHash: cert.TxHash,
+			BlockNumber:     cert.BlockNumber,
+			DocumentHash:    cert.FileHash,
+			IPFSCID:         cert.IPFSCID,
+			IPFSURL:         cert.IPFSURL,
+		}},
+	}
+	if !cert.Metadata.ValidUntil.IsZero() {
+		credential.ValidUntil = cert.Metadata.ValidUntil.UTC().Format(time.RFC3339)
+	}
+	return credential, achievement, nil
+}
+
+// achievementFor finds the club event achievement of a participation certificate, creating it on first use
+func (b *BadgeService) achievementFor(cert models.Certificate) (models.BadgeAchievement, error) {
+	meta := cert.Metadata
+
+	clubName, _ := meta.AdditionalData["club_name"].(string)
+	if issuer, err := b.store.GetUserByID(cert.IssuerID); err == nil && issuer.ClubName != "" {
+		clubName = issuer.ClubName
+	}
+	if clubName == "" {
+		clubName = meta.Institution
+	}
+
+	eventName, _ := meta.AdditionalData["event_name"].(string)
+	for _, candidate := range []string{meta.Course, meta.Description} {
+		if eventName == "" {
+			eventName = candidate
+		}
+	}
+	eventName = strings.TrimSpace(eventName)
+	if eventName == "" {
+		return models.BadgeAchievement{}, fmt.Errorf("%w: certificate does not name the club event (set additional_data.event_name)", ErrCredentialUnavailable)
+	}
+
+	return b.store.GetOrCreateBadgeAchievement(models.BadgeAchievement{
+		ClubName:    clubName,
+		EventName:   eventName,
+		Description: meta.Description,
+		Criteria:    fmt.Sprintf("Participated in %s, organised by %s at %s.", eventName, clubName, b.keys.Name()),
+		CreatedBy:   cert.IssuerID,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func (b *BadgeService) achievementDefinition(achievement models.BadgeAchievement) models.OBAchievement {
+	url := b.apiURL + "/badges/achievements/" + achievement.ID.Hex()
+	description := achievement.Description
+	if description == "" {
+		description = fmt.Sprintf("Awarded for participating in %s, organised by %s.", achievement.EventName, achievement.ClubName)
+	}
+	issuer := b.IssuerProfile()
+	return models.OBAchievement{
+		ID:              url,
+		Type:            []string{"Achievement"},
+		AchievementType: "Badge",
+		Name:            achievement.EventName,
+		Description:     description,
+		Criteria:        models.OBCriteria{Narrative: achievement.Criteria},
+		Image:           &models.OBImage{ID: url + "/image", Type: "Image"},
+		Creator:         &issuer,
+		Tag:             []string{achievement.ClubName},
+	}
+}
+
+// hashedEmailIdentity identifies the recipient without publishing their address
+func hashedEmailIdentity(email string) (models.OBIdentityObject, error) {
+	salt := make([]byte, 8)
+	if _, err := rand.Read(salt); err != nil {
+		return models.OBIdentityObject{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	saltHex := hex.EncodeToString(salt)
+	sum := sha256.Sum256([]byte(email + saltHex))
+	return models.OBIdentityObject{
+		Type:         "IdentityObject",
+		IdentityHash: "sha256$" + hex.EncodeToString(sum[:]),
+		IdentityType: "emailAddress",
+		Hashed:       true,
+		Salt:         saltHex,
+	}, nil
+}
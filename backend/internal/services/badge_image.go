@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+
+	"blockcred-backend/internal/pdf"
+)
+
+const (
+	badgeImageSize = 400
+	obBakingNS     = "https://purl.imsglobal.org/ob/v3p0"
+	obPNGKeyword   = "openbadgecredential"
+)
+
+// badgePalette derives a stable primary color per club so every event of a club looks related
+func badgePalette(clubName string) (primary, accent color.RGBA) {
+	sum := sha256.Sum256([]byte(strings.ToLower(clubName)))
+	hue := float64(binary.BigEndian.Uint16(sum[:2])%360) / 360
+	return hslToRGB(hue, 0.55, 0.42), hslToRGB(hue, 0.65, 0.85)
+}
+
+func hslToRGB(h, s, l float64) color.RGBA {
+	q := l + s - l*s
+	if l < 0.5 {
+		q = l * (1 + s)
+	}
+	p := 2*l - q
+	channel := func(t float64) uint8 {
+		t -= math.Floor(t)
+		var v float64
+		switch {
+		case t < 1.0/6:
+			v = p + (q-p)*6*t
+		case t < 0.5:
+			v = q
+		case t < 2.0/3:
+			v = p + (q-p)*(2.0/3-t)*6
+		default:
+			v = p
+		}
+		return uint8(math.Round(v * 255))
+	}
+	return color.RGBA{channel(h + 1.0/3), channel(h), channel(h - 1.0/3), 255}
+}
+
+// starPoints returns the vertices of a five-pointed star centered at (cx, cy)
+func starPoints(cx, cy, outer, inner float64) [][2]float64 {
+	points := make([][2]float64, 0, 10)
+	for i := 0; i < 10; i++ {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		angle := -math.Pi/2 + float64(i)*math.Pi/5
+		points = append(points, [2]float64{cx + r*math.Cos(angle), cy + r*math.Sin(angle)})
+	}
+	return points
+}
+
+func insidePolygon(x, y float64, poly [][2]float64) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		xi, yi, xj, yj := poly[i][0], poly[i][1], poly[j][0], poly[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// renderBadgePNG draws the badge medallion. PNG output has no text; the SVG variant carries the event name.
+func renderBadgePNG(clubName string) ([]byte, error) {
+	primary, accent := badgePalette(clubName)
+	white := color.RGBA{255, 255, 255, 255}
+	size := float64(badgeImageSize)
+	c := size / 2
+	star := starPoints(c, c, size*0.26, size*0.105)
+
+	// Colour of the design at a sub-pixel sample, or transparent outside the medallion
+	sample := func(x, y float64) (color.RGBA, bool) {
+		d := math.Hypot(x-c, y-c)
+		switch {
+		case d > size*0.48:
+			return color.RGBA{}, false
+		case d > size*0.44:
+			return primary, true
+		case d > size*0.41:
+			return white, true
+		case d > size*0.38:
+			return accent, true
+		case insidePolygon(x, y, star):
+			return white, true
+		}
+		return primary, true
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, badgeImageSize, badgeImageSize))
+	const grid = 4 // 4x4 supersampling for smooth edges
+	for py := 0; py < badgeImageSize; py++ {
+		for px := 0; px < badgeImageSize; px++ {
+			var r, g, b, a int
+			for sy := 0; sy < grid; sy++ {
+				for sx := 0; sx < grid; sx++ {
+					col, ok := sample(float64(px)+(float64(sx)+0.5)/grid, float64(py)+(float64(sy)+0.5)/grid)
+					if ok {
+						r, g, b, a = r+int(col.R), g+int(col.G), b+int(col.B), a+255
+					}
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			n := grid * grid
+			// Premultiplied alpha: colour sums already weight each covered sample by 255
+			img.SetRGBA(px, py, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode badge: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderBadgeSVG draws the same medallion with the event and club names
+func renderBadgeSVG(clubName, eventName string) []byte {
+	primary, accent := badgePalette(clubName)
+	hex := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	size := float64(badgeImageSize)
+	c := size / 2
+
+	var star strings.Builder
+	for i, p := range starPoints(c, size*0.36, size*0.13, size*0.052) {
+		if i > 0 {
+			star.WriteByte(' ')
+		}
+		fmt.Fprintf(&star, "%.1f,%.1f", p[0], p[1])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, badgeImageSize, badgeImageSize, badgeImageSize, badgeImageSize)
+	fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="%s"/>`, c, c, size*0.48, hex(primary))
+	fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="none" stroke="#ffffff" stroke-width="%.0f"/>`, c, c, size*0.425, size*0.03)
+	fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="none" stroke="%s" stroke-width="%.0f"/>`, c, c, size*0.395, hex(accent), size*0.03)
+	fmt.Fprintf(&b, `<polygon points="%s" fill="#ffffff"/>`, star.String())
+
+	lines := pdf.WrapText(eventName, 24, true, size*0.62)
+	if len(lines) > 3 {
+		lines = append(lines[:2], strings.TrimSpace(lines[2])+"…")
+	}
+	y := size * 0.58
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="24" font-weight="bold" fill="#ffffff" text-anchor="middle">%s</text>`, c, y, xmlEscape(line))
+		y += 28
+	}
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="16" fill="%s" text-anchor="middle">%s</text>`, c, size*0.8, hex(accent), xmlEscape(strings.ToUpper(clubName)))
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '"':
+			buf.WriteString("&quot;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// bakePNG embeds the credential in an iTXt chunk directly after IHDR, per Open Badges 3.0 baking
+func bakePNG(img []byte, credential []byte) ([]byte, error) {
+	const ihdrEnd = 8 + 4 + 4 + 13 + 4 // signature + IHDR length, type, data, CRC
+	if len(img) < ihdrEnd || !bytes.Equal(img[:8], []byte("\x89PNG\r\n\x1a\n")) || string(img[12:16]) != "IHDR" {
+		return nil, fmt.Errorf("not a PNG image")
+	}
+
+	// keyword, null, compression flag, compression method, empty language tag and translated keyword
+	var data bytes.Buffer
+	data.WriteString(obPNGKeyword)
+	data.Write([]byte{0, 0, 0, 0, 0})
+	data.Write(credential)
+
+	chunk := make([]byte, 0, data.Len()+12)
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(data.Len()))
+	chunk = append(chunk, "iTXt"...)
+	chunk = append(chunk, data.Bytes()...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	out := make([]byte, 0, len(img)+len(chunk))
+	out = append(out, img[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, img[ihdrEnd:]...), nil
+}
+
+// bakeSVG embeds the credential as an openbadges:credential element inside the root svg element
+func bakeSVG(svg []byte, credential []byte) ([]byte, error) {
+	if bytes.Contains(credential, []byte("]]>")) {
+		return nil, fmt.Errorf("credential cannot be embedded in CDATA")
+	}
+	end := bytes.IndexByte(svg, '>')
+	if !bytes.HasPrefix(svg, []byte("<svg ")) || end < 0 {
+		return nil, fmt.Errorf("not an SVG image")
+	}
+
+	var out bytes.Buffer
+	out.Write(svg[:end])
+	fmt.Fprintf(&out, ` xmlns:openbadges="%s">`, obBakingNS)
+	out.WriteString("<openbadges:credential><![CDATA[")
+	out.Write(credential)
+	out.WriteString("]]></openbadges:credential>")
+	out.Write(svg[end+1:])
+	return out.Bytes(), nil
+}
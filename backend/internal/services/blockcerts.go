@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"blockcred-backend/internal/blockcerts"
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// maxBlockcertsBatch bounds how many certificates share one anchoring transaction
+const maxBlockcertsBatch = 1000
+
+// BlockcertsService exports certificates as Blockcerts v3 documents anchored in Merkle batches
+type BlockcertsService struct {
+	store      store.Store
+	vc         *VCService
+	blockchain BlockchainServiceInterface
+	keys       *InstitutionKeys
+	apiURL     string
+	chainID    int64
+	verifier   *blockcerts.Verifier
+}
+
+func NewBlockcertsService(cfg config.Config, s store.Store, vc *VCService, blockchain BlockchainServiceInterface, keys *InstitutionKeys) *BlockcertsService {
+	return &BlockcertsService{
+		store:      s,
+		vc:         vc,
+		blockchain: blockchain,
+		keys:       keys,
+		apiURL:     cfg.PublicAPIURL,
+		chainID:    cfg.ChainID,
+		verifier:   &blockcerts.Verifier{RPCURL: cfg.BlockchainRPCURL, ContractAddress: cfg.ContractAddress},
+	}
+}
+
+// IssuerProfile lists every account that has anchored a batch, so verifiers can trust their transactions
+func (b *BlockcertsService) IssuerProfile() (*models.BlockcertsIssuerProfile, error) {
+	batches, err := b.store.ListBlockcertsBatches()
+	if err != nil {
+		return nil, err
+	}
+
+	// Report each account with the date it first anchored
+	first := map[string]time.Time{}
+	for _, batch := range batches {
+		if created, ok := first[batch.IssuerAddress]; !ok || batch.CreatedAt.Before(created) {
+			first[batch.IssuerAddress] = batch.CreatedAt
+		}
+	}
+	keys := make([]models.BlockcertsPublicKey, 0, len(first))
+	for address, created := range first {
+		keys = append(keys, models.BlockcertsPublicKey{
+			ID:      blockcerts.KeyIDPrefix + address,
+			Created: created.UTC().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+
+	return &models.BlockcertsIssuerProfile{
+		Context:   []string{"https://w3id.org/openbadges/v2", blockcerts.ContextV3},
+		ID:        b.issuerProfileURL(),
+		Type:      "Profile",
+		Name:      b.keys.Name(),
+		DID:       b.keys.DID(),
+		PublicKey: keys,
+	}, nil
+}
+
+// CreateBatch builds documents for the certificates and anchors their Merkle root in one transaction
+func (b *BlockcertsService) CreateBatch(ctx context.Context, certIDs []string, actorID string) (*models.BlockcertsBatch, error) {
+	if b.blockchain == nil {
+		return nil, fmt.Errorf("blockchain service not available")
+	}
+	actor, err := b.store.GetUserByID(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	certIDs = uniqueStrings(certIDs)
+	if len(certIDs) == 0 {
+		return nil, fmt.Errorf("cert_ids is required")
+	}
+	if len(certIDs) > maxBlockcertsBatch {
+		return nil, fmt.Errorf("a batch can hold at most %d certificates", maxBlockcertsBatch)
+	}
+
+	entries := make([]models.BlockcertsEntry, 0, len(certIDs))
+	leaves := make([][]byte, 0, len(certIDs))
+	for _, certID := range certIDs {
+		cert, err := b.store.GetCertificateByCertID(certID)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %s not found: %w", certID, err)
+		}
+		if actor.ID.Hex() != cert.IssuerID && !actor.CanPerformAction("can_manage_users") {
+			return nil, fmt.Errorf("%w: certificate %s was issued by another user", ErrPermissionDenied, certID)
+		}
+		if cert.Status != models.CertStatusIssued && cert.Status != models.CertStatusVerified {
+			return nil, fmt.Errorf("%w: certificate %s is %s", ErrCredentialUnavailable, certID, cert.Status)
+		}
+
+		doc := b.buildDocument(cert)
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		targetHash, err := blockcerts.TargetHash(doc)
+		if err != nil {
+			return nil, err
+		}
+		leaf, _ := hex.DecodeString(targetHash)
+
+		entries = append(entries, models.BlockcertsEntry{CertID: certID, TargetHash: targetHash, Document: string(raw)})
+		leaves = append(leaves, leaf)
+	}
+
+	tree, err := blockcerts.NewTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.Root()
+	tx, err := b.blockchain.AnchorMerkleRoot(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anchor batch: %w", err)
+	}
+
+	batch, err := b.store.CreateBlockcertsBatch(models.BlockcertsBatch{
+		MerkleRoot:    root,
+		TxHash:        tx.TxHash,
+		BlockNumber:   tx.BlockNumber,
+		Anchor:        blockcerts.EthereumAnchor(b.chainID, tx.TxHash),
+		IssuerAddress: tx.From,
+		Entries:       entries,
+		CreatedBy:     actorID,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// Document returns the certificate's Blockcerts document from the most recent batch that anchored it
+func (b *BlockcertsService) Document(certID string, user models.User) (*models.BlockcertsDocument, error) {
+	if _, err := b.vc.exportableCertificate(certID, user); err != nil {
+		return nil, err
+	}
+	batch, err := b.store.GetLatestBlockcertsBatchByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: certificate has not been anchored in a Blockcerts batch", ErrCredentialUnavailable)
+	}
+
+	index := -1
+	leaves := make([][]byte, len(batch.Entries))
+	for i, entry := range batch.Entries {
+		if leaves[i], err = hex.DecodeString(entry.TargetHash); err != nil {
+			return nil, fmt.Errorf("corrupt batch %s: %w", batch.ID.Hex(), err)
+		}
+		if entry.CertID == certID {
+			index = i
+		}
+	}
+	tree, err := blockcerts.NewTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := batch.Entries[index]
+	proofValue, err := blockcerts.EncodeProofValue(blockcerts.MerkleProof2019{
+		Path:       tree.Path(index),
+		MerkleRoot: batch.MerkleRoot,
+		TargetHash: entry.TargetHash,
+		Anchors:    []string{batch.Anchor},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc models.BlockcertsDocument
+	if err := json.Unmarshal([]byte(entry.Document), &doc); err != nil {
+		return nil, fmt.Errorf("corrupt batch %s: %w", batch.ID.Hex(), err)
+	}
+	doc.Proof = &models.BlockcertsProof{
+		Type:               blockcerts.ProofType,
+		Created:            batch.CreatedAt.UTC().Format(time.RFC3339),
+		ProofPurpose:       "assertionMethod",
+		VerificationMethod: blockcerts.KeyIDPrefix + batch.IssuerAddress,
+		ProofValue:         proofValue,
+	}
+	return &doc, nil
+}
+
+// Verify checks a Blockcerts document against the configured chain, as an external verifier would
+func (b *BlockcertsService) Verify(raw []byte) (*blockcerts.Result, error) {
+	return b.verifier.Verify(raw)
+}
+
+func (b *BlockcertsService) buildDocument(cert models.Certificate) models.BlockcertsDocument {
+	vc := b.vc.buildCredential(cert)
+	return models.BlockcertsDocument{
+		Context:           []string{blockcerts.ContextVC, blockcerts.ContextV3},
+		ID:                vc.ID,
+		Type:              []string{"VerifiableCredential", "BlockcertsCredential"},
+		Issuer:            b.issuerProfileURL(),
+		IssuanceDate:      vc.ValidFrom,
+		ExpirationDate:    vc.ValidUntil,
+		CredentialSubject: vc.CredentialSubject,
+		CredentialStatus:  vc.CredentialStatus,
+		Evidence:          vc.Evidence,
+	}
+}
+
+func (b *BlockcertsService) issuerProfileURL() string {
+	return b.apiURL + "/blockcerts/issuer"
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
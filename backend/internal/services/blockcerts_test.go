@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+func TestBlockcertsBatchProofsVerifyAgainstAnchoredRoot(t *testing.T) {
+	for _, n := range []int{1, 3, 5, 7} {
+		t.Run(fmt.Sprintf("%d certificates", n), func(t *testing.T) {
+			cfg := config.Config{
+				JWTSecret:      "test-secret",
+				InstitutionDID: "did:web:example.edu",
+				PublicAPIURL:   "http://localhost:8080/api",
+				ChainID:        1337,
+			}
+			st := store.NewMemoryStore()
+			admin := testAdmin(t, st)
+
+			keys, err := NewInstitutionKeys(cfg, st)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chain, _ := NewBlockchainService(cfg)
+			certs := &CertificateService{store: st}
+			svc := NewBlockcertsService(cfg, st, NewVCService(cfg, st, certs, keys), chain, keys)
+
+			certIDs := make([]string, n)
+			for i := range certIDs {
+				certIDs[i] = fmt.Sprintf("0x%064x", i+1)
+				if _, err := st.CreateCertificate(models.Certificate{
+					CertID:    certIDs[i],
+					StudentID: fmt.Sprintf("STU%03d", i),
+					IssuerID:  admin.ID.Hex(),
+					CertType:  models.CredentialTypeBonafide,
+					Status:    models.CertStatusIssued,
+					IssuedAt:  time.Now(),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			batch, err := svc.CreateBatch(context.Background(), certIDs, admin.ID.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rpc := httptest.NewServer(anchoringChain(batch))
+			defer rpc.Close()
+			svc.verifier.RPCURL = rpc.URL
+
+			for _, certID := range certIDs {
+				doc, err := svc.Document(certID, admin)
+				if err != nil {
+					t.Fatalf("%s: %v", certID, err)
+				}
+				raw, _ := json.Marshal(doc)
+				result, err := svc.Verify(raw)
+				if err != nil {
+					t.Fatalf("%s: %v", certID, err)
+				}
+				if !result.Valid || result.MerkleRoot != batch.MerkleRoot {
+					t.Fatalf("%s: valid = %v, root %s, checks %+v", certID, result.Valid, result.MerkleRoot, result.Checks)
+				}
+
+				// Any change to the document breaks its inclusion in the anchored root
+				doc.CredentialSubject.StudentID = "STU999"
+				raw, _ = json.Marshal(doc)
+				if result, _ := svc.Verify(raw); result.Valid {
+					t.Fatalf("%s: tampered document verified", certID)
+				}
+			}
+		})
+	}
+}
+
+func testAdmin(t *testing.T, st store.Store) models.User {
+	t.Helper()
+	users, err := st.ListUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		if u.CanPerformAction("can_manage_users") {
+			return u
+		}
+	}
+	t.Fatal("no admin user seeded")
+	return models.User{}
+}
+
+// anchoringChain answers the verifier's JSON-RPC calls for a chain holding the batch's anchoring transaction
+func anchoringChain(batch *models.BlockcertsBatch) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{}
+		switch req.Method {
+		case "eth_chainId":
+			result = "0x539"
+		case "eth_getTransactionByHash":
+			result = map[string]interface{}{"from": batch.IssuerAddress, "input": "0x" + batch.MerkleRoot, "blockNumber": "0x10"}
+		case "eth_getTransactionReceipt":
+			result = map[string]interface{}{"status": "0x1"}
+		case "eth_getBlockByNumber":
+			result = map[string]interface{}{"number": "0x10", "timestamp": "0x65000000"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -13,20 +14,30 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"blockcred-backend/internal/config"
 	"blockcred-backend/internal/models"
 )
 
+// besuValidatorAddress is the genesis validator account used when no issuer address is available
+const besuValidatorAddress = "0x53b8be11aada878bbf830e426d5d3071c34facef"
+
+// anchorBurnAddress receives data-only anchoring transactions, following the Blockcerts convention
+const anchorBurnAddress = "0xdeaddeaddeaddeaddeaddeaddeaddeaddeaddead"
+
 // BesuBlockchainService implements blockchain operations using Hyperledger Besu
 type BesuBlockchainService struct {
 	config       config.Config
 	contractAddr string
 	httpClient   *http.Client
+	rpc          *Dependency
+	keys         *ManagedKey // Signs transactions locally; nil leaves signing to accounts the node holds
 }
 
-// NewBesuBlockchainService creates a new blockchain service connected to Besu
-func NewBesuBlockchainService(cfg config.Config) (*BesuBlockchainService, error) {
+// NewBesuBlockchainService creates a new blockchain service connected to Besu. Transactions are
+// signed with keys when it is set and sent from its account.
+func NewBesuBlockchainService(cfg config.Config, keys *ManagedKey) (*BesuBlockchainService, error) {
 	if cfg.BlockchainRPCURL == "" {
 		return nil, fmt.Errorf("blockchain RPC URL not configured")
 	}
@@ -38,14 +49,21 @@ func NewBesuBlockchainService(cfg config.Config) (*BesuBlockchainService, error)
 	return &BesuBlockchainService{
 		config:       cfg,
 		contractAddr: cfg.ContractAddress,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// Deadlines come from the caller's context and the dependency guard's per-attempt timeout
+		httpClient: &http.Client{},
+		rpc:  dependencyFor(cfg, "blockchain_rpc"),
+		keys: keys,
 	}, nil
 }
 
 // callRPC makes a JSON-RPC call to the Besu node
 func (s *BesuBlockchainService) callRPC(method string, params []interface{}) (*JSONRPCResponse, error) {
+	return s.callRPCContext(context.Background(), method, params)
+}
+
+// callRPCContext makes a JSON-RPC call bounded by ctx. Reads are retried; transactions are only
+// resent when the node was never reached, so a retry cannot submit the same transaction twice.
+func (s *BesuBlockchainService) callRPCContext(ctx context.Context, method string, params []interface{}) (*JSONRPCResponse, error) {
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
 		Method:  method,
@@ -58,15 +76,32 @@ func (s *BesuBlockchainService) callRPC(method string, params []interface{}) (*J
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	resp, err := s.httpClient.Post(s.config.BlockchainRPCURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
 	var response JSONRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	idempotent := !strings.HasPrefix(method, "eth_send")
+	err = s.rpc.Do(ctx, idempotent, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", s.config.BlockchainRPCURL, bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return upstreamTransportError("blockchain_rpc", fmt.Errorf("failed to make request: %w", err))
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return upstreamStatusError("blockchain_rpc", resp.StatusCode, fmt.Errorf("RPC node returned status %d for %s", resp.StatusCode, method))
+		}
+		response = JSONRPCResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return upstreamTransportError("blockchain_rpc", fmt.Errorf("failed to decode response: %w", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if response.Error != nil {
@@ -77,8 +112,8 @@ func (s *BesuBlockchainService) callRPC(method string, params []interface{}) (*J
 }
 
 // getNonce gets the transaction count for an address (nonce)
-func (s *BesuBlockchainService) getNonce(address string) (uint64, error) {
-	response, err := s.callRPC("eth_getTransactionCount", []interface{}{address, "latest"})
+func (s *BesuBlockchainService) getNonce(ctx context.Context, address string) (uint64, error) {
+	response, err := s.callRPCContext(ctx, "eth_getTransactionCount", []interface{}{address, "latest"})
 	if err != nil {
 		return 0, err
 	}
@@ -94,8 +129,8 @@ func (s *BesuBlockchainService) getNonce(address string) (uint64, error) {
 }
 
 // getGasPrice gets the current gas price
-func (s *BesuBlockchainService) getGasPrice() (*big.Int, error) {
-	response, err := s.callRPC("eth_gasPrice", []interface{}{})
+func (s *BesuBlockchainService) getGasPrice(ctx context.Context) (*big.Int, error) {
+	response, err := s.callRPCContext(ctx, "eth_gasPrice", []interface{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -157,11 +192,11 @@ func (s *BesuBlockchainService) encodeIssueCertificate(
 }
 
 // IssueCertificateOnChain issues a certificate with full on-chain data on Besu
-func (s *BesuBlockchainService) IssueCertificateOnChain(data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
+func (s *BesuBlockchainService) IssueCertificateOnChain(ctx context.Context, data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
 	if s.contractAddr == "" {
 		// Contract not deployed - use mock for now
 		fmt.Println("⚠️  Contract not deployed. Using mock transaction.")
-		return s.issueCertificateMock(data, ipfsCID)
+		return s.issueCertificateMock(ctx, data, ipfsCID)
 	}
 
 	// Issue certificate on Hyperledger Besu PoA network with all required on-chain data
@@ -176,11 +211,11 @@ func (s *BesuBlockchainService) IssueCertificateOnChain(data *OnChainCertificate
 	fmt.Printf("   IPFS CID: %s\n", ipfsCID)
 	
 	// Get actual transaction from blockchain
-	txHash, blockNumber, err := s.sendTransaction(data, ipfsCID)
+	txHash, blockNumber, err := s.sendTransaction(ctx, data, ipfsCID)
 	if err != nil {
 		// Fallback to mock if transaction fails
 		fmt.Printf("⚠️  Transaction failed, using mock: %v\n", err)
-		return s.issueCertificateMock(data, ipfsCID)
+		return s.issueCertificateMock(ctx, data, ipfsCID)
 	}
 
 	return &ContractTransaction{
@@ -193,26 +228,14 @@ func (s *BesuBlockchainService) IssueCertificateOnChain(data *OnChainCertificate
 
 // sendTransaction sends a transaction to the Besu network with all on-chain data
 // Stores: Certificate hash, Metadata hash, Issuer address, Timestamp, Student wallet, Revocation flag
-func (s *BesuBlockchainService) sendTransaction(data *OnChainCertificateData, ipfsCID string) (string, uint64, error) {
+func (s *BesuBlockchainService) sendTransaction(ctx context.Context, data *OnChainCertificateData, ipfsCID string) (string, uint64, error) {
 	// Use issuer address as the transaction sender (must be authorized issuer)
 	issuerAddr := data.IssuerAddress
 	if issuerAddr == "" {
 		// Fallback to validator if issuer address not provided
-		issuerAddr = "0x53b8be11aada878bbf830e426d5d3071c34facef"
+		issuerAddr = besuValidatorAddress
 	}
 	
-	// Get nonce
-	nonce, err := s.getNonce(issuerAddr)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err := s.getGasPrice()
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to get gas price: %w", err)
-	}
-
 	// Convert student wallet to common.Address
 	studentWallet := common.HexToAddress(data.StudentWallet)
 	if !common.IsHexAddress(data.StudentWallet) {
@@ -235,37 +258,41 @@ func (s *BesuBlockchainService) sendTransaction(data *OnChainCertificateData, ip
 		return "", 0, fmt.Errorf("failed to encode function call: %w", err)
 	}
 
-	tx := map[string]interface{}{
-		"from":     issuerAddr,
-		"to":       s.contractAddr,
-		"data":     "0x" + txDataHex,
-		"gas":      "0x30d40", // 200000 in hex
-		"gasPrice": fmt.Sprintf("0x%x", gasPrice),
-		"nonce":    fmt.Sprintf("0x%x", nonce),
-		"value":    "0x0",
-	}
-
-	// Send transaction
-	// Note: For Besu, you may need to unlock the account or use eth_sendTransaction with proper authentication
-	response, err := s.callRPC("eth_sendTransaction", []interface{}{tx})
+	txHash, _, _, err := s.sendTx(ctx, issuerAddr, s.contractAddr, txDataHex, 200000)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to send transaction: %w", err)
+		return "", 0, err
 	}
 
-	txHash, ok := response.Result.(string)
-	if !ok {
-		return "", 0, fmt.Errorf("invalid transaction hash response")
+	return txHash, s.waitForReceipt(ctx, txHash), nil
+}
+
+// waitForReceipt polls for the block number of a transaction, returning 0 if it is still pending
+func (s *BesuBlockchainService) waitForReceipt(ctx context.Context, txHash string) uint64 {
+	receipt := s.pollReceipt(ctx, txHash)
+	if receipt == nil {
+		// The transaction is still pending and will be mined in next block
+		fmt.Printf("⚠️  Transaction sent but receipt not available yet. TX: %s\n", txHash)
+		return 0
 	}
+	return receipt.BlockNumber
+}
 
+// pollReceipt waits for a transaction to be mined and returns its receipt, or nil if it is still
+// pending or ctx ended first
+func (s *BesuBlockchainService) pollReceipt(ctx context.Context, txHash string) *TransactionReceipt {
 	// Wait for transaction receipt (Clique PoA has 5 second block period)
 	// Try multiple times with increasing wait time
 	maxRetries := 12 // Up to 60 seconds
 	for i := 0; i < maxRetries; i++ {
-		time.Sleep(5 * time.Second) // Wait for block period
+		select {
+		case <-time.After(5 * time.Second): // Wait for block period
+		case <-ctx.Done():
+			return nil
+		}
 		
-		receipt, err := s.getTransactionReceipt(txHash)
+		receipt, err := s.getTransactionReceipt(ctx, txHash)
 		if err == nil && receipt != nil {
-			return txHash, receipt.BlockNumber, nil
+			return receipt
 		}
 		
 		// If still no receipt, try again
@@ -273,16 +300,27 @@ func (s *BesuBlockchainService) sendTransaction(data *OnChainCertificateData, ip
 			fmt.Printf("   Waiting for block confirmation (attempt %d/%d)...\n", i+1, maxRetries)
 		}
 	}
-	
-	// If receipt still not available, return tx hash with block 0
-	// The transaction is still pending and will be mined in next block
-	fmt.Printf("⚠️  Transaction sent but receipt not available yet. TX: %s\n", txHash)
-	return txHash, 0, nil
+	return nil
+}
+
+// confirmTransaction waits for a transaction to be mined and fails unless it executed successfully
+func (s *BesuBlockchainService) confirmTransaction(ctx context.Context, txHash string) error {
+	receipt := s.pollReceipt(ctx, txHash)
+	if receipt == nil {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("gave up waiting for transaction %s: %w", txHash, err)
+		}
+		return fmt.Errorf("transaction %s was not mined in time", txHash)
+	}
+	if receipt.Status == "0x0" {
+		return fmt.Errorf("transaction %s reverted", txHash)
+	}
+	return nil
 }
 
 // getTransactionReceipt gets the receipt for a transaction
-func (s *BesuBlockchainService) getTransactionReceipt(txHash string) (*TransactionReceipt, error) {
-	response, err := s.callRPC("eth_getTransactionReceipt", []interface{}{txHash})
+func (s *BesuBlockchainService) getTransactionReceipt(ctx context.Context, txHash string) (*TransactionReceipt, error) {
+	response, err := s.callRPCContext(ctx, "eth_getTransactionReceipt", []interface{}{txHash})
 	if err != nil {
 		return nil, err
 	}
@@ -301,9 +339,12 @@ func (s *BesuBlockchainService) getTransactionReceipt(txHash string) (*Transacti
 	blockNumber := new(big.Int)
 	blockNumber.SetString(strings.TrimPrefix(blockNumberHex, "0x"), 16)
 
+	// 0x1 on success, 0x0 when execution reverted
+	status, _ := receiptMap["status"].(string)
+
 	return &TransactionReceipt{
 		BlockNumber: blockNumber.Uint64(),
-		Status:      "0x1", // Success
+		Status:      status,
 	}, nil
 }
 
@@ -314,9 +355,9 @@ type TransactionReceipt struct {
 }
 
 // issueCertificateMock creates a mock transaction when contract is not deployed
-func (s *BesuBlockchainService) issueCertificateMock(data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
+func (s *BesuBlockchainService) issueCertificateMock(ctx context.Context, data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
 	// Get current block number
-	response, err := s.callRPC("eth_blockNumber", []interface{}{})
+	response, err := s.callRPCContext(ctx, "eth_blockNumber", []interface{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +382,7 @@ func (s *BesuBlockchainService) issueCertificateMock(data *OnChainCertificateDat
 }
 
 // IssueCertificate implements BlockchainServiceInterface
-func (s *BesuBlockchainService) IssueCertificate(certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error) {
+func (s *BesuBlockchainService) IssueCertificate(ctx context.Context, certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error) {
 	// Use simplified on-chain data
 	data := &OnChainCertificateData{
 		CertID:         certID,
@@ -350,7 +391,7 @@ func (s *BesuBlockchainService) IssueCertificate(certID, ipfsCID string, certTyp
 		CertType:       certType,
 		Timestamp:      time.Now().Unix(),
 	}
-	return s.IssueCertificateOnChain(data, ipfsCID)
+	return s.IssueCertificateOnChain(ctx, data, ipfsCID)
 }
 
 // VerifyCertificate checks if a certificate exists on the blockchain
@@ -442,7 +483,7 @@ func (s *BesuBlockchainService) GetCertificateOnChain(certID string) (*OnChainCe
 }
 
 // RegisterStudentWallet registers a student-wallet mapping
-func (s *BesuBlockchainService) RegisterStudentWallet(studentID, walletAddress string) error {
+func (s *BesuBlockchainService) RegisterStudentWallet(ctx context.Context, studentID, walletAddress string) error {
 	if s.contractAddr == "" {
 		fmt.Printf("🔗 Besu: Registering student-wallet mapping (mock)\n")
 		fmt.Printf("   Student ID: %s\n", studentID)
@@ -461,14 +502,14 @@ func (s *BesuBlockchainService) RegisterStudentWallet(studentID, walletAddress s
 }
 
 // GetStudentWallet retrieves wallet address for a student
-func (s *BesuBlockchainService) GetStudentWallet(studentID string) (string, error) {
+func (s *BesuBlockchainService) GetStudentWallet(ctx context.Context, studentID string) (string, error) {
 	if s.contractAddr == "" {
 		return "", fmt.Errorf("wallet not found for student %s", studentID)
 	}
 
 	// Call contract's getStudentWallet function
 	// In production, use proper ABI encoding
-	response, err := s.callRPC("eth_call", []interface{}{
+	response, err := s.callRPCContext(ctx, "eth_call", []interface{}{
 		map[string]interface{}{
 			"to":   s.contractAddr,
 			"data": fmt.Sprintf("0x%x", []byte("getStudentWallet:"+studentID)),
@@ -488,7 +529,7 @@ func (s *BesuBlockchainService) GetStudentWallet(studentID string) (string, erro
 }
 
 // RevokeCertificateOnChain revokes a certificate on the blockchain
-func (s *BesuBlockchainService) RevokeCertificateOnChain(certID string) error {
+func (s *BesuBlockchainService) RevokeCertificateOnChain(ctx context.Context, certID string) error {
 	if s.contractAddr == "" {
 		fmt.Printf("🔗 Besu: Revoking certificate %s (mock)\n", certID)
 		return nil
@@ -502,6 +543,226 @@ func (s *BesuBlockchainService) RevokeCertificateOnChain(certID string) error {
 	return nil
 }
 
+// SuspendCertificateOnChain places a certificate on hold on the blockchain
+func (s *BesuBlockchainService) SuspendCertificateOnChain(ctx context.Context, certID, reason string, reinstateAt int64) error {
+	if s.contractAddr == "" {
+		fmt.Printf("🔗 Besu: Suspending certificate %s (mock)\n", certID)
+		return nil
+	}
+
+	fmt.Printf("🔗 Besu: Suspending certificate %s\n", certID)
+	fmt.Printf("   Reason: %s\n", reason)
+	fmt.Printf("   Reinstate At: %d\n", reinstateAt)
+
+	// suspendCertificate(string _certId, string _reason, uint256 _reinstateAt)
+	txData, err := packContractCall(`[{
+		"name": "suspendCertificate",
+		"type": "function",
+		"inputs": [
+			{"name": "_certId", "type": "string"},
+			{"name": "_reason", "type": "string"},
+			{"name": "_reinstateAt", "type": "uint256"}
+		]
+	}]`, "suspendCertificate", certID, reason, big.NewInt(reinstateAt))
+	if err != nil {
+		return err
+	}
+
+	txHash, err := s.sendContractTransaction(ctx, besuValidatorAddress, txData)
+	if err != nil {
+		return fmt.Errorf("failed to suspend certificate on-chain: %w", err)
+	}
+	fmt.Printf("   TX: %s\n", txHash)
+	return nil
+}
+
+// ReinstateCertificateOnChain lifts a suspension on the blockchain
+func (s *BesuBlockchainService) ReinstateCertificateOnChain(ctx context.Context, certID string) error {
+	if s.contractAddr == "" {
+		fmt.Printf("🔗 Besu: Reinstating certificate %s (mock)\n", certID)
+		return nil
+	}
+
+	fmt.Printf("🔗 Besu: Reinstating certificate %s\n", certID)
+
+	// reinstateCertificate(string _certId)
+	txData, err := packContractCall(`[{
+		"name": "reinstateCertificate",
+		"type": "function",
+		"inputs": [
+			{"name": "_certId", "type": "string"}
+		]
+	}]`, "reinstateCertificate", certID)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := s.sendContractTransaction(ctx, besuValidatorAddress, txData)
+	if err != nil {
+		return fmt.Errorf("failed to reinstate certificate on-chain: %w", err)
+	}
+	fmt.Printf("   TX: %s\n", txHash)
+	return nil
+}
+
+// SupersedeCertificateOnChain links an old certificate to the version that replaces it
+func (s *BesuBlockchainService) SupersedeCertificateOnChain(ctx context.Context, oldCertID, newCertID string) error {
+	if s.contractAddr == "" {
+		fmt.Printf("🔗 Besu: Certificate %s superseded by %s (mock)\n", oldCertID, newCertID)
+		return nil
+	}
+
+	fmt.Printf("🔗 Besu: Certificate %s superseded by %s\n", oldCertID, newCertID)
+
+	// supersedeCertificate(string _oldCertId, string _newCertId)
+	txData, err := packContractCall(`[{
+		"name": "supersedeCertificate",
+		"type": "function",
+		"inputs": [
+			{"name": "_oldCertId", "type": "string"},
+			{"name": "_newCertId", "type": "string"}
+		]
+	}]`, "supersedeCertificate", oldCertID, newCertID)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := s.sendContractTransaction(ctx, besuValidatorAddress, txData)
+	if err != nil {
+		return fmt.Errorf("failed to supersede certificate on-chain: %w", err)
+	}
+	fmt.Printf("   TX: %s\n", txHash)
+	return nil
+}
+
+// AnchorMerkleRoot writes a Blockcerts batch root as the data of a transaction to the burn address.
+// Unlike certificate calls this needs no contract, so it never falls back to a mock transaction.
+func (s *BesuBlockchainService) AnchorMerkleRoot(ctx context.Context, merkleRoot string) (*ContractTransaction, error) {
+	fmt.Printf("🔗 Besu: Anchoring Merkle root %s\n", merkleRoot)
+
+	// 30000 gas: 21000 base plus 32 bytes of calldata
+	txHash, from, gasPrice, err := s.sendTx(ctx, besuValidatorAddress, anchorBurnAddress, merkleRoot, 30000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anchor merkle root: %w", err)
+	}
+	fmt.Printf("   TX: %s\n", txHash)
+
+	return &ContractTransaction{
+		TxHash:      txHash,
+		BlockNumber: s.waitForReceipt(ctx, txHash),
+		GasUsed:     21512,
+		GasPrice:    gasPrice.String(),
+		From:        common.HexToAddress(from).Hex(),
+	}, nil
+}
+
+// packContractCall ABI-encodes a single contract function call and returns it hex-encoded
+func packContractCall(abiJSON, method string, args ...interface{}) (string, error) {
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	packed, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack arguments: %w", err)
+	}
+
+	return hex.EncodeToString(packed), nil
+}
+
+// sendContractTransaction sends an encoded call to the certificate contract and waits for its
+// receipt, failing if the call was not mined or reverted so callers never record an unapplied change
+func (s *BesuBlockchainService) sendContractTransaction(ctx context.Context, from, txDataHex string) (string, error) {
+	txHash, _, _, err := s.sendTx(ctx, from, s.contractAddr, txDataHex, 200000)
+	if err != nil {
+		return "", err
+	}
+	return txHash, s.confirmTransaction(ctx, txHash)
+}
+
+// sendTx submits a transaction and returns its hash, the sending account and the gas price paid.
+// With a blockchain signing key the transaction is signed here and sent raw from the key's
+// account; otherwise the node signs it for from, which it must hold unlocked.
+func (s *BesuBlockchainService) sendTx(ctx context.Context, from, to, dataHex string, gas uint64) (string, string, *big.Int, error) {
+	var signer Signer
+	if s.keys != nil {
+		_, signer = s.keys.Current()
+		from, _ = ethereumAddress(signer)
+	}
+
+	nonce, err := s.getNonce(ctx, from)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := s.getGasPrice(ctx)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	var response *JSONRPCResponse
+	if signer == nil {
+		tx := map[string]interface{}{
+			"from":     from,
+			"to":       to,
+			"data":     "0x" + strings.TrimPrefix(dataHex, "0x"),
+			"gas":      fmt.Sprintf("0x%x", gas),
+			"gasPrice": fmt.Sprintf("0x%x", gasPrice),
+			"nonce":    fmt.Sprintf("0x%x", nonce),
+			"value":    "0x0",
+		}
+		response, err = s.callRPCContext(ctx, "eth_sendTransaction", []interface{}{tx})
+	} else {
+		var raw string
+		if raw, err = s.signTx(signer, nonce, gasPrice, gas, to, dataHex); err != nil {
+			return "", "", nil, err
+		}
+		response, err = s.callRPCContext(ctx, "eth_sendRawTransaction", []interface{}{raw})
+	}
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	txHash, ok := response.Result.(string)
+	if !ok {
+		return "", "", nil, fmt.Errorf("invalid transaction hash response")
+	}
+	return txHash, from, gasPrice, nil
+}
+
+// signTx builds an EIP-155 legacy transaction and returns it signed and RLP-encoded
+func (s *BesuBlockchainService) signTx(signer Signer, nonce uint64, gasPrice *big.Int, gas uint64, to, dataHex string) (string, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(dataHex, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("invalid transaction data: %w", err)
+	}
+	toAddr := common.HexToAddress(to)
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       &toAddr,
+		Value:    big.NewInt(0),
+		Data:     data,
+	})
+
+	txSigner := types.NewEIP155Signer(big.NewInt(s.config.ChainID))
+	hash := txSigner.Hash(tx)
+	signature, err := signEthereumHash(signer, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	signed, err := tx.WithSignature(txSigner, signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return "0x" + hex.EncodeToString(raw), nil
+}
+
 // ComputeCertID computes the certificate ID using SHA256(fileHash + studentId + issuedAt)
 func (s *BesuBlockchainService) ComputeCertID(fileHash, studentID string, issuedAt time.Time) string {
 	input := fileHash + studentID + issuedAt.Format(time.RFC3339)
@@ -546,7 +807,7 @@ func (s *BesuBlockchainService) GetBlockNumber() (uint64, error) {
 
 // GetGasPrice gets the current gas price
 func (s *BesuBlockchainService) GetGasPrice() (*big.Int, error) {
-	return s.getGasPrice()
+	return s.getGasPrice(context.Background())
 }
 
 // RegisterIssuer registers an issuer on the blockchain
@@ -2,9 +2,11 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -14,6 +16,10 @@ import (
 	"blockcred-backend/internal/models"
 )
 
+// ErrOnChainUnsupported is returned for contract calls the GoEth service does not implement, so
+// callers do not record a change that never reached the chain
+var ErrOnChainUnsupported = errors.New("operation not supported by the GoEth blockchain service")
+
 // GoEthBlockchainService implements blockchain operations using GoEth
 type GoEthBlockchainService struct {
 	config        config.Config
@@ -95,7 +101,7 @@ func (s *GoEthBlockchainService) callContract(method string, params []interface{
 }
 
 // IssueCertificate calls the smart contract to issue a certificate (implements BlockchainServiceInterface)
-func (s *GoEthBlockchainService) IssueCertificate(certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error) {
+func (s *GoEthBlockchainService) IssueCertificate(ctx context.Context, certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error) {
 	// Generate a mock transaction hash for development
 	txHash := fmt.Sprintf("0x%x", time.Now().UnixNano())
 	blockNumber := uint64(time.Now().Unix() % 1000000)
@@ -122,7 +128,7 @@ func (s *GoEthBlockchainService) IssueCertificateWithDetails(certID, ipfsCID str
 	fmt.Printf("   Issuer: %s\n", issuerAddress)
 
 	// Use the standard IssueCertificate method
-	return s.IssueCertificate(certID, ipfsCID, certType)
+	return s.IssueCertificate(context.Background(), certID, ipfsCID, certType)
 }
 
 // VerifyCertificate checks if a certificate exists on the blockchain
@@ -237,7 +243,7 @@ func (s *GoEthBlockchainService) GetGasPrice() (*big.Int, error) {
 }
 
 // IssueCertificateOnChain issues a certificate with full on-chain data
-func (s *GoEthBlockchainService) IssueCertificateOnChain(data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
+func (s *GoEthBlockchainService) IssueCertificateOnChain(ctx context.Context, data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error) {
 	// In production, this would call the smart contract's issueCertificate function
 	// with all the on-chain data: credential hash, metadata hash, issuer address, student wallet, timestamp
 	
@@ -285,7 +291,7 @@ func (s *GoEthBlockchainService) GetCertificateOnChain(certID string) (*OnChainC
 }
 
 // RegisterStudentWallet registers a student-wallet mapping
-func (s *GoEthBlockchainService) RegisterStudentWallet(studentID, walletAddress string) error {
+func (s *GoEthBlockchainService) RegisterStudentWallet(ctx context.Context, studentID, walletAddress string) error {
 	// TODO: Call smart contract registerStudentWallet function
 	fmt.Printf("🔗 Blockchain: Registering student-wallet mapping\n")
 	fmt.Printf("   Student ID: %s\n", studentID)
@@ -294,19 +300,40 @@ func (s *GoEthBlockchainService) RegisterStudentWallet(studentID, walletAddress
 }
 
 // GetStudentWallet retrieves wallet address for a student
-func (s *GoEthBlockchainService) GetStudentWallet(studentID string) (string, error) {
+func (s *GoEthBlockchainService) GetStudentWallet(ctx context.Context, studentID string) (string, error) {
 	// TODO: Call smart contract getStudentWallet function
 	// For now, return empty (will trigger wallet generation)
 	return "", fmt.Errorf("wallet not found for student %s", studentID)
 }
 
 // RevokeCertificateOnChain revokes a certificate on the blockchain
-func (s *GoEthBlockchainService) RevokeCertificateOnChain(certID string) error {
+func (s *GoEthBlockchainService) RevokeCertificateOnChain(ctx context.Context, certID string) error {
 	// TODO: Call smart contract revokeCertificate function
 	fmt.Printf("🔗 Blockchain: Revoking certificate %s\n", certID)
 	return nil
 }
 
+// SuspendCertificateOnChain places a certificate on hold on the blockchain
+func (s *GoEthBlockchainService) SuspendCertificateOnChain(ctx context.Context, certID, reason string, reinstateAt int64) error {
+	return fmt.Errorf("%w: suspendCertificate", ErrOnChainUnsupported)
+}
+
+// ReinstateCertificateOnChain lifts a suspension on the blockchain
+func (s *GoEthBlockchainService) ReinstateCertificateOnChain(ctx context.Context, certID string) error {
+	return fmt.Errorf("%w: reinstateCertificate", ErrOnChainUnsupported)
+}
+
+// SupersedeCertificateOnChain links an old certificate to the version that replaces it
+func (s *GoEthBlockchainService) SupersedeCertificateOnChain(ctx context.Context, oldCertID, newCertID string) error {
+	return fmt.Errorf("%w: supersedeCertificate", ErrOnChainUnsupported)
+}
+
+// AnchorMerkleRoot writes a Blockcerts batch root to the chain. The GoEth service sends no data
+// transactions, so a batch is never recorded against a hash that is not on chain.
+func (s *GoEthBlockchainService) AnchorMerkleRoot(ctx context.Context, merkleRoot string) (*ContractTransaction, error) {
+	return nil, fmt.Errorf("%w: anchorMerkleRoot", ErrOnChainUnsupported)
+}
+
 // Close closes the blockchain connection
 func (s *GoEthBlockchainService) Close() {
 	// Close HTTP client if needed
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"time"
 
 	"blockcred-backend/internal/models"
@@ -12,6 +13,7 @@ type ContractTransaction struct {
 	BlockNumber uint64 `json:"block_number"`
 	GasUsed     uint64 `json:"gas_used"`
 	GasPrice    string `json:"gas_price"`
+	From        string `json:"from,omitempty"` // Sending account, set for anchoring transactions
 }
 
 // OnChainCertificateData contains all data to be stored on-chain
@@ -20,23 +22,28 @@ type OnChainCertificateData struct {
 	StudentID      string
 	StudentWallet  string
 	CredentialHash string // SHA-256 hash of certificate file
-	MetadataHash   string // SHA-256 hash of metadata JSON
+	MetadataHash   string // SHA-256 commitment over the salted metadata field disclosures
 	IssuerAddress  string
 	CertType       models.CredentialType
 	Timestamp      int64
 }
 
-// BlockchainServiceInterface defines the interface for blockchain operations
+// BlockchainServiceInterface defines the interface for blockchain operations. Calls that write to
+// the chain or sit on the issuance path take the caller's context so a cancelled request stops them.
 type BlockchainServiceInterface interface {
-	IssueCertificate(certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error)
-	IssueCertificateOnChain(data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error)
+	IssueCertificate(ctx context.Context, certID, ipfsCID string, certType models.CredentialType) (*ContractTransaction, error)
+	IssueCertificateOnChain(ctx context.Context, data *OnChainCertificateData, ipfsCID string) (*ContractTransaction, error)
 	VerifyCertificate(certID string) (bool, error)
 	ComputeCertID(fileHash, studentID string, issuedAt time.Time) string
 	GetCertificateInfo(certID string) (map[string]interface{}, error)
 	GetCertificateOnChain(certID string) (*OnChainCertificateData, error)
-	RegisterStudentWallet(studentID, walletAddress string) error
-	GetStudentWallet(studentID string) (string, error)
-	RevokeCertificateOnChain(certID string) error
+	RegisterStudentWallet(ctx context.Context, studentID, walletAddress string) error
+	GetStudentWallet(ctx context.Context, studentID string) (string, error)
+	RevokeCertificateOnChain(ctx context.Context, certID string) error
+	SuspendCertificateOnChain(ctx context.Context, certID, reason string, reinstateAt int64) error
+	ReinstateCertificateOnChain(ctx context.Context, certID string) error
+	SupersedeCertificateOnChain(ctx context.Context, oldCertID, newCertID string) error
+	AnchorMerkleRoot(ctx context.Context, merkleRoot string) (*ContractTransaction, error)
 	Close()
 }
 
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// maxBulkDocumentSize caps each document extracted from the archive
+const maxBulkDocumentSize = 20 << 20
+
+// Manifest columns with a dedicated meaning; anything else lands in AdditionalData
+var bulkReservedColumns = map[string]bool{
+	"student_id": true, "cert_type": true, "file_name": true,
+	"student_name": true, "student_email": true, "institution": true, "department": true,
+	"course": true, "semester": true, "academic_year": true, "grade": true, "cgpa": true,
+	"valid_from": true, "valid_until": true, "description": true,
+}
+
+// BulkIssuanceService issues certificates from a manifest plus a ZIP of documents
+type BulkIssuanceService struct {
+	store        store.Store
+	certificates *CertificateService
+	approvals    *ApprovalService
+	uploadDir    string
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewBulkIssuanceService(cfg config.Config, s store.Store, certificates *CertificateService, approvals *ApprovalService) *BulkIssuanceService {
+	return &BulkIssuanceService{
+		store:        s,
+		certificates: certificates,
+		approvals:    approvals,
+		uploadDir:    cfg.BulkUploadDir,
+		running:      make(map[string]bool),
+	}
+}
+
+// Validate runs the dry-run pass: every row is checked but nothing is stored or issued
+func (b *BulkIssuanceService) Validate(manifestName string, manifest, archive []byte, issuerID string) (*models.BulkIssuanceJob, error) {
+	rows, err := b.validate(manifestName, manifest, archive, issuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &models.BulkIssuanceJob{
+		IssuerID:     issuerID,
+		ManifestName: manifestName,
+		Status:       models.BulkJobValidated,
+		Rows:         rows,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	job.Tally()
+	return job, nil
+}
+
+// Start validates the upload, persists the job and processes valid rows in the background
+func (b *BulkIssuanceService) Start(manifestName string, manifest, archive []byte, issuerID string) (*models.BulkIssuanceJob, error) {
+	rows, err := b.validate(manifestName, manifest, archive, issuerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(b.uploadDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to prepare upload directory: %w", err)
+	}
+	archiveFile, err := os.CreateTemp(b.uploadDir, "bulk-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document archive: %w", err)
+	}
+	if _, err := archiveFile.Write(archive); err != nil {
+		archiveFile.Close()
+		os.Remove(archiveFile.Name())
+		return nil, fmt.Errorf("failed to store document archive: %w", err)
+	}
+	archiveFile.Close()
+
+	now := time.Now()
+	job := models.BulkIssuanceJob{
+		IssuerID:     issuerID,
+		ManifestName: manifestName,
+		ArchivePath:  archiveFile.Name(),
+		Status:       models.BulkJobQueued,
+		Rows:         rows,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	job.Tally()
+
+	created, err := b.store.CreateBulkJob(job)
+	if err != nil {
+		os.Remove(archiveFile.Name())
+		return nil, fmt.Errorf("failed to save bulk job: %w", err)
+	}
+
+	b.launch(created)
+	return &created, nil
+}
+
+// Resume re-processes rows that are still pending or failed, e.g. after a restart or an outage
+func (b *BulkIssuanceService) Resume(jobID, userID string) (*models.BulkIssuanceJob, error) {
+	job, err := b.GetJob(jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IssuerID != userID {
+		return nil, fmt.Errorf("%w: only the submitting issuer can resume a job", ErrPermissionDenied)
+	}
+	if job.Status == models.BulkJobCompleted {
+		return nil, fmt.Errorf("bulk job already completed")
+	}
+
+	b.launch(*job)
+	return job, nil
+}
+
+// launch starts processing unless the job is already running in this process
+func (b *BulkIssuanceService) launch(job models.BulkIssuanceJob) {
+	id := job.ID.Hex()
+
+	b.mu.Lock()
+	if b.running[id] {
+		b.mu.Unlock()
+		return
+	}
+	b.running[id] = true
+	b.mu.Unlock()
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			delete(b.running, id)
+			b.mu.Unlock()
+		}()
+		b.process(job)
+	}()
+}
+
+// process pushes each pending row through the issuance pipeline, saving progress after every row
+func (b *BulkIssuanceService) process(job models.BulkIssuanceJob) {
+	id := job.ID.Hex()
+	log.Printf("📦 Bulk job %s: processing %d rows", id, len(job.Rows))
+	b.reconcile(&job)
+
+	zr, err := zip.OpenReader(job.ArchivePath)
+	if err != nil {
+		log.Printf("❌ Bulk job %s: cannot open document archive: %v", id, err)
+		for i := range job.Rows {
+			if job.Rows[i].Status == models.BulkRowValid {
+				job.Rows[i].Status = models.BulkRowFailed
+				job.Rows[i].Errors = []string{"document archive unavailable"}
+			}
+		}
+		b.finish(&job)
+		return
+	}
+	defer zr.Close()
+	documents := indexArchive(&zr.Reader)
+
+	job.Status = models.BulkJobProcessing
+	b.save(&job)
+
+	for i := range job.Rows {
+		row := &job.Rows[i]
+		if row.Status != models.BulkRowValid && row.Status != models.BulkRowFailed {
+			continue
+		}
+
+		if err := b.processRow(&job, row, documents); err != nil {
+			row.Status = models.BulkRowFailed
+			row.Errors = []string{err.Error()}
+		} else {
+			row.Errors = nil
+		}
+		b.save(&job)
+	}
+
+	b.finish(&job)
+}
+
+// reconcile settles rows an interrupted run left mid-issuance. A certificate stored under the
+// reserved cert ID means issuance finished; otherwise the row is retried under the same reservation.
+func (b *BulkIssuanceService) reconcile(job *models.BulkIssuanceJob) {
+	for i := range job.Rows {
+		row := &job.Rows[i]
+		if row.CertID == "" || (row.Status != models.BulkRowProcessing && row.Status != models.BulkRowFailed) {
+			continue
+		}
+		if cert, err := b.store.GetCertificateByCertID(row.CertID); err == nil {
+			row.Status = models.BulkRowIssued
+			row.TxHash = cert.TxHash
+			row.Errors = nil
+		} else if row.Status == models.BulkRowProcessing {
+			row.Status = models.BulkRowFailed
+			row.Errors = []string{"interrupted before the certificate was stored"}
+		}
+	}
+}
+
+func (b *BulkIssuanceService) processRow(job *models.BulkIssuanceJob, row *models.BulkIssuanceRow, documents map[string]*zip.File) error {
+	issuerID := job.IssuerID
+	f, ok := documents[strings.ToLower(row.FileName)]
+	if !ok {
+		return fmt.Errorf("document %s not found in archive", row.FileName)
+	}
+	data, err := readArchiveFile(f)
+	if err != nil {
+		return err
+	}
+
+	req := models.IssueCertificateRequest{
+		StudentID: row.StudentID,
+		CertType:  row.CertType,
+		FileData:  data,
+		FileName:  row.FileName,
+		Metadata:  row.Metadata,
+	}
+
+	if b.approvals != nil && b.approvals.RequiresApproval(row.CertType) {
+		draft, err := b.approvals.CreateDraft(context.Background(), models.CreateDraftRequest{IssueCertificateRequest: req, Submit: true}, issuerID)
+		if err != nil {
+			return err
+		}
+		row.Status = models.BulkRowSubmitted
+		row.DraftID = draft.ID.Hex()
+		return nil
+	}
+	if b.certificates.requiresApproval(row.CertType) {
+		return fmt.Errorf("%w: %s certificates must be submitted as drafts", ErrApprovalRequired, row.CertType)
+	}
+
+	// The cert ID is reserved and saved before issuing, so a resumed job can tell whether a row
+	// that was interrupted mid-issuance already produced its certificate
+	if row.CertID == "" || row.IssuedAt == nil {
+		issuedAt := time.Now()
+		row.CertID = b.certificates.blockchainService.ComputeCertID(b.certificates.computeFileHash(data), row.StudentID, issuedAt)
+		row.IssuedAt = &issuedAt
+	}
+	row.Status = models.BulkRowProcessing
+	b.save(job)
+
+	cert, err := b.certificates.issueCertificate(context.Background(), req, issuerID, issueOptions{certID: row.CertID, issuedAt: *row.IssuedAt})
+	if err != nil {
+		return err
+	}
+	row.Status = models.BulkRowIssued
+	row.TxHash = cert.TxHash
+	return nil
+}
+
+func (b *BulkIssuanceService) save(job *models.BulkIssuanceJob) {
+	job.Tally()
+	job.UpdatedAt = time.Now()
+	if _, err := b.store.UpdateBulkJob(job.ID.Hex(), *job); err != nil {
+		log.Printf("⚠️  Bulk job %s: failed to save progress: %v", job.ID.Hex(), err)
+	}
+}
+
+func (b *BulkIssuanceService) finish(job *models.BulkIssuanceJob) {
+	job.Tally()
+	now := time.Now()
+	job.CompletedAt = &now
+	if job.Failed > 0 {
+		// Keep the archive so the failed rows can be resumed
+		job.Status = models.BulkJobCompletedWithError
+	} else {
+		job.Status = models.BulkJobCompleted
+		if job.ArchivePath != "" {
+			os.Remove(job.ArchivePath)
+		}
+	}
+	b.save(job)
+	log.Printf("📦 Bulk job %s: %s (%d issued, %d submitted, %d failed, %d invalid)",
+		job.ID.Hex(), job.Status, job.Issued, job.Submitted, job.Failed, job.Invalid)
+}
+
+// GetJob returns a job visible to the user: its issuer or anyone who can view all credentials
+func (b *BulkIssuanceService) GetJob(jobID, userID string) (*models.BulkIssuanceJob, error) {
+	job, err := b.store.GetBulkJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.IssuerID != userID {
+		user, err := b.store.GetUserByID(userID)
+		if err != nil || !user.CanPerformAction("can_view_all_credentials") {
+			return nil, fmt.Errorf("%w: user cannot view this bulk job", ErrPermissionDenied)
+		}
+	}
+	return &job, nil
+}
+
+// ListJobs returns the bulk jobs submitted by an issuer
+func (b *BulkIssuanceService) ListJobs(issuerID string) ([]models.BulkIssuanceJob, error) {
+	return b.store.ListBulkJobsByIssuer(issuerID)
+}
+
+// ResultCSV renders the per-row outcome, including cert IDs and tx hashes, as CSV
+func (b *BulkIssuanceService) ResultCSV(job *models.BulkIssuanceJob) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"row", "student_id", "cert_type", "file_name", "status", "cert_id", "tx_hash", "draft_id", "errors"})
+	for _, row := range job.Rows {
+		// Failed rows keep their reserved cert ID for the next attempt, but no certificate has it yet
+		certID := row.CertID
+		if row.Status != models.BulkRowIssued {
+			certID = ""
+		}
+		_ = w.Write([]string{
+			strconv.Itoa(row.RowNumber),
+			row.StudentID,
+			string(row.CertType),
+			row.FileName,
+			string(row.Status),
+			certID,
+			row.TxHash,
+			row.DraftID,
+			strings.Join(row.Errors, "; "),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write result file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// validate parses the manifest and checks every row against the archive, the user store and issuer permissions
+func (b *BulkIssuanceService) validate(manifestName string, manifest, archive []byte, issuerID string) ([]models.BulkIssuanceRow, error) {
+	issuer, err := b.store.GetUserByID(issuerID)
+	if err != nil {
+		return nil, fmt.Errorf("issuer not found: %w", err)
+	}
+
+	records, err := ParseManifest(manifestName, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document archive: %w", err)
+	}
+	documents := indexArchive(zr)
+
+	seen := map[string]int{}
+	rows := make([]models.BulkIssuanceRow, 0, len(records))
+	for _, line := range records {
+		record := line.Record
+		row := models.BulkIssuanceRow{
+			RowNumber: line.Number,
+			StudentID: record["student_id"],
+			CertType:  models.CredentialType(record["cert_type"]),
+			FileName:  record["file_name"],
+			Status:    models.BulkRowValid,
+		}
+		if row.FileName == "" && row.StudentID != "" {
+			row.FileName = row.StudentID + ".pdf"
+		}
+
+		var errs []string
+		if row.StudentID == "" {
+			errs = append(errs, "student_id is required")
+		} else if student, err := b.store.GetUserByStudentID(row.StudentID); err != nil {
+			errs = append(errs, "student not found")
+		} else {
+			row.Metadata.StudentName = student.Name
+			row.Metadata.StudentEmail = student.Email
+			row.Metadata.Department = student.Department
+		}
+
+		if row.CertType == "" {
+			errs = append(errs, "cert_type is required")
+		} else if !b.certificates.canIssueCertificate(issuer.Role, row.CertType) {
+			errs = append(errs, fmt.Sprintf("issuer cannot issue %s certificates", row.CertType))
+		}
+
+		if f, ok := documents[strings.ToLower(row.FileName)]; !ok {
+			errs = append(errs, fmt.Sprintf("document %s not found in archive", row.FileName))
+		} else if f.UncompressedSize64 == 0 {
+			errs = append(errs, fmt.Sprintf("document %s is empty", row.FileName))
+		} else if f.UncompressedSize64 > maxBulkDocumentSize {
+			errs = append(errs, fmt.Sprintf("document %s exceeds %d MB", row.FileName, maxBulkDocumentSize>>20))
+		}
+
+		errs = append(errs, applyManifestMetadata(&row.Metadata, record)...)
+		row.Metadata.IssuerName = issuer.Name
+		row.Metadata.IssuerRole = issuer.Role
+		if row.Metadata.Institution == "" {
+			row.Metadata.Institution = issuer.Institution
+		}
+
+		key := strings.Join([]string{row.StudentID, string(row.CertType), row.Metadata.Semester}, "|")
+		if first, dup := seen[key]; dup {
+			errs = append(errs, fmt.Sprintf("duplicate of row %d (same student, type and semester)", first))
+		} else {
+			seen[key] = row.RowNumber
+		}
+
+		if len(errs) > 0 {
+			row.Status = models.BulkRowInvalid
+			row.Errors = errs
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// applyManifestMetadata copies manifest columns into certificate metadata and returns parse errors
+func applyManifestMetadata(meta *models.CertificateMetadata, record ManifestRecord) []string {
+	var errs []string
+
+	if v := record["student_name"]; v != "" {
+		meta.StudentName = v
+	}
+	if v := record["student_email"]; v != "" {
+		meta.StudentEmail = v
+	}
+	if v := record["department"]; v != "" {
+		meta.Department = v
+	}
+	meta.Institution = record["institution"]
+	meta.Course = record["course"]
+	meta.Semester = record["semester"]
+	meta.AcademicYear = record["academic_year"]
+	meta.Grade = record["grade"]
+	meta.Description = record["description"]
+
+	if v := record["cgpa"]; v != "" {
+		cgpa, err := strconv.ParseFloat(v, 64)
+		if err != nil || cgpa < 0 || cgpa > 10 {
+			errs = append(errs, fmt.Sprintf("invalid cgpa %q", v))
+		} else {
+			meta.CGPA = cgpa
+		}
+	}
+
+	var err error
+	if v := record["valid_from"]; v != "" {
+		if meta.ValidFrom, err = parseManifestDate(v); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid valid_from %q", v))
+		}
+	}
+	if v := record["valid_until"]; v != "" {
+		if meta.ValidUntil, err = parseManifestDate(v); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid valid_until %q", v))
+		}
+	}
+
+	for key, value := range record {
+		if bulkReservedColumns[key] || value == "" {
+			continue
+		}
+		if meta.AdditionalData == nil {
+			meta.AdditionalData = make(map[string]interface{})
+		}
+		meta.AdditionalData[key] = value
+	}
+	return errs
+}
+
+// parseManifestDate accepts ISO dates, RFC 3339 timestamps and Excel serial day numbers
+func parseManifestDate(v string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "02/01/2006"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(v, 64); err == nil && serial > 0 {
+		excelEpoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return excelEpoch.Add(time.Duration(serial * 24 * float64(time.Hour))), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date")
+}
+
+// indexArchive maps lower-cased base file names to archive entries
+func indexArchive(zr *zip.Reader) map[string]*zip.File {
+	documents := map[string]*zip.File{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		documents[strings.ToLower(filepath.Base(f.Name))] = f
+	}
+	return documents
+}
+
+func readArchiveFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxBulkDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if len(data) > maxBulkDocumentSize {
+		return nil, fmt.Errorf("document %s exceeds %d MB", f.Name, maxBulkDocumentSize>>20)
+	}
+	return data, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+func TestBulkResumeReconcilesInterruptedRows(t *testing.T) {
+	st := store.NewMemoryStore()
+	if _, err := st.CreateCertificate(models.Certificate{CertID: "0xstored", TxHash: "0xtx", Status: models.CertStatusIssued}); err != nil {
+		t.Fatal(err)
+	}
+	b := &BulkIssuanceService{store: st}
+
+	reservedAt := time.Now()
+	job := models.BulkIssuanceJob{Rows: []models.BulkIssuanceRow{
+		{RowNumber: 2, Status: models.BulkRowProcessing, CertID: "0xstored", IssuedAt: &reservedAt},
+		{RowNumber: 3, Status: models.BulkRowProcessing, CertID: "0xlost", IssuedAt: &reservedAt},
+		{RowNumber: 4, Status: models.BulkRowFailed, CertID: "0xstored", IssuedAt: &reservedAt, Errors: []string{"timeout"}},
+		{RowNumber: 5, Status: models.BulkRowFailed, CertID: "0xlost", IssuedAt: &reservedAt, Errors: []string{"timeout"}},
+		{RowNumber: 6, Status: models.BulkRowValid},
+	}}
+	b.reconcile(&job)
+
+	want := []struct {
+		status models.BulkRowStatus
+		txHash string
+		certID string
+	}{
+		{models.BulkRowIssued, "0xtx", "0xstored"},
+		{models.BulkRowFailed, "", "0xlost"}, // Retried under the same reservation
+		{models.BulkRowIssued, "0xtx", "0xstored"},
+		{models.BulkRowFailed, "", "0xlost"},
+		{models.BulkRowValid, "", ""},
+	}
+	for i, row := range job.Rows {
+		if row.Status != want[i].status || row.TxHash != want[i].txHash || row.CertID != want[i].certID {
+			t.Errorf("row %d: %s, tx %q, cert %q; want %s, tx %q, cert %q",
+				row.RowNumber, row.Status, row.TxHash, row.CertID, want[i].status, want[i].txHash, want[i].certID)
+		}
+		if row.Status == models.BulkRowIssued && row.Errors != nil {
+			t.Errorf("row %d: issued with errors %v", row.RowNumber, row.Errors)
+		}
+	}
+}
+
+// zipFiles builds a ZIP archive holding the named files
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testXLSX is a workbook whose sheet leaves out row 3 and has a row of empty cells at row 5,
+// mixing shared and inline strings
+func testXLSX(t *testing.T) []byte {
+	return zipFiles(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Manifest" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/manifest.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>Student ID</t></si><si><t>Cert Type</t></si><si><r><t>bona</t></r><r><t>fide</t></r></si></sst>`,
+		"xl/worksheets/manifest.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>CGPA</t></is></c></row>` +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>STU001</t></is></c><c r="B2" t="s"><v>2</v></c><c r="C2"><v>8.5</v></c></row>` +
+			`<row r="4"><c r="A4" t="inlineStr"><is><t>STU002</t></is></c><c r="C4"><v>9</v></c></row>` +
+			`<row r="5"><c r="A5" t="inlineStr"><is><t> </t></is></c></row>` +
+			`<row r="6"><c r="B6" t="s"><v>2</v></c></row>` +
+			`</sheetData></worksheet>`,
+	})
+}
+
+func TestParseManifest(t *testing.T) {
+	cases := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     []ManifestRow
+		wantErr  string
+	}{
+		{
+			name:     "csv",
+			fileName: "manifest.csv",
+			data:     []byte("\ufeffStudent ID,cert-type, Grade\nSTU001,bonafide, A \nSTU002,noc\n"),
+			want: []ManifestRow{
+				{2, ManifestRecord{"student_id": "STU001", "cert_type": "bonafide", "grade": "A"}},
+				{3, ManifestRecord{"student_id": "STU002", "cert_type": "noc"}},
+			},
+		},
+		{
+			name:     "csv blank rows keep source numbers",
+			fileName: "MANIFEST.CSV",
+			data:     []byte("student_id,cert_type\n\nSTU001,bonafide\n,\n  ,  \nSTU002,noc\n"),
+			want: []ManifestRow{
+				{3, ManifestRecord{"student_id": "STU001", "cert_type": "bonafide"}},
+				{6, ManifestRecord{"student_id": "STU002", "cert_type": "noc"}},
+			},
+		},
+		{
+			name:     "csv quoted field spanning lines",
+			fileName: "manifest.csv",
+			data:     []byte("student_id,description\nSTU001,\"first\nsecond\"\nSTU002,x\n"),
+			want: []ManifestRow{
+				{2, ManifestRecord{"student_id": "STU001", "description": "first\nsecond"}},
+				{4, ManifestRecord{"student_id": "STU002", "description": "x"}},
+			},
+		},
+		{
+			name:     "csv duplicate rows are kept for validation",
+			fileName: "manifest.csv",
+			data:     []byte("student_id,cert_type\nSTU001,bonafide\nSTU001,bonafide\n"),
+			want: []ManifestRow{
+				{2, ManifestRecord{"student_id": "STU001", "cert_type": "bonafide"}},
+				{3, ManifestRecord{"student_id": "STU001", "cert_type": "bonafide"}},
+			},
+		},
+		{
+			name:     "xlsx",
+			fileName: "manifest.xlsx",
+			data:     testXLSX(t),
+			want: []ManifestRow{
+				{2, ManifestRecord{"student_id": "STU001", "cert_type": "bonafide", "cgpa": "8.5"}},
+				{4, ManifestRecord{"student_id": "STU002", "cert_type": "", "cgpa": "9"}},
+				{6, ManifestRecord{"student_id": "", "cert_type": "bonafide"}},
+			},
+		},
+		{name: "header only", fileName: "manifest.csv", data: []byte("student_id,cert_type\n"), wantErr: "no data rows"},
+		{name: "unsupported format", fileName: "manifest.ods", data: []byte("x"), wantErr: "unsupported manifest format"},
+		{name: "malformed csv", fileName: "manifest.csv", data: []byte("a,b\n\"unterminated\n"), wantErr: "failed to parse CSV"},
+		{name: "not an xlsx", fileName: "manifest.xlsx", data: []byte("student_id\n"), wantErr: "failed to open XLSX"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseManifest(tc.fileName, tc.data)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v\nwant %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBulkValidateMatchesAttachments(t *testing.T) {
+	st := store.NewMemoryStore()
+	issuer := testAdmin(t, st)
+	for _, id := range []string{"STU001", "STU002", "STU003"} {
+		if _, err := st.CreateUser(models.User{StudentID: id, Name: "Student " + id, Role: models.RoleStudent}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &BulkIssuanceService{store: st, certificates: &CertificateService{store: st}}
+
+	manifest := []byte("student_id,cert_type,file_name,semester\n" +
+		"STU001,bonafide,,1\n" + // Defaults to STU001.pdf
+		"\n" +
+		"STU002,bonafide,stu002.pdf,1\n" + // Stored as docs/STU002.PDF
+		"STU001,bonafide,other.pdf,1\n" + // Same student, type and semester as row 2
+		"STU003,bonafide,missing.pdf,1\n" +
+		"STU003,noc,empty.pdf,1\n" +
+		"STU404,bonafide,STU001.pdf,1\n")
+	archive := zipFiles(t, map[string]string{
+		"STU001.pdf":             "%PDF-1.4 one",
+		"docs/STU002.PDF":        "%PDF-1.4 two",
+		"other.pdf":              "%PDF-1.4 three",
+		"empty.pdf":              "",
+		"__MACOSX/._missing.pdf": "resource fork",
+	})
+
+	rows, err := b.validate("manifest.csv", manifest, archive, issuer.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		number int
+		errs   string
+	}{
+		{2, ""},
+		{4, ""},
+		{5, "duplicate of row 2"},
+		{6, "document missing.pdf not found in archive"},
+		{7, "document empty.pdf is empty"},
+		{8, "student not found"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("validated %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.RowNumber != want[i].number {
+			t.Errorf("row %d numbered %d", want[i].number, row.RowNumber)
+		}
+		errs := strings.Join(row.Errors, "; ")
+		if want[i].errs == "" {
+			if row.Status != models.BulkRowValid || errs != "" {
+				t.Errorf("row %d: %s (%s), want valid", row.RowNumber, row.Status, errs)
+			}
+			continue
+		}
+		if row.Status != models.BulkRowInvalid || !strings.Contains(errs, want[i].errs) {
+			t.Errorf("row %d: %s (%s), want invalid with %q", row.RowNumber, row.Status, errs, want[i].errs)
+		}
+	}
+	if rows[0].FileName != "STU001.pdf" || rows[0].Metadata.StudentName != "Student STU001" || rows[0].Metadata.IssuerName != issuer.Name {
+		t.Errorf("row 2 file %s, student %q, issuer %q", rows[0].FileName, rows[0].Metadata.StudentName, rows[0].Metadata.IssuerName)
+	}
+}
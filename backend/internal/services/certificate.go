@@ -1,32 +1,89 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
+	"blockcred-backend/internal/config"
 	"blockcred-backend/internal/models"
 	"blockcred-backend/internal/store"
 )
 
+// ErrPermissionDenied is returned when the acting user may not perform an operation on a certificate
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrCertificateNotFound is returned when no certificate has the requested cert ID
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// ErrApprovalRequired is returned when a credential type must go through the approval workflow
+var ErrApprovalRequired = errors.New("approval required")
+
+// ErrSupersedePending is returned with a reissued certificate whose supersession could not be recorded
+// on-chain yet. The new version stays pending_supersede until a retry or the background sweep completes it.
+var ErrSupersedePending = errors.New("supersession pending")
+
 type CertificateService struct {
 	store             store.Store
-	ipfsService       *IPFSService
+	content           ContentStore
+	documents         *DocumentCipher // Nil when files are stored unencrypted
+	validator         *DocumentValidator
+	pdfSigner         *PDFSigner      // Nil when SIGN_PDFS is off
+	documentCache     *documentCache  // Nil when DOCUMENT_CACHE_MB is 0
+	pins              *PinTracker
 	blockchainService BlockchainServiceInterface
+	duplicatePolicy   string
 }
 
-func NewCertificateService(s store.Store, ipfs *IPFSService, blockchain BlockchainServiceInterface) *CertificateService {
+func NewCertificateService(cfg config.Config, s store.Store, content ContentStore, documents *DocumentCipher, validator *DocumentValidator, pdfSigner *PDFSigner, pins *PinTracker, blockchain BlockchainServiceInterface) *CertificateService {
 	return &CertificateService{
 		store:             s,
-		ipfsService:       ipfs,
+		content:           content,
+		documents:         documents,
+		validator:         validator,
+		pdfSigner:         pdfSigner,
+		documentCache:     newDocumentCache(cfg.DocumentCacheMB << 20),
+		pins:              pins,
 		blockchainService: blockchain,
+		duplicatePolicy:   normalizeDuplicatePolicy(cfg.DuplicatePolicy),
 	}
 }
 
 // IssueCertificate orchestrates the complete certificate issuance process
-func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest, issuerID string) (*models.Certificate, error) {
+func (c *CertificateService) IssueCertificate(ctx context.Context, req models.IssueCertificateRequest, issuerID string) (*models.Certificate, error) {
+	if c.requiresApproval(req.CertType) {
+		return nil, fmt.Errorf("%w: %s certificates must be submitted as drafts", ErrApprovalRequired, req.CertType)
+	}
+	return c.issueCertificate(ctx, req, issuerID, issueOptions{})
+}
+
+// IssueCertificateUpload issues a certificate for a document spooled by an UploadReceiver
+func (c *CertificateService) IssueCertificateUpload(ctx context.Context, req models.IssueCertificateRequest, upload *Upload, issuerID string) (*models.Certificate, error) {
+	if c.requiresApproval(req.CertType) {
+		return nil, fmt.Errorf("%w: %s certificates must be submitted as drafts", ErrApprovalRequired, req.CertType)
+	}
+	if req.FileName == "" {
+		req.FileName = upload.FileName
+	}
+	return c.issueCertificate(ctx, req, issuerID, issueOptions{upload: upload})
+}
+
+// issueOptions carries pipeline inputs that are fixed before issuance starts
+type issueOptions struct {
+	supersedes string    // CertID of the version being replaced
+	certID     string    // Reserved before issuance, e.g. when the document itself embeds its cert ID
+	issuedAt   time.Time // Issuance time the reserved cert ID was derived from
+	generated  bool      // Document comes from our own renderer and skips upload checks
+	upload     *Upload   // Spooled document, used in place of req.FileData
+}
+
+// issueCertificate runs the issuance pipeline. Uploads and chain calls stop when ctx ends.
+func (c *CertificateService) issueCertificate(ctx context.Context, req models.IssueCertificateRequest, issuerID string, opts issueOptions) (*models.Certificate, error) {
 	// 1. Validate student exists
 	student, err := c.store.GetUserByStudentID(req.StudentID)
 	if err != nil {
@@ -45,10 +102,51 @@ func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest
 		return nil, fmt.Errorf("issuer does not have permission to issue %s certificates (issuer role: %s, cert type: %s)", req.CertType, issuer.Role, req.CertType)
 	}
 
-	// 3. Compute file hash (Credential Hash - SHA-256)
-	fileHash := c.computeFileHash(req.FileData)
+	// Degrees are only issued to students who meet their department's eligibility rule
+	if req.CertType == models.CredentialTypeDegree {
+		if err := c.checkDegreeEligibility(req.StudentID, opts.supersedes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Supplied documents are checked, cleaned and scanned before anything commits to their hash.
+	// Generated documents come from our own renderer and carry a reserved cert ID.
+	if !opts.generated {
+		if opts.upload != nil {
+			err = c.validateUpload(opts.upload, documentOrigin{fileName: req.FileName, uploadedBy: issuerID, studentID: req.StudentID})
+		} else {
+			req.FileData, err = c.validateDocument(req.FileData, documentOrigin{fileName: req.FileName, uploadedBy: issuerID, studentID: req.StudentID})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// 4. Prepare metadata and compute metadata hash
+	// 3. Compute file hash (Credential Hash - SHA-256); spooled uploads were hashed as they arrived
+	var sourceHash string
+	if opts.upload != nil {
+		sourceHash = opts.upload.SHA256
+	} else {
+		sourceHash = c.computeFileHash(req.FileData)
+	}
+
+	// Retried or repeated requests must not mint a second certificate for the same document.
+	// Signatures differ on every issuance, so documents are compared as supplied.
+	duplicateOf, err := c.checkDuplicates(req, sourceHash, opts.supersedes)
+	if err != nil {
+		return nil, err
+	}
+
+	// PDFs carry the institution's signature; the anchored hash covers the signed file holders receive
+	fileHash, signature, err := c.signDocument(&req, opts.upload, sourceHash)
+	if err != nil {
+		return nil, err
+	}
+	if fileHash == sourceHash {
+		sourceHash = ""
+	}
+
+	// 4. Prepare metadata and commit to each field with a salted disclosure
 	metadata := map[string]interface{}{
 		"student_id":   req.StudentID,
 		"student_name": student.Name,
@@ -62,34 +160,41 @@ func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest
 		metadata[k] = v
 	}
 
-	// Compute metadata hash (SHA-256 of metadata JSON)
-	metadataJSON, err := json.Marshal(metadata)
+	// The metadata hash anchored on-chain commits to the disclosure digests, so students
+	// can later reveal individual fields without exposing the rest
+	disclosures, metadataHash, err := newDisclosures(req.StudentID, req.Metadata)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		return nil, err
 	}
-	metadataHash := c.computeFileHash(metadataJSON)
 
-	// 5. Upload file to IPFS
-	ipfsCID, err := c.ipfsService.UploadFile(req.FileData, req.FileName, metadata)
+	// 5. Encrypt the file and upload it to the content store; the anchored hash stays over the plaintext
+	stored, encryption, err := c.storedContent(req, fileHash, opts.upload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload to IPFS: %w", err)
+		return nil, err
+	}
+	ipfsCID, err := putContent(ctx, c.content, stored, req.FileName, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload to %s: %w", c.content.Name(), err)
 	}
 
 	// 6. Get or create student wallet address
-	studentWallet, err := c.blockchainService.GetStudentWallet(req.StudentID)
+	studentWallet, err := c.blockchainService.GetStudentWallet(ctx, req.StudentID)
 	if err != nil || studentWallet == "" {
 		// Generate a deterministic wallet address for the student
 		studentWallet = c.generateStudentWallet(req.StudentID)
 		// Register wallet mapping on blockchain
-		if err := c.blockchainService.RegisterStudentWallet(req.StudentID, studentWallet); err != nil {
+		if err := c.blockchainService.RegisterStudentWallet(ctx, req.StudentID, studentWallet); err != nil {
 			// Log but don't fail - wallet mapping is optional
 			fmt.Printf("⚠️  Warning: Failed to register student wallet: %v\n", err)
 		}
 	}
 
-	// 7. Compute certificate ID
+	// 7. Compute certificate ID (generated documents reserve theirs before rendering)
 	issuedAt := time.Now()
 	certID := c.blockchainService.ComputeCertID(fileHash, req.StudentID, issuedAt)
+	if opts.certID != "" {
+		certID, issuedAt = opts.certID, opts.issuedAt
+	}
 
 	// 8. Get issuer wallet address (for now, use a default or generate from issuer ID)
 	issuerWallet := c.generateIssuerWallet(issuerID)
@@ -107,17 +212,22 @@ func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest
 	}
 
 	// 10. Issue certificate on blockchain with full on-chain data
-	txResult, err := c.blockchainService.IssueCertificateOnChain(onChainData, ipfsCID)
+	txResult, err := c.blockchainService.IssueCertificateOnChain(ctx, onChainData, ipfsCID)
 	if err != nil {
 		// Fallback to simple issue if on-chain fails
 		fmt.Printf("⚠️  Warning: On-chain issuance failed, using fallback: %v\n", err)
-		txResult, err = c.blockchainService.IssueCertificate(certID, ipfsCID, req.CertType)
+		txResult, err = c.blockchainService.IssueCertificate(ctx, certID, ipfsCID, req.CertType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to issue certificate on blockchain: %w", err)
 		}
 	}
 
-	// 11. Create certificate record (OFF-CHAIN in MongoDB)
+	// 11. Create certificate record (OFF-CHAIN in MongoDB). A new version is stored as pending until
+	// the old one is superseded, so the two are never valid at the same time.
+	status := models.CertStatusIssued
+	if opts.supersedes != "" {
+		status = models.CertStatusPendingSupersede
+	}
 	certificate := models.Certificate{
 		CertID:      certID,
 		StudentID:   req.StudentID,
@@ -125,12 +235,18 @@ func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest
 		CertType:    req.CertType,
 		FileHash:    fileHash, // Credential Hash
 		IPFSCID:     ipfsCID,
-		IPFSURL:     c.ipfsService.GetFileURL(ipfsCID),
+		IPFSURL:     c.content.URL(ipfsCID),
 		TxHash:      txResult.TxHash,
 		BlockNumber: txResult.BlockNumber,
-		Status:      models.CertStatusIssued,
+		Status:      status,
 		IssuedAt:    issuedAt,
+		Supersedes:  opts.supersedes,
 		Metadata:    req.Metadata,
+		Disclosures: disclosures,
+		DuplicateOf: duplicateOf,
+		Encryption:  encryption,
+		SourceHash:  sourceHash,
+		Signature:   signature,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -149,9 +265,90 @@ func (c *CertificateService) IssueCertificate(req models.IssueCertificateRequest
 		return nil, fmt.Errorf("failed to save certificate: %w", err)
 	}
 
+	// 13. Copy the file to the replica stores. A spooled upload is removed once the request ends,
+	// so it is copied before returning.
+	if c.pins != nil {
+		if stored.data == nil {
+			c.pins.Replicate(certID, ipfsCID, stored)
+		} else {
+			go c.pins.Replicate(certID, ipfsCID, stored)
+		}
+	}
+
 	return &createdCert, nil
 }
 
+// validateDocument runs the pre-issuance checks when a validator is configured
+func (c *CertificateService) validateDocument(data []byte, origin documentOrigin) ([]byte, error) {
+	if c.validator == nil {
+		return data, nil
+	}
+	return c.validator.Validate(data, origin)
+}
+
+func (c *CertificateService) validateUpload(upload *Upload, origin documentOrigin) error {
+	if c.validator == nil {
+		return nil
+	}
+	return c.validator.ValidateUpload(upload, origin)
+}
+
+// signDocument signs PDFs when a signer is configured and returns the hash of the file to anchor.
+// Other documents, and PDFs when signing is off, keep the hash they were supplied with.
+func (c *CertificateService) signDocument(req *models.IssueCertificateRequest, upload *Upload, sourceHash string) (string, *models.DocumentSignature, error) {
+	if c.pdfSigner == nil {
+		return sourceHash, nil, nil
+	}
+	data := req.FileData
+	if upload != nil {
+		if upload.ContentType != "application/pdf" {
+			return sourceHash, nil, nil
+		}
+		var err error
+		if data, err = upload.ReadAll(); err != nil {
+			return "", nil, fmt.Errorf("failed to read uploaded file: %w", err)
+		}
+	} else if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return sourceHash, nil, nil
+	}
+
+	signed, signature, err := c.pdfSigner.Sign(data, fmt.Sprintf("Issued %s certificate to %s", req.CertType, req.StudentID))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign certificate PDF: %w", err)
+	}
+	if upload != nil {
+		if err := upload.replace(signed); err != nil {
+			return "", nil, err
+		}
+		return upload.SHA256, signature, nil
+	}
+	req.FileData = signed
+	return c.computeFileHash(signed), signature, nil
+}
+
+// storedContent is the content written to the content store. A spooled upload is streamed as is;
+// encryption seals the whole file as one message, so an encrypted upload is read into memory.
+func (c *CertificateService) storedContent(req models.IssueCertificateRequest, fileHash string, upload *Upload) (ContentSource, *models.DocumentEncryption, error) {
+	if upload != nil && c.documents == nil {
+		return upload.source(), nil, nil
+	}
+	data := req.FileData
+	if upload != nil {
+		var err error
+		if data, err = upload.ReadAll(); err != nil {
+			return ContentSource{}, nil, fmt.Errorf("failed to read uploaded file: %w", err)
+		}
+	}
+	if c.documents == nil {
+		return bytesSource(data), nil, nil
+	}
+	sealed, encryption, err := c.documents.Seal(data, fileHash)
+	if err != nil {
+		return ContentSource{}, nil, fmt.Errorf("failed to encrypt certificate file: %w", err)
+	}
+	return bytesSource(sealed), encryption, nil
+}
+
 // VerifyCertificate verifies a certificate by checking blockchain and database
 func (c *CertificateService) VerifyCertificate(certID string) (*models.CertificateVerificationResult, error) {
 	// 1. Get certificate from database
@@ -183,6 +380,38 @@ func (c *CertificateService) VerifyCertificate(certID string) (*models.Certifica
 		}, nil
 	}
 
+	// A reissued version only becomes valid once the version it replaces is superseded
+	if cert.Status == models.CertStatusPendingSupersede {
+		return &models.CertificateVerificationResult{
+			IsValid:      false,
+			CertID:       cert.CertID,
+			StudentID:    cert.StudentID,
+			IssuerID:     cert.IssuerID,
+			CertType:     cert.CertType,
+			Status:       cert.Status,
+			IssuedAt:     cert.IssuedAt,
+			Supersedes:   cert.Supersedes,
+			ErrorMessage: "Certificate is pending supersession of its previous version",
+		}, nil
+	}
+
+	// Point verifiers at the current version of a superseded certificate
+	if cert.Status == models.CertStatusSuperseded {
+		return &models.CertificateVerificationResult{
+			IsValid:       false,
+			CertID:        cert.CertID,
+			StudentID:     cert.StudentID,
+			IssuerID:      cert.IssuerID,
+			CertType:      cert.CertType,
+			Status:        cert.Status,
+			IssuedAt:      cert.IssuedAt,
+			Supersedes:    cert.Supersedes,
+			SupersededBy:  cert.SupersededBy,
+			CurrentCertID: c.currentVersion(cert),
+			ErrorMessage:  "Certificate has been superseded by a newer version",
+		}, nil
+	}
+
 	// 4. Return verification result
 	result := &models.CertificateVerificationResult{
 		IsValid:     isValidOnChain && cert.Status != models.CertStatusRevoked,
@@ -195,7 +424,23 @@ func (c *CertificateService) VerifyCertificate(certID string) (*models.Certifica
 		IPFSURL:     cert.IPFSURL,
 		TxHash:      cert.TxHash,
 		BlockNumber: cert.BlockNumber,
-		Metadata:    cert.Metadata,
+		Supersedes:  cert.Supersedes,
+	}
+
+	if cert.Status == models.CertStatusSuspended {
+		// Suspended certificates are flagged as temporarily invalid
+		result.IsValid = false
+		result.Suspended = true
+		if cert.Suspension != nil {
+			result.ReinstateAt = cert.Suspension.ReinstateAt
+		}
+		result.ErrorMessage = "Certificate is temporarily suspended"
+		// Verification never writes; ReinstateDueSuspensions lifts lapsed suspensions in the background
+		if cert.Suspension.IsDue(time.Now()) {
+			result.SuspensionLapsed = true
+			result.ErrorMessage = "Certificate suspension has lapsed and is awaiting reinstatement"
+		}
+		return result, nil
 	}
 
 	if !result.IsValid {
@@ -241,8 +486,296 @@ func (c *CertificateService) RevokeCertificate(certID, reason string) error {
 	return nil
 }
 
+// ReissueCertificate issues an amended version of a certificate and marks the old one superseded
+func (c *CertificateService) ReissueCertificate(ctx context.Context, certID string, req models.ReissueCertificateRequest, issuerID string) (*models.Certificate, error) {
+	old, err := c.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
+	}
+	if c.requiresApproval(old.CertType) {
+		return nil, fmt.Errorf("%w: reissued %s certificates must be submitted as drafts", ErrApprovalRequired, old.CertType)
+	}
+	return c.reissueCertificate(ctx, old, req, issuerID)
+}
+
+// reissueCertificate issues the new version and links it to the old one. The new version is recorded
+// as pending_supersede before the old one is superseded on-chain; if that step fails the pending
+// version is returned with ErrSupersedePending, and a retry completes it instead of issuing again.
+func (c *CertificateService) reissueCertificate(ctx context.Context, old models.Certificate, req models.ReissueCertificateRequest, issuerID string) (*models.Certificate, error) {
+
+	switch old.Status {
+	case models.CertStatusRevoked:
+		return nil, fmt.Errorf("revoked certificates cannot be reissued")
+	case models.CertStatusSuperseded:
+		return nil, fmt.Errorf("certificate has already been superseded by %s", old.SupersededBy)
+	}
+
+	if pending, ok := c.pendingVersion(old); ok {
+		return c.completeSupersede(ctx, old, pending)
+	}
+
+	if req.Reason == "" {
+		return nil, fmt.Errorf("reissue reason is required")
+	}
+
+	// The new version goes through the full issuance pipeline, including permission checks
+	issued, err := c.issueCertificate(ctx, models.IssueCertificateRequest{
+		StudentID: old.StudentID,
+		CertType:  old.CertType,
+		FileData:  req.FileData,
+		FileName:  req.FileName,
+		Metadata:  req.Metadata,
+	}, issuerID, issueOptions{supersedes: old.CertID})
+	if err != nil {
+		return nil, err
+	}
+
+	issued.ReissueReason = req.Reason
+	updatedNew, err := c.store.UpdateCertificate(issued.CertID, *issued)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record reissue reason: %w", err)
+	}
+
+	return c.completeSupersede(ctx, old, updatedNew)
+}
+
+// pendingVersion finds a new version of the certificate whose supersession was not completed
+func (c *CertificateService) pendingVersion(old models.Certificate) (models.Certificate, bool) {
+	certs, err := c.store.ListCertificatesByStudent(old.StudentID)
+	if err != nil {
+		return models.Certificate{}, false
+	}
+	for _, cert := range certs {
+		if cert.Supersedes == old.CertID && cert.Status == models.CertStatusPendingSupersede {
+			return cert, true
+		}
+	}
+	return models.Certificate{}, false
+}
+
+// completeSupersede supersedes the old certificate on-chain and in the store, then activates the new one
+func (c *CertificateService) completeSupersede(ctx context.Context, old, pending models.Certificate) (*models.Certificate, error) {
+	if err := c.blockchainService.SupersedeCertificateOnChain(ctx, old.CertID, pending.CertID); err != nil {
+		return &pending, fmt.Errorf("%w: certificate %s issued but %s is not superseded yet: %v", ErrSupersedePending, pending.CertID, old.CertID, err)
+	}
+
+	now := time.Now()
+	old.Status = models.CertStatusSuperseded
+	old.SupersededBy = pending.CertID
+	old.Suspension = nil
+	old.UpdatedAt = now
+	if _, err := c.store.UpdateCertificate(old.CertID, old); err != nil {
+		return &pending, fmt.Errorf("%w: failed to mark certificate superseded: %v", ErrSupersedePending, err)
+	}
+
+	pending.Status = models.CertStatusIssued
+	pending.UpdatedAt = now
+	updated, err := c.store.UpdateCertificate(pending.CertID, pending)
+	if err != nil {
+		return &pending, fmt.Errorf("%w: failed to activate certificate: %v", ErrSupersedePending, err)
+	}
+
+	return &updated, nil
+}
+
+// CompletePendingSupersessions retries the supersession of every reissue left pending_supersede
+func (c *CertificateService) CompletePendingSupersessions() {
+	certs, err := c.store.ListCertificates()
+	if err != nil {
+		log.Printf("⚠️  Supersession sweep failed: %v", err)
+		return
+	}
+
+	for _, cert := range certs {
+		if cert.Status != models.CertStatusPendingSupersede {
+			continue
+		}
+		old, err := c.store.GetCertificateByCertID(cert.Supersedes)
+		if err != nil {
+			log.Printf("⚠️  Certificate %s supersedes unknown certificate %s", cert.CertID, cert.Supersedes)
+			continue
+		}
+		if _, err := c.completeSupersede(context.Background(), old, cert); err != nil {
+			log.Printf("⚠️  Failed to complete reissue %s: %v", cert.CertID, err)
+		}
+	}
+}
+
+// currentVersion follows the superseded_by chain to the latest certificate version
+func (c *CertificateService) currentVersion(cert models.Certificate) string {
+	current := cert.CertID
+	next := cert.SupersededBy
+	// Bound the walk so a corrupted chain cannot loop forever
+	for i := 0; next != "" && i < 100; i++ {
+		current = next
+		nextCert, err := c.store.GetCertificateByCertID(next)
+		if err != nil {
+			break
+		}
+		next = nextCert.SupersededBy
+	}
+	return current
+}
+
+// SuspendCertificate places a certificate on a temporary, reversible hold
+func (c *CertificateService) SuspendCertificate(ctx context.Context, certID, actorID string, req models.SuspendCertificateRequest) (*models.Certificate, error) {
+	cert, err := c.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
+	}
+
+	actor, err := c.store.GetUserByID(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !c.canManageSuspension(actor, cert) {
+		return nil, fmt.Errorf("%w: user cannot suspend this certificate", ErrPermissionDenied)
+	}
+
+	switch cert.Status {
+	case models.CertStatusRevoked:
+		return nil, fmt.Errorf("revoked certificates cannot be suspended")
+	case models.CertStatusSuspended:
+		return nil, fmt.Errorf("certificate is already suspended")
+	}
+
+	if req.Reason == "" {
+		return nil, fmt.Errorf("suspension reason is required")
+	}
+
+	now := time.Now()
+	var reinstateUnix int64
+	if req.ReinstateAt != nil {
+		if !req.ReinstateAt.After(now) {
+			return nil, fmt.Errorf("reinstate_at must be in the future")
+		}
+		reinstateUnix = req.ReinstateAt.Unix()
+	}
+
+	if err := c.blockchainService.SuspendCertificateOnChain(ctx, certID, req.Reason, reinstateUnix); err != nil {
+		return nil, err
+	}
+
+	cert.Suspension = &models.CertificateSuspension{
+		Reason:      req.Reason,
+		SuspendedBy: actorID,
+		SuspendedAt: now,
+		ReinstateAt: req.ReinstateAt,
+		PrevStatus:  cert.Status,
+	}
+	cert.Status = models.CertStatusSuspended
+	cert.UpdatedAt = now
+
+	updated, err := c.store.UpdateCertificate(certID, cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suspend certificate: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// ReinstateCertificate lifts a suspension and restores the certificate's previous status
+func (c *CertificateService) ReinstateCertificate(ctx context.Context, certID, actorID string) (*models.Certificate, error) {
+	cert, err := c.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
+	}
+
+	actor, err := c.store.GetUserByID(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !c.canManageSuspension(actor, cert) {
+		return nil, fmt.Errorf("%w: user cannot reinstate this certificate", ErrPermissionDenied)
+	}
+
+	if cert.Status != models.CertStatusSuspended {
+		return nil, fmt.Errorf("certificate is not suspended")
+	}
+
+	updated, err := c.reinstate(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ReinstateDueSuspensions lifts every suspension whose reinstatement date has passed
+func (c *CertificateService) ReinstateDueSuspensions() {
+	certs, err := c.store.ListCertificates()
+	if err != nil {
+		log.Printf("⚠️  Suspension sweep failed: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, cert := range certs {
+		if cert.Status != models.CertStatusSuspended || !cert.Suspension.IsDue(now) {
+			continue
+		}
+		if _, err := c.reinstate(context.Background(), cert); err != nil {
+			log.Printf("⚠️  Failed to auto-reinstate certificate %s: %v", cert.CertID, err)
+		}
+	}
+}
+
+// reinstate clears the suspension on-chain and in the store
+func (c *CertificateService) reinstate(ctx context.Context, cert models.Certificate) (models.Certificate, error) {
+	if err := c.blockchainService.ReinstateCertificateOnChain(ctx, cert.CertID); err != nil {
+		return models.Certificate{}, err
+	}
+
+	status := models.CertStatusIssued
+	if cert.Suspension != nil && cert.Suspension.PrevStatus != "" {
+		status = cert.Suspension.PrevStatus
+	}
+	cert.Status = status
+	cert.Suspension = nil
+	cert.UpdatedAt = time.Now()
+
+	updated, err := c.store.UpdateCertificate(cert.CertID, cert)
+	if err != nil {
+		return models.Certificate{}, fmt.Errorf("failed to reinstate certificate: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DiscloseSuspension attaches the suspension reason and actor to a verification result when the
+// viewer is the certificate's holder, its issuer or staff; public results carry only the flag and date
+func (c *CertificateService) DiscloseSuspension(result *models.CertificateVerificationResult, viewer models.User) {
+	if !result.Suspended {
+		return
+	}
+	cert, err := c.store.GetCertificateByCertID(result.CertID)
+	if err != nil || cert.Suspension == nil || !c.canAccessCertificate(viewer, cert) {
+		return
+	}
+	result.Suspension = cert.Suspension
+}
+
 // Helper functions
 
+// canManageSuspension allows the original issuer or any role with suspension rights
+func (c *CertificateService) canManageSuspension(actor models.User, cert models.Certificate) bool {
+	if actor.ID.Hex() == cert.IssuerID {
+		return true
+	}
+	return actor.CanPerformAction("can_suspend_credentials")
+}
+
+// canAccessCertificate allows the certificate's student, its issuer and staff who can view all credentials
+func (c *CertificateService) canAccessCertificate(user models.User, cert models.Certificate) bool {
+	if user.StudentID != "" && user.StudentID == cert.StudentID {
+		return true
+	}
+	if user.ID.Hex() == cert.IssuerID {
+		return true
+	}
+	return user.CanPerformAction("can_view_all_credentials")
+}
+
 func (c *CertificateService) computeFileHash(fileData []byte) string {
 	hash := sha256.Sum256(fileData)
 	return hex.EncodeToString(hash[:])
@@ -264,6 +797,12 @@ func (c *CertificateService) generateIssuerWallet(issuerID string) string {
 	return fmt.Sprintf("0x%x", hash[:20])
 }
 
+// requiresApproval reports whether an approval policy applies to the credential type
+func (c *CertificateService) requiresApproval(certType models.CredentialType) bool {
+	policy, err := c.store.GetApprovalPolicy(certType)
+	return err == nil && len(policy.RequiredRoles) > 0
+}
+
 func (c *CertificateService) canIssueCertificate(role models.UserRole, certType models.CredentialType) bool {
 	// Explicit check for NFT certificates - allow for admin and COE roles
 	certTypeStr := string(certType)
@@ -293,6 +832,8 @@ func (c *CertificateService) canIssueCertificate(role models.UserRole, certType
 		return permissions.CanIssueParticipation
 	case models.CredentialTypeDegree:
 		return permissions.CanIssueMarksheet // COE can issue degrees
+	case models.CredentialTypeTranscript:
+		return permissions.CanIssueMarksheet // Transcripts consolidate COE marksheets
 	default:
 		return false
 	}
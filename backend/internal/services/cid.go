@@ -0,0 +1,165 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Local CID computation. The settings mirror what Kubo, and Pinata with cidVersion 1, use when adding a
+// file: fixed 256 KiB chunks stored as raw leaves, a balanced UnixFS DAG of at most 174 links per node,
+// sha2-256 multihashes and base32 CIDv1 strings.
+const (
+	cidChunkSize = 262144
+	cidMaxLinks  = 174
+
+	codecRaw    = 0x55
+	codecDagPB  = 0x70
+	mhSHA256    = 0x12
+	unixfsFile  = 2
+	cidVersion1 = 1
+)
+
+var cidBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// cidNode is a DAG node that has already been encoded
+type cidNode struct {
+	cid      []byte // Binary CID
+	fileSize uint64 // File bytes below this node
+	tsize    uint64 // Encoded bytes of this node and everything below it
+}
+
+// ComputeCID returns the CIDv1 an IPFS node assigns to data when it is added as a single file
+func ComputeCID(data []byte) string {
+	var b cidBuilder
+	b.Write(data)
+	return b.Sum()
+}
+
+// cidBuilder computes a CID as content streams through it, holding one chunk at a time
+type cidBuilder struct {
+	chunk  []byte
+	leaves []cidNode
+}
+
+func (b *cidBuilder) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		if b.chunk == nil {
+			b.chunk = make([]byte, 0, cidChunkSize)
+		}
+		n := min(cidChunkSize-len(b.chunk), len(p))
+		b.chunk, p = append(b.chunk, p[:n]...), p[n:]
+		if len(b.chunk) == cidChunkSize {
+			b.addLeaf()
+		}
+	}
+	return written, nil
+}
+
+func (b *cidBuilder) addLeaf() {
+	b.leaves = append(b.leaves, cidNode{cid: encodeCID(codecRaw, b.chunk), fileSize: uint64(len(b.chunk)), tsize: uint64(len(b.chunk))})
+	b.chunk = b.chunk[:0]
+}
+
+// Sum returns the CID of everything written so far
+func (b *cidBuilder) Sum() string {
+	// Only an empty file ends in an empty chunk
+	if len(b.chunk) > 0 || len(b.leaves) == 0 {
+		b.addLeaf()
+	}
+	// A file that fits in one chunk is the raw leaf itself
+	level := b.leaves
+	for len(level) > 1 {
+		var parents []cidNode
+		for start := 0; start < len(level); start += cidMaxLinks {
+			end := min(start+cidMaxLinks, len(level))
+			parents = append(parents, encodeFileNode(level[start:end]))
+		}
+		level = parents
+	}
+	return "b" + strings.ToLower(cidBase32.EncodeToString(level[0].cid))
+}
+
+// encodeFileNode builds a dag-pb node holding UnixFS file metadata and links to its children
+func encodeFileNode(children []cidNode) cidNode {
+	var fileSize, tsize uint64
+	unixfs := protoVarint(nil, 1, unixfsFile)
+	for _, child := range children {
+		fileSize += child.fileSize
+	}
+	unixfs = protoVarint(unixfs, 3, fileSize)
+	for _, child := range children {
+		unixfs = protoVarint(unixfs, 4, child.fileSize)
+	}
+
+	// dag-pb puts the links ahead of the data, and every link carries an (empty) name
+	var node []byte
+	for _, child := range children {
+		var link []byte
+		link = protoBytes(link, 1, child.cid)
+		link = protoBytes(link, 2, nil)
+		link = protoVarint(link, 3, child.tsize)
+		node = protoBytes(node, 2, link)
+		tsize += child.tsize
+	}
+	node = protoBytes(node, 1, unixfs)
+
+	return cidNode{cid: encodeCID(codecDagPB, node), fileSize: fileSize, tsize: tsize + uint64(len(node))}
+}
+
+func encodeCID(codec uint64, block []byte) []byte {
+	digest := sha256.Sum256(block)
+	cid := binary.AppendUvarint(nil, cidVersion1)
+	cid = binary.AppendUvarint(cid, codec)
+	cid = binary.AppendUvarint(cid, mhSHA256)
+	cid = binary.AppendUvarint(cid, sha256.Size)
+	return append(cid, digest[:]...)
+}
+
+func protoVarint(buf []byte, field int, value uint64) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field)<<3)
+	return binary.AppendUvarint(buf, value)
+}
+
+func protoBytes(buf []byte, field int, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|2)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// isCIDv1 reports whether s is a base32 sha2-256 CIDv1 of the kind ComputeCID produces
+func isCIDv1(s string) bool {
+	if len(s) < 2 || s[0] != 'b' || s != strings.ToLower(s) {
+		return false
+	}
+	raw, err := cidBase32.DecodeString(strings.ToUpper(s[1:]))
+	if err != nil {
+		return false
+	}
+	fields := make([]uint64, 4)
+	rest := raw
+	for i := range fields {
+		value, n := binary.Uvarint(rest)
+		if n <= 0 {
+			return false
+		}
+		fields[i], rest = value, rest[n:]
+	}
+	return fields[0] == cidVersion1 && (fields[1] == codecRaw || fields[1] == codecDagPB) &&
+		fields[2] == mhSHA256 && fields[3] == sha256.Size && len(rest) == sha256.Size
+}
+
+// verifyCID rejects content whose CID, as reported by a pinning service, differs from the one computed here
+func verifyCID(provider, reported string, data []byte) error {
+	return checkCID(provider, reported, ComputeCID(data))
+}
+
+func checkCID(provider, reported, expected string) error {
+	if reported != expected {
+		return fmt.Errorf("%s returned CID %s for content whose CID is %s", provider, reported, expected)
+	}
+	return nil
+}
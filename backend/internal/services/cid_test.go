@@ -0,0 +1,129 @@
+package services
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CIDs printed by `ipfs add --cid-version=1 --raw-leaves` for the same content
+var kuboCIDs = []struct {
+	name string
+	data []byte
+	cid  string
+}{
+	{"empty file", nil, "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+	{"single raw leaf", []byte("hello world"), "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e"},
+}
+
+func TestComputeCIDMatchesKubo(t *testing.T) {
+	for _, tc := range kuboCIDs {
+		if got := ComputeCID(tc.data); got != tc.cid {
+			t.Errorf("%s: CID %s, want %s", tc.name, got, tc.cid)
+		}
+		if !isCIDv1(tc.cid) {
+			t.Errorf("%s: %s not recognised as a CIDv1", tc.name, tc.cid)
+		}
+	}
+}
+
+// cidTestData returns n bytes of reproducible content
+func cidTestData(n int) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(int64(n))).Read(data)
+	return data
+}
+
+func TestComputeCIDBalancedLayout(t *testing.T) {
+	leaf := func(data []byte) cidNode {
+		return cidNode{cid: encodeCID(codecRaw, data), fileSize: uint64(len(data)), tsize: uint64(len(data))}
+	}
+	leaves := func(data []byte) []cidNode {
+		var nodes []cidNode
+		for start := 0; start < len(data); start += cidChunkSize {
+			nodes = append(nodes, leaf(data[start:min(start+cidChunkSize, len(data))]))
+		}
+		return nodes
+	}
+	cidString := func(node cidNode) string {
+		return "b" + strings.ToLower(cidBase32.EncodeToString(node.cid))
+	}
+
+	// A full chunk is still a single raw leaf
+	chunk := cidTestData(cidChunkSize)
+	if got, want := ComputeCID(chunk), cidString(leaf(chunk)); got != want {
+		t.Errorf("one chunk: CID %s, want raw leaf %s", got, want)
+	}
+
+	// One byte more needs a file node over two leaves
+	twoChunks := cidTestData(cidChunkSize + 1)
+	if got, want := ComputeCID(twoChunks), cidString(encodeFileNode(leaves(twoChunks))); got != want {
+		t.Errorf("two chunks: CID %s, want %s", got, want)
+	}
+
+	// 174 chunks fill one file node; the next chunk starts a second level, whose first child keeps
+	// the full node and whose second holds the remaining leaf under a node of its own
+	full := cidTestData(cidChunkSize * cidMaxLinks)
+	if got, want := ComputeCID(full), cidString(encodeFileNode(leaves(full))); got != want {
+		t.Errorf("%d chunks: CID %s, want %s", cidMaxLinks, got, want)
+	}
+	deep := cidTestData(cidChunkSize*cidMaxLinks + 1)
+	deepLeaves := leaves(deep)
+	root := encodeFileNode([]cidNode{
+		encodeFileNode(deepLeaves[:cidMaxLinks]),
+		encodeFileNode(deepLeaves[cidMaxLinks:]),
+	})
+	if got, want := ComputeCID(deep), cidString(root); got != want {
+		t.Errorf("%d chunks plus a byte: CID %s, want %s", cidMaxLinks, got, want)
+	}
+	if root.fileSize != uint64(len(deep)) {
+		t.Errorf("root covers %d bytes, want %d", root.fileSize, len(deep))
+	}
+
+	// Streaming in uneven writes gives the same CID as hashing in one call
+	var b cidBuilder
+	for rest := deep; len(rest) > 0; {
+		n := min(len(rest), 100003)
+		b.Write(rest[:n])
+		rest = rest[n:]
+	}
+	if got, want := b.Sum(), cidString(root); got != want {
+		t.Errorf("streamed CID %s, want %s", got, want)
+	}
+}
+
+// TestComputeCIDAgainstKubo compares multi-chunk files with a local Kubo install, when there is one
+func TestComputeCIDAgainstKubo(t *testing.T) {
+	ipfs, err := exec.LookPath("ipfs")
+	if err != nil {
+		t.Skip("ipfs not installed")
+	}
+	if testing.Short() {
+		t.Skip("hashes a 45 MB file")
+	}
+	repo := t.TempDir()
+	t.Setenv("IPFS_PATH", repo)
+	if out, err := exec.Command(ipfs, "init", "--profile=test", "--empty-repo").CombinedOutput(); err != nil {
+		t.Fatalf("ipfs init: %v\n%s", err, out)
+	}
+
+	sizes := []int{0, 11, cidChunkSize, cidChunkSize + 1, cidChunkSize * cidMaxLinks, cidChunkSize*cidMaxLinks + 1}
+	for _, size := range sizes {
+		data := cidTestData(size)
+		path := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command(ipfs, "add", "--only-hash", "--cid-version=1", "--raw-leaves", "-Q", path).Output()
+		if err != nil {
+			t.Fatalf("ipfs add: %v", err)
+		}
+		if want := string(bytes.TrimSpace(out)); ComputeCID(data) != want {
+			t.Errorf("%d bytes: CID %s, Kubo %s", size, ComputeCID(data), want)
+		}
+	}
+}
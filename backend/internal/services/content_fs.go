@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"blockcred-backend/internal/config"
+)
+
+// FilesystemStore keeps content in a local directory, addressed by CID. Meant for development.
+type FilesystemStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewFilesystemStore(cfg config.Config) (*FilesystemStore, error) {
+	if err := os.MkdirAll(cfg.ContentDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create content directory: %w", err)
+	}
+	return &FilesystemStore{
+		dir:     cfg.ContentDir,
+		baseURL: cfg.PublicAPIURL + "/content/",
+	}, nil
+}
+
+func (f *FilesystemStore) Name() string {
+	return "filesystem"
+}
+
+// Put writes the file atomically; storing the same content twice is a no-op
+func (f *FilesystemStore) Put(ctx context.Context, data []byte, name string, metadata map[string]interface{}) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	return f.write(contentKey(data), bytes.NewReader(data))
+}
+
+// PutStream copies the source into the store without loading it
+func (f *FilesystemStore) PutStream(ctx context.Context, src ContentSource, name string, metadata map[string]interface{}) (string, error) {
+	if src.Size == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	r, err := src.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open content: %w", err)
+	}
+	defer r.Close()
+	return f.write(src.CID, r)
+}
+
+func (f *FilesystemStore) write(key string, r io.Reader) (string, error) {
+	path := filepath.Join(f.dir, key)
+	if _, err := os.Stat(path); err == nil {
+		return key, nil
+	}
+
+	tmp, err := os.CreateTemp(f.dir, key+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create content file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write content file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write content file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store content file: %w", err)
+	}
+	return key, nil
+}
+
+func (f *FilesystemStore) Get(ctx context.Context, cid string) ([]byte, error) {
+	if !validContentKey(cid) {
+		return nil, fmt.Errorf("invalid content key %q", cid)
+	}
+	data, err := os.ReadFile(filepath.Join(f.dir, cid))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("content %s not found", cid)
+	}
+	return data, err
+}
+
+// Pin confirms the file exists; the directory keeps everything it holds
+func (f *FilesystemStore) Pin(cid string) error {
+	_, err := f.Stat(cid)
+	return err
+}
+
+// Unpin deletes the file, since a directory has no separate notion of pinning
+func (f *FilesystemStore) Unpin(cid string) error {
+	if !validContentKey(cid) {
+		return fmt.Errorf("invalid content key %q", cid)
+	}
+	if err := os.Remove(filepath.Join(f.dir, cid)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove content file: %w", err)
+	}
+	return nil
+}
+
+func (f *FilesystemStore) Stat(cid string) (*ContentStat, error) {
+	if !validContentKey(cid) {
+		return nil, fmt.Errorf("invalid content key %q", cid)
+	}
+	info, err := os.Stat(filepath.Join(f.dir, cid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("content %s not found", cid)
+		}
+		return nil, fmt.Errorf("failed to stat content file: %w", err)
+	}
+	return &ContentStat{CID: cid, Size: info.Size(), Pinned: true}, nil
+}
+
+// URL points at the API route that serves filesystem content
+func (f *FilesystemStore) URL(cid string) string {
+	return f.baseURL + cid
+}
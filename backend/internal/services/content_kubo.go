@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"blockcred-backend/internal/config"
+)
+
+// KuboStore stores content on a self-hosted IPFS node through the Kubo RPC API
+type KuboStore struct {
+	apiURL     string
+	gatewayURL string
+	client     *http.Client
+	dep        *Dependency
+}
+
+func NewKuboStore(cfg config.Config) *KuboStore {
+	return &KuboStore{
+		apiURL:     cfg.KuboAPIURL,
+		gatewayURL: cfg.KuboGatewayURL,
+		// Attempt deadlines come from the dependency guard; this only bounds a stuck connection
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+		dep: dependencyFor(cfg, "kubo"),
+	}
+}
+
+func (k *KuboStore) Name() string {
+	return "kubo"
+}
+
+// Put adds and pins the file as CIDv1 with raw leaves, matching what Pinata returns
+func (k *KuboStore) Put(ctx context.Context, data []byte, name string, metadata map[string]interface{}) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	return k.PutStream(ctx, bytesSource(data), name, metadata)
+}
+
+func (k *KuboStore) PutStream(ctx context.Context, src ContentSource, name string, metadata map[string]interface{}) (string, error) {
+	if src.Size == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	args := url.Values{"cid-version": {"1"}, "raw-leaves": {"true"}, "pin": {"true"}}
+	body, err := k.callStream(ctx, "add", args, func() (io.ReadCloser, string, error) {
+		return multipartStream(src, name, nil)
+	})
+	if err != nil {
+		return "", err
+	}
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.Unmarshal(body, &added); err != nil {
+		return "", fmt.Errorf("failed to parse Kubo add response: %w", err)
+	}
+	if err := checkCID("Kubo", added.Hash, src.CID); err != nil {
+		k.Unpin(added.Hash)
+		return "", err
+	}
+	return added.Hash, nil
+}
+
+func (k *KuboStore) Get(ctx context.Context, cid string) ([]byte, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+	return k.call(ctx, "cat", url.Values{"arg": {cid}})
+}
+
+func (k *KuboStore) Pin(cid string) error {
+	_, err := k.call(context.Background(), "pin/add", url.Values{"arg": {cid}})
+	return err
+}
+
+func (k *KuboStore) Unpin(cid string) error {
+	_, err := k.call(context.Background(), "pin/rm", url.Values{"arg": {cid}})
+	return err
+}
+
+func (k *KuboStore) Stat(cid string) (*ContentStat, error) {
+	body, err := k.call(context.Background(), "files/stat", url.Values{"arg": {"/ipfs/" + cid}})
+	if err != nil {
+		return nil, err
+	}
+	var stat struct {
+		CumulativeSize int64 `json:"CumulativeSize"`
+	}
+	if err := json.Unmarshal(body, &stat); err != nil {
+		return nil, fmt.Errorf("failed to parse Kubo stat response: %w", err)
+	}
+
+	// pin/ls fails for content that is not pinned
+	_, pinErr := k.call(context.Background(), "pin/ls", url.Values{"arg": {cid}, "type": {"recursive"}})
+	return &ContentStat{CID: cid, Size: stat.CumulativeSize, Pinned: pinErr == nil}, nil
+}
+
+func (k *KuboStore) URL(cid string) string {
+	return k.gatewayURL + cid
+}
+
+// call invokes a Kubo RPC command without a body
+func (k *KuboStore) call(ctx context.Context, command string, args url.Values) ([]byte, error) {
+	return k.callStream(ctx, command, args, nil)
+}
+
+// callStream invokes a Kubo RPC command; the API only accepts POST. Every command used here is safe
+// to repeat, and openBody supplies a fresh request body for each attempt.
+func (k *KuboStore) callStream(ctx context.Context, command string, args url.Values, openBody func() (io.ReadCloser, string, error)) ([]byte, error) {
+	var respBody []byte
+	err := k.dep.Do(ctx, true, func(ctx context.Context) error {
+		var body io.ReadCloser
+		var contentType string
+		if openBody != nil {
+			var err error
+			if body, contentType, err = openBody(); err != nil {
+				return err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", k.apiURL+"/api/v0/"+command+"?"+args.Encode(), body)
+		if err != nil {
+			if body != nil {
+				body.Close()
+			}
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := k.client.Do(req)
+		if err != nil {
+			return upstreamTransportError("kubo", fmt.Errorf("failed to reach Kubo API: %w", err))
+		}
+		defer resp.Body.Close()
+
+		if respBody, err = io.ReadAll(resp.Body); err != nil {
+			return upstreamTransportError("kubo", fmt.Errorf("failed to read Kubo response: %w", err))
+		}
+		if resp.StatusCode != http.StatusOK {
+			var kuboErr struct {
+				Message string `json:"Message"`
+			}
+			// Kubo answers failed commands (e.g. "not pinned") with a 500 and a message; that is the node working
+			if json.Unmarshal(respBody, &kuboErr) == nil && kuboErr.Message != "" {
+				return fmt.Errorf("Kubo %s failed: %s", command, kuboErr.Message)
+			}
+			return upstreamStatusError("kubo", resp.StatusCode, fmt.Errorf("Kubo %s failed (status %d): %s", command, resp.StatusCode, string(respBody)))
+		}
+		return nil
+	})
+	return respBody, err
+}
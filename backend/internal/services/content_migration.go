@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// ContentMigrationService re-pins certificate files held by another provider into the configured
+// content store. The CID anchored on chain must survive the move, so every file is checked against
+// it both when it is read from the old provider and when the new one stores it.
+type ContentMigrationService struct {
+	cfg    config.Config
+	store  store.Store
+	target ContentStore
+}
+
+func NewContentMigrationService(cfg config.Config, s store.Store, target ContentStore) *ContentMigrationService {
+	return &ContentMigrationService{cfg: cfg, store: s, target: target}
+}
+
+// Migrate copies every certificate file missing from the configured store out of the named provider
+func (m *ContentMigrationService) Migrate(req models.ContentMigrationRequest, user models.User) (*models.ContentMigrationReport, error) {
+	if !user.CanPerformAction("can_manage_users") {
+		return nil, fmt.Errorf("%w: only administrators can migrate content", ErrPermissionDenied)
+	}
+	if strings.TrimSpace(req.From) == "" {
+		return nil, fmt.Errorf("source content store is required")
+	}
+
+	sourceCfg := m.cfg
+	sourceCfg.ContentStore = req.From
+	source, err := NewContentStore(sourceCfg)
+	if err != nil {
+		return nil, err
+	}
+	if source.Name() == m.target.Name() {
+		return nil, fmt.Errorf("source and target are both the %s store", source.Name())
+	}
+
+	certs, err := m.store.ListCertificates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+
+	report := &models.ContentMigrationReport{From: source.Name(), To: m.target.Name(), Failed: []models.ContentMigrationFailure{}}
+	for _, cert := range certs {
+		if cert.IPFSCID == "" {
+			continue
+		}
+		report.Checked++
+
+		copied, err := m.migrate(source, cert)
+		if err != nil {
+			report.Failed = append(report.Failed, models.ContentMigrationFailure{CertID: cert.CertID, CID: cert.IPFSCID, Error: err.Error()})
+			continue
+		}
+		if copied {
+			report.Copied++
+		} else {
+			report.AlreadyPresent++
+		}
+
+		// Point the certificate at the new provider's gateway; the CID itself is unchanged
+		if url := m.target.URL(cert.IPFSCID); url != cert.IPFSURL {
+			cert.IPFSURL = url
+			if _, err := m.store.UpdateCertificate(cert.CertID, cert); err != nil {
+				fmt.Printf("⚠️  Warning: failed to update document URL of %s: %v\n", cert.CertID, err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// migrate re-pins one file, reporting whether it had to be copied
+func (m *ContentMigrationService) migrate(source ContentStore, cert models.Certificate) (bool, error) {
+	cid := cert.IPFSCID
+	if !isCIDv1(cid) {
+		return false, fmt.Errorf("identifier is not a CIDv1, so it cannot be kept on another provider")
+	}
+	if stat, err := m.target.Stat(cid); err == nil && stat.Pinned {
+		return false, nil
+	}
+
+	data, err := source.Get(context.Background(), cid)
+	if err != nil {
+		return false, err
+	}
+	if err := verifyCID(source.Name(), cid, data); err != nil {
+		return false, err
+	}
+	stored, err := m.target.Put(context.Background(), data, cert.CertID, map[string]interface{}{"cert_id": cert.CertID})
+	if err != nil {
+		return false, err
+	}
+	if stored != cid {
+		return false, fmt.Errorf("%s stored the file as %s instead of %s", m.target.Name(), stored, cid)
+	}
+	return true, nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+)
+
+// S3Store keeps content in an S3-compatible bucket such as MinIO, addressed by CID.
+// Requests are signed with AWS Signature Version 4 using path-style URLs.
+type S3Store struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+	client    *http.Client
+}
+
+func NewS3Store(cfg config.Config) (*S3Store, error) {
+	if cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3 credentials not configured - please set S3_ACCESS_KEY and S3_SECRET_KEY environment variables")
+	}
+	endpoint, err := url.Parse(cfg.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.S3Endpoint)
+	}
+	publicURL := cfg.S3PublicURL
+	if publicURL == "" {
+		publicURL = cfg.S3Endpoint + "/" + cfg.S3Bucket
+	}
+	return &S3Store{
+		endpoint:  endpoint,
+		region:    cfg.S3Region,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		publicURL: publicURL,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}, nil
+}
+
+func (s *S3Store) Name() string {
+	return "s3"
+}
+
+// Put uploads the file under its CID, carrying string metadata as x-amz-meta headers
+func (s *S3Store) Put(ctx context.Context, data []byte, name string, metadata map[string]interface{}) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	key := contentKey(data)
+	headers := s.objectHeaders(data, name, metadata)
+	if _, _, err := s.do(ctx, "PUT", key, data, headers); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// PutStream uploads the source without loading it; its precomputed SHA-256 signs the payload
+func (s *S3Store) PutStream(ctx context.Context, src ContentSource, name string, metadata map[string]interface{}) (string, error) {
+	if src.Size == 0 {
+		return "", fmt.Errorf("file data is empty")
+	}
+	r, err := src.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open content: %w", err)
+	}
+	defer r.Close()
+
+	// The type is sniffed from the first bytes, as Put does, and those bytes are then sent first
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+	headers := s.objectHeaders(head[:n], name, metadata)
+	if _, _, err := s.doStream(ctx, "PUT", src.CID, io.MultiReader(bytes.NewReader(head[:n]), r), src.Size, src.SHA256, headers); err != nil {
+		return "", err
+	}
+	return src.CID, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, cid string) ([]byte, error) {
+	if !validContentKey(cid) {
+		return nil, fmt.Errorf("invalid content key %q", cid)
+	}
+	body, _, err := s.do(ctx, "GET", cid, nil, nil)
+	return body, err
+}
+
+// Pin confirms the object exists; buckets keep everything they hold
+func (s *S3Store) Pin(cid string) error {
+	_, err := s.Stat(cid)
+	return err
+}
+
+// Unpin deletes the object, since a bucket has no separate notion of pinning
+func (s *S3Store) Unpin(cid string) error {
+	if !validContentKey(cid) {
+		return fmt.Errorf("invalid content key %q", cid)
+	}
+	_, _, err := s.do(context.Background(), "DELETE", cid, nil, nil)
+	return err
+}
+
+func (s *S3Store) Stat(cid string) (*ContentStat, error) {
+	if !validContentKey(cid) {
+		return nil, fmt.Errorf("invalid content key %q", cid)
+	}
+	_, header, err := s.do(context.Background(), "HEAD", cid, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	size, _ := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	return &ContentStat{CID: cid, Size: size, Pinned: true}, nil
+}
+
+func (s *S3Store) URL(cid string) string {
+	return s.publicURL + "/" + cid
+}
+
+// objectHeaders carries the sniffed content type, file name and string metadata as x-amz-meta headers
+func (s *S3Store) objectHeaders(head []byte, name string, metadata map[string]interface{}) http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Type", http.DetectContentType(head))
+	if name != "" {
+		headers.Set("X-Amz-Meta-Filename", name)
+	}
+	for k, v := range metadata {
+		if value, ok := v.(string); ok && isHeaderToken(k) && isPrintableASCII(value) {
+			headers.Set("X-Amz-Meta-"+k, value)
+		}
+	}
+	return headers
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, headers http.Header) ([]byte, http.Header, error) {
+	return s.doStream(ctx, method, key, bytes.NewReader(body), int64(len(body)), contentSHA256(body), headers)
+}
+
+func (s *S3Store) doStream(ctx context.Context, method, key string, body io.Reader, size int64, payloadHash string, headers http.Header) ([]byte, http.Header, error) {
+	u := *s.endpoint
+	u.Path = "/" + s.bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	req.ContentLength = size
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reach S3 endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read S3 response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, nil, fmt.Errorf("object %s not found in bucket %s", key, s.bucket)
+		}
+		return nil, nil, fmt.Errorf("S3 %s failed (status %d): %s", method, resp.StatusCode, string(respBody))
+	}
+	return respBody, resp.Header, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header covering host and every x-amz header
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		if lower := strings.ToLower(k); strings.HasPrefix(lower, "x-amz-") {
+			signed[lower] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// isHeaderToken keeps metadata keys that are safe to send as header names
+func isHeaderToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrintableASCII(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
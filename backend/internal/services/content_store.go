@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+
+	"blockcred-backend/internal/config"
+)
+
+// ContentStore keeps certificate documents. Put returns the identifier recorded on the
+// certificate, which is the content's CIDv1 on every backend, so the same bytes keep the
+// same identifier when they move between providers. Put and Get run on request paths and
+// stop when ctx ends.
+type ContentStore interface {
+	Name() string
+	Put(ctx context.Context, data []byte, name string, metadata map[string]interface{}) (string, error)
+	Get(ctx context.Context, cid string) ([]byte, error)
+	Pin(cid string) error
+	Unpin(cid string) error
+	Stat(cid string) (*ContentStat, error)
+	URL(cid string) string
+}
+
+// ContentStat describes stored content
+type ContentStat struct {
+	CID    string `json:"cid"`
+	Size   int64  `json:"size"`
+	Pinned bool   `json:"pinned"` // Object stores keep everything they hold, so their content is always pinned
+}
+
+// ContentSource is content that can be read more than once, so a failed upload can be retried
+type ContentSource struct {
+	Open   func() (io.ReadCloser, error)
+	Size   int64
+	SHA256 string // Hex digest of the content
+	CID    string // CIDv1 of the content, computed locally
+	data   []byte // Set when the content is already in memory
+}
+
+// bytesSource wraps content that is already in memory
+func bytesSource(data []byte) ContentSource {
+	return ContentSource{
+		Open:   func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil },
+		Size:   int64(len(data)),
+		SHA256: contentSHA256(data),
+		CID:    ComputeCID(data),
+		data:   data,
+	}
+}
+
+// StreamingContentStore accepts content as a stream, so large files never have to sit in memory.
+// Stores check that the content is kept under src.CID.
+type StreamingContentStore interface {
+	PutStream(ctx context.Context, src ContentSource, name string, metadata map[string]interface{}) (string, error)
+}
+
+// putContent stores a source, streaming it when both the source and the store allow
+func putContent(ctx context.Context, store ContentStore, src ContentSource, name string, metadata map[string]interface{}) (string, error) {
+	if streaming, ok := store.(StreamingContentStore); ok && src.data == nil {
+		return streaming.PutStream(ctx, src, name, metadata)
+	}
+	data := src.data
+	if data == nil {
+		r, err := src.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to open content: %w", err)
+		}
+		defer r.Close()
+		if data, err = io.ReadAll(r); err != nil {
+			return "", fmt.Errorf("failed to read content: %w", err)
+		}
+	}
+	return store.Put(ctx, data, name, metadata)
+}
+
+// multipartStream encodes form fields and then the file of src as multipart/form-data without
+// buffering it; the returned body is fed from a goroutine that stops when the body is closed
+func multipartStream(src ContentSource, fileName string, fields [][2]string) (io.ReadCloser, string, error) {
+	file, err := src.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open content: %w", err)
+	}
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		defer file.Close()
+		for _, field := range fields {
+			if err := w.WriteField(field[0], field[1]); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		fw, err := w.CreateFormFile("file", fileName)
+		if err == nil {
+			_, err = io.Copy(fw, file)
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, w.FormDataContentType(), nil
+}
+
+// NewContentStore builds the backend named by CONTENT_STORE
+func NewContentStore(cfg config.Config) (ContentStore, error) {
+	switch strings.ToLower(cfg.ContentStore) {
+	case "", "pinata":
+		return NewIPFSService(cfg), nil
+	case "kubo", "ipfs":
+		return NewKuboStore(cfg), nil
+	case "s3", "minio":
+		return NewS3Store(cfg)
+	case "filesystem", "fs":
+		return NewFilesystemStore(cfg)
+	}
+	return nil, fmt.Errorf("unknown content store %q (expected pinata, kubo, s3 or filesystem)", cfg.ContentStore)
+}
+
+func contentSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// contentKey names content in object stores that are not content-addressed themselves
+func contentKey(data []byte) string {
+	return ComputeCID(data)
+}
+
+// validContentKey guards object store paths against traversal. Content stored before keys
+// became CIDs is still addressed by its hex SHA-256.
+func validContentKey(key string) bool {
+	if isCIDv1(key) {
+		return true
+	}
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var didContexts = []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/multikey/v1"}
+
+const ethrContext = "https://w3id.org/security/suites/secp256k1recovery-2020/v2"
+
+// DIDService publishes the institution's did:web document and maps on-chain issuers to did:ethr
+type DIDService struct {
+	store     store.Store
+	keys      *InstitutionKeys
+	chainID   int64
+	verifyURL string
+}
+
+func NewDIDService(cfg config.Config, s store.Store, keys *InstitutionKeys) *DIDService {
+	return &DIDService{
+		store:     s,
+		keys:      keys,
+		chainID:   cfg.ChainID,
+		verifyURL: cfg.PublicVerifyURL,
+	}
+}
+
+// InstitutionDocument builds the did:web document. Retired keys stay listed under assertionMethod
+// so credentials signed before a rotation remain verifiable.
+func (d *DIDService) InstitutionDocument() models.DIDDocument {
+	did := d.keys.DID()
+	doc := models.DIDDocument{
+		Context:            didContexts,
+		ID:                 did,
+		VerificationMethod: []models.DIDVerificationMethod{},
+		Service: []models.DIDService{{
+			ID:              did + "#certificate-verification",
+			Type:            "CertificateVerificationService",
+			ServiceEndpoint: d.verifyURL,
+		}},
+	}
+	for _, key := range d.keys.Keys() {
+		doc.VerificationMethod = append(doc.VerificationMethod, models.DIDVerificationMethod{
+			ID:                 key.KeyID,
+			Type:               "Multikey",
+			Controller:         did,
+			PublicKeyMultibase: key.PublicKeyMultibase,
+		})
+		doc.AssertionMethod = append(doc.AssertionMethod, key.KeyID)
+	}
+	doc.Authentication = []string{d.keys.CurrentKeyID()}
+	return doc
+}
+
+// Resolve returns the DID document for the institution's did:web or any did:ethr on our chain
+func (d *DIDService) Resolve(did string) (*models.DIDDocument, error) {
+	if did == d.keys.DID() {
+		doc := d.InstitutionDocument()
+		return &doc, nil
+	}
+	if !strings.HasPrefix(did, "did:ethr:") {
+		return nil, fmt.Errorf("DID not found: unsupported method")
+	}
+
+	address, err := d.parseEthrDID(did)
+	if err != nil {
+		return nil, err
+	}
+	canonical := ethrDID(d.chainID, address)
+	controller := canonical + "#controller"
+	doc := &models.DIDDocument{
+		Context: []string{didContexts[0], ethrContext},
+		ID:      canonical,
+		VerificationMethod: []models.DIDVerificationMethod{{
+			ID:                  controller,
+			Type:                "EcdsaSecp256k1RecoveryMethod2020",
+			Controller:          canonical,
+			BlockchainAccountID: fmt.Sprintf("eip155:%d:%s", d.chainID, address),
+		}},
+		Authentication:  []string{controller},
+		AssertionMethod: []string{controller},
+	}
+	// Registered issuers are also controlled by the institution that onboarded them
+	if _, err := d.store.GetIssuerDIDByAddress(address); err == nil {
+		doc.Controller = d.keys.DID()
+	}
+	return doc, nil
+}
+
+// RecordIssuer stores the did:ethr mapping for an address registered through RegisterIssuer
+func (d *DIDService) RecordIssuer(address, name, role, institution, actorID string) (*models.IssuerDID, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid issuer address %q", address)
+	}
+	checksummed := common.HexToAddress(address).Hex()
+	issuer, err := d.store.UpsertIssuerDID(models.IssuerDID{
+		DID:          ethrDID(d.chainID, checksummed),
+		Address:      checksummed,
+		Name:         name,
+		Role:         role,
+		Institution:  institution,
+		RegisteredBy: actorID,
+		RegisteredAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record issuer DID: %w", err)
+	}
+	return &issuer, nil
+}
+
+// ListIssuers returns every issuer address mapped to a did:ethr
+func (d *DIDService) ListIssuers() ([]models.IssuerDID, error) {
+	return d.store.ListIssuerDIDs()
+}
+
+// Keys returns the institution's signing key history
+func (d *DIDService) Keys() []models.InstitutionKey {
+	return d.keys.Keys()
+}
+
+// RotateKey replaces the institution's signing key; the old key keeps verifying earlier credentials
+func (d *DIDService) RotateKey(actorID, reason string) (*models.InstitutionKey, error) {
+	actor, err := d.store.GetUserByID(actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !actor.CanPerformAction("can_manage_users") {
+		return nil, fmt.Errorf("%w: only administrators can rotate the institution key", ErrPermissionDenied)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("rotation reason is required")
+	}
+	return d.keys.Rotate(actorID, reason)
+}
+
+// parseEthrDID accepts did:ethr:<address> and did:ethr:<chain>:<address> for our chain
+func (d *DIDService) parseEthrDID(did string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(did, "did:ethr:"), ":")
+	address := parts[len(parts)-1]
+	switch len(parts) {
+	case 1:
+	case 2:
+		chainID, err := strconv.ParseInt(parts[0], 0, 64)
+		if err != nil || chainID != d.chainID {
+			return "", fmt.Errorf("DID not found: network %s is not served here", parts[0])
+		}
+	default:
+		return "", fmt.Errorf("invalid did:ethr %q", did)
+	}
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid did:ethr %q", did)
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
+
+// ethrDID formats an Ethereum address as did:ethr with a hex chain ID, e.g. did:ethr:0x539:0xAbC...
+func ethrDID(chainID int64, address string) string {
+	return fmt.Sprintf("did:ethr:0x%x:%s", chainID, common.HexToAddress(address).Hex())
+}
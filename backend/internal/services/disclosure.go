@@ -0,0 +1,312 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// SelectiveDisclosureService lets students reveal a subset of certificate fields (SD-JWT style).
+// At issuance every metadata field becomes a salted disclosure, and the hash over all disclosure
+// digests is anchored on-chain as the certificate's metadata hash.
+type SelectiveDisclosureService struct {
+	store      store.Store
+	vc         *VCService
+	blockchain BlockchainServiceInterface
+}
+
+func NewSelectiveDisclosureService(s store.Store, vc *VCService, blockchain BlockchainServiceInterface) *SelectiveDisclosureService {
+	return &SelectiveDisclosureService{
+		store:      s,
+		vc:         vc,
+		blockchain: blockchain,
+	}
+}
+
+// newDisclosures creates one salted disclosure per non-empty metadata field and returns them with
+// the commitment that is anchored on-chain
+func newDisclosures(studentID string, meta models.CertificateMetadata) ([]string, string, error) {
+	fields := []struct {
+		name  string
+		value interface{}
+		set   bool
+	}{
+		{"student_id", studentID, studentID != ""},
+		{"student_name", meta.StudentName, meta.StudentName != ""},
+		{"student_email", meta.StudentEmail, meta.StudentEmail != ""},
+		{"issuer_name", meta.IssuerName, meta.IssuerName != ""},
+		{"issuer_role", string(meta.IssuerRole), meta.IssuerRole != ""},
+		{"institution", meta.Institution, meta.Institution != ""},
+		{"department", meta.Department, meta.Department != ""},
+		{"course", meta.Course, meta.Course != ""},
+		{"semester", meta.Semester, meta.Semester != ""},
+		{"academic_year", meta.AcademicYear, meta.AcademicYear != ""},
+		{"grade", meta.Grade, meta.Grade != ""},
+		{"cgpa", meta.CGPA, meta.CGPA != 0},
+		{"valid_from", meta.ValidFrom.UTC().Format(time.RFC3339), !meta.ValidFrom.IsZero()},
+		{"valid_until", meta.ValidUntil.UTC().Format(time.RFC3339), !meta.ValidUntil.IsZero()},
+		{"description", meta.Description, meta.Description != ""},
+	}
+
+	seen := map[string]bool{}
+	var disclosures []string
+	add := func(name string, value interface{}) error {
+		d, err := newDisclosure(name, value)
+		if err != nil {
+			return fmt.Errorf("failed to create disclosure for %s: %w", name, err)
+		}
+		seen[name] = true
+		disclosures = append(disclosures, d)
+		return nil
+	}
+	for _, f := range fields {
+		if f.set {
+			if err := add(f.name, f.value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	// Additional data keys are disclosable too, unless they shadow a standard field
+	extra := make([]string, 0, len(meta.AdditionalData))
+	for k := range meta.AdditionalData {
+		if !seen[k] {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+	for _, k := range extra {
+		if err := add(k, meta.AdditionalData[k]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	digests := make([]string, len(disclosures))
+	for i, d := range disclosures {
+		digests[i] = disclosureDigest(d)
+	}
+	return disclosures, sdCommitment(digests), nil
+}
+
+// newDisclosure encodes base64url(JSON [salt, name, value]) with a 128-bit random salt
+func newDisclosure(name string, value interface{}) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal([]interface{}{base64.RawURLEncoding.EncodeToString(salt), name, value})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func disclosureDigest(disclosure string) string {
+	sum := sha256.Sum256([]byte(disclosure))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sdCommitment is SHA-256 over the sorted digests joined by ".", hex encoded like other on-chain hashes
+func sdCommitment(digests []string) string {
+	sorted := append([]string(nil), digests...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ".")))
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeDisclosure returns the field name and value of a disclosure
+func decodeDisclosure(disclosure string) (string, interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(disclosure)
+	if err != nil {
+		return "", nil, fmt.Errorf("disclosure is not base64url: %w", err)
+	}
+	var parts []interface{}
+	if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != 3 {
+		return "", nil, fmt.Errorf("disclosure is not a [salt, name, value] array")
+	}
+	name, ok := parts[1].(string)
+	if !ok || name == "" {
+		return "", nil, fmt.Errorf("disclosure has no field name")
+	}
+	return name, parts[2], nil
+}
+
+// CreatePresentation builds an SD-JWT presentation revealing only the requested fields
+func (d *SelectiveDisclosureService) CreatePresentation(certID string, user models.User, fields []string) (*models.SDPresentation, error) {
+	cert, err := d.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found: %w", err)
+	}
+	if user.StudentID == "" || user.StudentID != cert.StudentID {
+		return nil, fmt.Errorf("%w: only the certificate holder can create presentations", ErrPermissionDenied)
+	}
+	if cert.Status != models.CertStatusIssued && cert.Status != models.CertStatusVerified {
+		return nil, fmt.Errorf("%w: certificate is %s", ErrCredentialUnavailable, cert.Status)
+	}
+	if len(cert.Disclosures) == 0 {
+		return nil, fmt.Errorf("%w: certificate was issued without field commitments", ErrCredentialUnavailable)
+	}
+
+	fields = uniqueStrings(fields)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields is required")
+	}
+	byName := make(map[string]string, len(cert.Disclosures))
+	digests := make([]string, 0, len(cert.Disclosures))
+	for _, disclosure := range cert.Disclosures {
+		name, _, err := decodeDisclosure(disclosure)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt disclosure on certificate %s: %w", certID, err)
+		}
+		byName[name] = disclosure
+		digests = append(digests, disclosureDigest(disclosure))
+	}
+	sort.Strings(digests)
+
+	selected := make([]string, 0, len(fields))
+	for _, field := range fields {
+		disclosure, ok := byName[field]
+		if !ok {
+			return nil, fmt.Errorf("field %s is not disclosable on this certificate", field)
+		}
+		selected = append(selected, disclosure)
+	}
+
+	token, err := d.vc.signJWS(map[string]string{"typ": "vc+sd-jwt"}, map[string]interface{}{
+		"iss":       d.vc.keys.DID(),
+		"iat":       time.Now().Unix(),
+		"cert_id":   cert.CertID,
+		"cert_type": string(cert.CertType),
+		"issued_at": cert.IssuedAt.UTC().Format(time.RFC3339),
+		"_sd":       digests,
+		"_sd_alg":   "sha-256",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign presentation: %w", err)
+	}
+
+	return &models.SDPresentation{
+		CertID:       cert.CertID,
+		Fields:       fields,
+		Presentation: token + "~" + strings.Join(selected, "~") + "~",
+	}, nil
+}
+
+// Verify checks the issuer signature, matches each disclosure against the signed digests and
+// confirms the digests hash to the commitment anchored for the certificate
+func (d *SelectiveDisclosureService) Verify(presentation string) (*models.SDVerificationResult, error) {
+	parts := strings.Split(strings.TrimSpace(presentation), "~")
+	if len(parts) < 2 || strings.Count(parts[0], ".") != 2 {
+		return nil, fmt.Errorf("presentation must be <jwt>~<disclosure>~...~")
+	}
+	result := &models.SDVerificationResult{Disclosed: map[string]interface{}{}}
+
+	claims, _, err := d.vc.verifyJWT(parts[0])
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.SignatureValid = true
+	}
+	if claims == nil {
+		return result, nil
+	}
+	if iss, _ := claims["iss"].(string); iss != d.vc.keys.DID() {
+		result.Errors = append(result.Errors, fmt.Sprintf("presentation was not issued by %s", d.vc.keys.DID()))
+	}
+	result.CertID, _ = claims["cert_id"].(string)
+	result.CertType, _ = claims["cert_type"].(string)
+
+	signed := map[string]bool{}
+	var digests []string
+	sdList, _ := claims["_sd"].([]interface{})
+	for _, v := range sdList {
+		if s, ok := v.(string); ok {
+			signed[s] = true
+			digests = append(digests, s)
+		}
+	}
+	if alg, _ := claims["_sd_alg"].(string); alg != "sha-256" {
+		result.Errors = append(result.Errors, fmt.Sprintf("unsupported _sd_alg %q", alg))
+	}
+
+	// Every disclosure must hash to a digest the issuer signed
+	for _, disclosure := range parts[1:] {
+		if disclosure == "" {
+			continue
+		}
+		name, value, err := decodeDisclosure(disclosure)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		digest := disclosureDigest(disclosure)
+		if !signed[digest] {
+			result.Errors = append(result.Errors, fmt.Sprintf("disclosure for %s does not match any committed field", name))
+			continue
+		}
+		delete(signed, digest)
+		result.Disclosed[name] = value
+	}
+	result.Undisclosed = len(signed)
+
+	if result.CertID == "" {
+		result.Errors = append(result.Errors, "presentation has no certificate reference")
+	} else {
+		result.Errors = append(result.Errors, d.checkCommitment(result, sdCommitment(digests))...)
+	}
+
+	result.Valid = result.SignatureValid && len(result.Errors) == 0
+	return result, nil
+}
+
+// checkCommitment compares the signed digests with the anchored commitment and checks certificate status.
+// The chain value is preferred; nodes that cannot return the full hash fall back to the stored record.
+func (d *SelectiveDisclosureService) checkCommitment(result *models.SDVerificationResult, commitment string) []string {
+	var problems []string
+
+	cert, err := d.store.GetCertificateByCertID(result.CertID)
+	if err != nil {
+		return append(problems, "certificate not found")
+	}
+
+	anchored, _ := cert.Metadata.AdditionalData["metadata_hash"].(string)
+	result.CommitmentSource = "record"
+	if d.blockchain != nil {
+		if onChain, err := d.blockchain.GetCertificateOnChain(result.CertID); err == nil {
+			if h := strings.TrimPrefix(onChain.MetadataHash, "0x"); isSHA256Hex(h) {
+				anchored = h
+				result.CommitmentSource = "blockchain"
+			}
+		}
+	}
+	if !strings.EqualFold(anchored, commitment) {
+		problems = append(problems, "disclosed fields do not match the commitment anchored for this certificate")
+	}
+
+	check, err := d.vc.certificates.VerifyCertificate(result.CertID)
+	if err != nil {
+		return append(problems, fmt.Sprintf("certificate status check failed: %v", err))
+	}
+	result.Status = check.Status
+	if !check.IsValid {
+		problems = append(problems, check.ErrorMessage)
+	}
+	return problems
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
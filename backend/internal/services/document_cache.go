@@ -0,0 +1,72 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+)
+
+// documentCache keeps recently served certificate files in memory, keyed by their anchored
+// SHA-256. Only files that passed the hash check are added, and nothing is written to disk,
+// so encrypted documents never sit decrypted at rest.
+type documentCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List // Front is the most recently used
+	entries  map[string]*list.Element
+}
+
+type documentCacheEntry struct {
+	fileHash string
+	data     []byte
+}
+
+// newDocumentCache returns nil when caching is disabled
+func newDocumentCache(maxBytes int64) *documentCache {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &documentCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *documentCache) get(fileHash string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[fileHash]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*documentCacheEntry).data, true
+}
+
+func (c *documentCache) add(fileHash string, data []byte) {
+	if c == nil || int64(len(data)) > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[fileHash]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[fileHash] = c.order.PushFront(&documentCacheEntry{fileHash: fileHash, data: data})
+	c.size += int64(len(data))
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*documentCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.fileHash)
+		c.size -= int64(len(entry.data))
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pdf"
+	"blockcred-backend/internal/store"
+)
+
+// ErrDocumentRejected is returned for documents that fail the pre-issuance checks
+var ErrDocumentRejected = errors.New("document rejected")
+
+// maxImagePixels bounds the images that are fully decoded, so a small file cannot expand into gigabytes
+const maxImagePixels = 100_000_000
+
+// DocumentValidator runs the pre-issuance checks on documents supplied by issuers: it confirms the
+// file really is a well-formed PDF or image, removes active content and anything appended after the
+// end of the file, and has it scanned for malware. Documents that fail are rejected, or quarantined
+// for review when DOCUMENT_REJECT_ACTION is quarantine.
+type DocumentValidator struct {
+	store         store.Store
+	scanner       DocumentScanner
+	quarantineDir string // Empty when failing documents are only rejected
+}
+
+func NewDocumentValidator(cfg config.Config, s store.Store) (*DocumentValidator, error) {
+	scanner, err := NewDocumentScanner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	v := &DocumentValidator{store: s, scanner: scanner}
+	switch strings.ToLower(cfg.RejectAction) {
+	case "", "reject":
+	case "quarantine":
+		if err := os.MkdirAll(cfg.QuarantineDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create quarantine directory: %w", err)
+		}
+		v.quarantineDir = cfg.QuarantineDir
+	default:
+		return nil, fmt.Errorf("unknown document reject action %q (expected reject or quarantine)", cfg.RejectAction)
+	}
+	return v, nil
+}
+
+// documentOrigin is recorded with a quarantined document
+type documentOrigin struct {
+	fileName   string
+	uploadedBy string
+	studentID  string
+}
+
+// Validate checks a document held in memory and returns the cleaned version to issue
+func (v *DocumentValidator) Validate(data []byte, origin documentOrigin) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("file data is empty")
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+
+	// The document is scanned as uploaded, so an infected file is caught even if cleaning would alter it
+	result, err := v.scanner.Scan(bytesSource(data))
+	if err != nil {
+		return nil, fmt.Errorf("document could not be scanned: %w", err)
+	}
+	if !result.Clean {
+		return nil, v.reject(data, contentType, origin, "malware detected: "+result.Threat, result.Threat)
+	}
+
+	var cleaned []byte
+	switch contentType {
+	case "application/pdf":
+		var disarmed []string
+		if cleaned, disarmed, err = pdf.Sanitize(data); err == nil && len(disarmed) > 0 {
+			fmt.Printf("⚠️  Warning: Disarmed /%s in %s uploaded by %s\n", strings.Join(disarmed, ", /"), origin.fileName, origin.uploadedBy)
+		}
+	case "image/png":
+		cleaned, err = cleanPNG(data)
+	case "image/jpeg":
+		cleaned, err = cleanJPEG(data)
+	default:
+		return nil, fmt.Errorf("%w %s: certificate documents must be PDF, PNG or JPEG", ErrUnsupportedDocument, contentType)
+	}
+	if err != nil {
+		return nil, v.reject(data, contentType, origin, err.Error(), "")
+	}
+	return cleaned, nil
+}
+
+// ValidateUpload checks a spooled upload, rewriting the spool file and its digests when cleaning changed it.
+// The checks need the whole document, so it is read into memory for their duration.
+func (v *DocumentValidator) ValidateUpload(upload *Upload, origin documentOrigin) error {
+	data, err := upload.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	cleaned, err := v.Validate(data, origin)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(cleaned, data) {
+		return nil
+	}
+	return upload.replace(cleaned)
+}
+
+// reject builds the rejection error, first setting the document aside when quarantine is enabled
+func (v *DocumentValidator) reject(data []byte, contentType string, origin documentOrigin, reason, threat string) error {
+	rejected := fmt.Errorf("%w: %s", ErrDocumentRejected, reason)
+	if v.quarantineDir == "" {
+		return rejected
+	}
+
+	fileHash := contentSHA256(data)
+	if err := os.WriteFile(filepath.Join(v.quarantineDir, fileHash), data, 0o600); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to quarantine %s: %v\n", origin.fileName, err)
+		return rejected
+	}
+	doc, err := v.store.CreateQuarantinedDocument(models.QuarantinedDocument{
+		FileName:    origin.fileName,
+		FileHash:    fileHash,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Reason:      reason,
+		Threat:      threat,
+		UploadedBy:  origin.uploadedBy,
+		StudentID:   origin.studentID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to record quarantined document %s: %v\n", fileHash, err)
+		return rejected
+	}
+	return fmt.Errorf("%w; it was quarantined for review as %s", rejected, doc.ID.Hex())
+}
+
+// ListQuarantine lists documents held for review
+func (v *DocumentValidator) ListQuarantine(user models.User) ([]models.QuarantinedDocument, error) {
+	if !user.CanPerformAction("can_manage_users") {
+		return nil, fmt.Errorf("%w: only administrators can review quarantined documents", ErrPermissionDenied)
+	}
+	return v.store.ListQuarantinedDocuments()
+}
+
+// DiscardQuarantined deletes a quarantined document and its record
+func (v *DocumentValidator) DiscardQuarantined(id string, user models.User) error {
+	if !user.CanPerformAction("can_manage_users") {
+		return fmt.Errorf("%w: only administrators can discard quarantined documents", ErrPermissionDenied)
+	}
+	doc, err := v.store.GetQuarantinedDocument(id)
+	if err != nil {
+		return err
+	}
+	if err := v.store.DeleteQuarantinedDocument(id); err != nil {
+		return err
+	}
+
+	// Identical uploads share a file, so it is kept while another record still refers to it
+	remaining, err := v.store.ListQuarantinedDocuments()
+	if err != nil {
+		return err
+	}
+	for _, other := range remaining {
+		if other.FileHash == doc.FileHash {
+			return nil
+		}
+	}
+	if v.quarantineDir != "" {
+		if err := os.Remove(filepath.Join(v.quarantineDir, doc.FileHash)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove quarantined file: %w", err)
+		}
+	}
+	return nil
+}
+
+// cleanPNG decodes the image to prove it is well formed and drops anything after the IEND chunk
+func cleanPNG(data []byte) ([]byte, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("malformed PNG: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("PNG is %dx%d pixels, which is too large", cfg.Width, cfg.Height)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("malformed PNG: %w", err)
+	}
+
+	// Chunks are length, type, data and CRC; the decoder has already validated them up to IEND
+	for i := 8; i+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if string(data[i+4:i+8]) == "IEND" {
+			return data[:end], nil
+		}
+		i = end
+	}
+	return nil, fmt.Errorf("malformed PNG: missing IEND chunk")
+}
+
+// cleanJPEG decodes the image to prove it is well formed and drops anything after the end-of-image marker
+func cleanJPEG(data []byte) ([]byte, error) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("malformed JPEG: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("JPEG is %dx%d pixels, which is too large", cfg.Width, cfg.Height)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("malformed JPEG: %w", err)
+	}
+
+	// Walk the marker segments, skipping entropy-coded scan data, until EOI
+	for i := 2; i+1 < len(data); {
+		if data[i] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG: expected a marker at offset %d", i)
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			i++
+			continue
+		case marker == 0xD9:
+			return data[:i+2], nil
+		case marker >= 0xD0 && marker <= 0xD7, marker == 0x01:
+			i += 2
+			continue
+		}
+		if i+4 > len(data) {
+			break
+		}
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA {
+			for i+1 < len(data) && !(data[i] == 0xFF && data[i+1] != 0 && (data[i+1] < 0xD0 || data[i+1] > 0xD7)) {
+				i++
+			}
+		}
+	}
+	return nil, fmt.Errorf("malformed JPEG: missing end-of-image marker")
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+)
+
+const documentAlgorithm = "AES-256-GCM"
+
+// ErrDocumentIntegrity is returned when a stored file no longer matches the hash anchored for its certificate
+var ErrDocumentIntegrity = errors.New("stored document does not match its anchored hash")
+
+// DocumentCipher encrypts certificate files before they leave for the content store. Each file
+// gets its own data key, which is kept on the certificate wrapped with the institution KEK.
+type DocumentCipher struct {
+	kek   []byte
+	keyID string
+}
+
+// NewDocumentCipher returns nil when document encryption is switched off
+func NewDocumentCipher(cfg config.Config) (*DocumentCipher, error) {
+	if !cfg.EncryptDocuments {
+		log.Printf("⚠️  ENCRYPT_DOCUMENTS is off, certificate files are stored in the clear")
+		return nil, nil
+	}
+
+	var kek []byte
+	if cfg.DocumentKEK != "" {
+		var err error
+		kek, err = hex.DecodeString(cfg.DocumentKEK)
+		if err != nil || len(kek) != 32 {
+			return nil, fmt.Errorf("DOCUMENT_KEK must be 32 hex-encoded bytes")
+		}
+	} else {
+		// Development fallback: stable across restarts, but anyone who knows JWT_SECRET can read stored documents
+		log.Printf("⚠️  DOCUMENT_KEK not set, deriving the document key-encryption key from JWT_SECRET")
+		sum := sha256.Sum256([]byte("document-kek:" + cfg.JWTSecret))
+		kek = sum[:]
+	}
+	fingerprint := sha256.Sum256(kek)
+	return &DocumentCipher{kek: kek, keyID: hex.EncodeToString(fingerprint[:8])}, nil
+}
+
+// Seal encrypts a file under a fresh data key. The plaintext hash is bound as associated data,
+// so a stored file cannot be swapped for another certificate's.
+func (d *DocumentCipher) Seal(plaintext []byte, fileHash string) ([]byte, *models.DocumentEncryption, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	sealed, err := gcmSeal(dataKey, plaintext, []byte(fileHash))
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := gcmSeal(d.kek, dataKey, []byte(fileHash))
+	if err != nil {
+		return nil, nil, err
+	}
+	return sealed, &models.DocumentEncryption{
+		Algorithm:  documentAlgorithm,
+		KeyID:      d.keyID,
+		WrappedKey: wrapped,
+	}, nil
+}
+
+// Open unwraps the data key and decrypts a stored file
+func (d *DocumentCipher) Open(sealed []byte, enc *models.DocumentEncryption, fileHash string) ([]byte, error) {
+	if enc.Algorithm != documentAlgorithm {
+		return nil, fmt.Errorf("unsupported document encryption %q", enc.Algorithm)
+	}
+	if enc.KeyID != d.keyID {
+		return nil, fmt.Errorf("document was encrypted under key %s, which is not the configured DOCUMENT_KEK", enc.KeyID)
+	}
+	dataKey, err := gcmOpen(d.kek, enc.WrappedKey, []byte(fileHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap document key: %w", err)
+	}
+	plaintext, err := gcmOpen(dataKey, sealed, []byte(fileHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt document: %w", err)
+	}
+	return plaintext, nil
+}
+
+func gcmSeal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func gcmOpen(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], aad)
+}
+
+// CertificateDocument is a certificate file released to an authorized user
+type CertificateDocument struct {
+	CertID   string
+	FileHash string
+	IssuedAt time.Time
+	Data     []byte
+}
+
+// Document returns the decrypted file of a certificate to its student, its issuer or staff who can view all credentials
+func (c *CertificateService) Document(ctx context.Context, certID string, user models.User) (*CertificateDocument, error) {
+	cert, err := c.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, ErrCertificateNotFound
+	}
+	if !c.canAccessCertificate(user, cert) {
+		return nil, fmt.Errorf("%w: user cannot download this certificate", ErrPermissionDenied)
+	}
+	data, err := c.fetchDocument(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateDocument{CertID: cert.CertID, FileHash: cert.FileHash, IssuedAt: cert.IssuedAt, Data: data}, nil
+}
+
+// fetchDocument loads a certificate's file from the content store, decrypts it and checks it against the
+// anchored hash. Verified files are cached by that hash, so repeat downloads skip the store entirely.
+func (c *CertificateService) fetchDocument(ctx context.Context, cert models.Certificate) ([]byte, error) {
+	if data, ok := c.documentCache.get(cert.FileHash); ok {
+		return data, nil
+	}
+	data, err := c.content.Get(ctx, cert.IPFSCID)
+	if err != nil && c.pins != nil {
+		// The primary store lost the file or is down; any replica will do, the hash check below still applies
+		if replica, replicaErr := c.pins.FetchReplica(ctx, cert.IPFSCID); replicaErr == nil {
+			data, err = replica, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cert.Encryption != nil {
+		if c.documents == nil {
+			return nil, fmt.Errorf("certificate %s is encrypted but document encryption is not configured", cert.CertID)
+		}
+		if data, err = c.documents.Open(data, cert.Encryption, cert.FileHash); err != nil {
+			return nil, err
+		}
+	}
+	if c.computeFileHash(data) != cert.FileHash {
+		return nil, fmt.Errorf("%w: certificate %s", ErrDocumentIntegrity, cert.CertID)
+	}
+	c.documentCache.add(cert.FileHash, data)
+	return data, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"blockcred-backend/internal/models"
+)
+
+// ErrDuplicateCertificate is returned when the duplicate policy rejects an issuance that repeats an active certificate
+var ErrDuplicateCertificate = errors.New("duplicate certificate")
+
+// Duplicate policies, set through DUPLICATE_POLICY
+const (
+	DuplicatePolicyReject = "reject"
+	DuplicatePolicyWarn   = "warn"
+	DuplicatePolicyAllow  = "allow"
+)
+
+func normalizeDuplicatePolicy(policy string) string {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case DuplicatePolicyWarn:
+		return DuplicatePolicyWarn
+	case DuplicatePolicyAllow:
+		return DuplicatePolicyAllow
+	case "", DuplicatePolicyReject:
+		return DuplicatePolicyReject
+	}
+	fmt.Printf("⚠️  Warning: Unknown duplicate policy %q, rejecting duplicates\n", policy)
+	return DuplicatePolicyReject
+}
+
+// checkDuplicates applies the duplicate policy to an issuance. Under warn it returns the
+// repeated cert IDs so they can be recorded on the new certificate.
+func (c *CertificateService) checkDuplicates(req models.IssueCertificateRequest, fileHash, supersedes string) ([]string, error) {
+	if c.duplicatePolicy == DuplicatePolicyAllow {
+		return nil, nil
+	}
+	duplicates, err := c.findDuplicates(req, fileHash, supersedes)
+	if err != nil || len(duplicates) == 0 {
+		return nil, err
+	}
+	if c.duplicatePolicy == DuplicatePolicyReject {
+		return nil, fmt.Errorf("%w: %s for student %s repeats %s; reissue it instead", ErrDuplicateCertificate, req.CertType, req.StudentID, strings.Join(duplicates, ", "))
+	}
+	fmt.Printf("⚠️  Warning: Issuing %s for student %s although it repeats %s\n", req.CertType, req.StudentID, strings.Join(duplicates, ", "))
+	return duplicates, nil
+}
+
+// findDuplicates lists the student's active certificates of the same type that carry the same
+// document, or for marksheets cover the same semester. The version being reissued is ignored.
+func (c *CertificateService) findDuplicates(req models.IssueCertificateRequest, fileHash, supersedes string) ([]string, error) {
+	certs, err := c.store.ListCertificatesByStudent(req.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate certificates: %w", err)
+	}
+
+	semester := semesterKey(req.Metadata.Semester)
+	var duplicates []string
+	for _, cert := range certs {
+		if cert.CertType != req.CertType || cert.CertID == supersedes {
+			continue
+		}
+		if cert.Status == models.CertStatusRevoked || cert.Status == models.CertStatusSuperseded {
+			continue
+		}
+		sameFile := cert.FileHash == fileHash || cert.SourceHash == fileHash
+		sameSemester := req.CertType == models.CredentialTypeMarksheet && semester != "" && semesterKey(cert.Metadata.Semester) == semester
+		if sameFile || sameSemester {
+			duplicates = append(duplicates, cert.CertID)
+		}
+	}
+	return duplicates, nil
+}
+
+// semesterKey compares "Semester 5", "V" and "5" as the same semester
+func semesterKey(semester string) string {
+	if n := semesterNumber(semester); n > 0 {
+		return strconv.Itoa(n)
+	}
+	return strings.ToLower(strings.TrimSpace(semester))
+}
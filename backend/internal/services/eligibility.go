@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// ErrNotEligible is returned when a student does not meet the degree eligibility rule of their department
+var ErrNotEligible = errors.New("student is not eligible for a degree")
+
+const maxCGPA = 10
+
+// defaultEligibilityRule applies when neither the student's department nor the institution has configured one
+var defaultEligibilityRule = models.DegreeEligibilityRule{
+	RequiredSemesters:   8,
+	NoActiveSuspensions: true,
+	NoDuplicateDegree:   true,
+}
+
+// EligibilityService manages the per-department rules checked before a degree is issued
+type EligibilityService struct {
+	store store.Store
+}
+
+func NewEligibilityService(s store.Store) *EligibilityService {
+	return &EligibilityService{store: s}
+}
+
+// ListRules returns every configured rule; the one with an empty department is the institution default
+func (e *EligibilityService) ListRules() ([]models.DegreeEligibilityRule, error) {
+	return e.store.ListEligibilityRules()
+}
+
+// SetRule creates or replaces the degree rule of a department
+func (e *EligibilityService) SetRule(req models.SetEligibilityRuleRequest, actorID string) (models.DegreeEligibilityRule, error) {
+	actor, err := e.store.GetUserByID(actorID)
+	if err != nil {
+		return models.DegreeEligibilityRule{}, fmt.Errorf("user not found: %w", err)
+	}
+	if !actor.CanPerformAction("can_manage_users") {
+		return models.DegreeEligibilityRule{}, fmt.Errorf("%w: only administrators can change eligibility rules", ErrPermissionDenied)
+	}
+	if req.RequiredSemesters < 0 {
+		return models.DegreeEligibilityRule{}, fmt.Errorf("required_semesters cannot be negative")
+	}
+	if req.MinCGPA < 0 || req.MinCGPA > maxCGPA {
+		return models.DegreeEligibilityRule{}, fmt.Errorf("min_cgpa must be between 0 and %d", maxCGPA)
+	}
+
+	return e.store.UpsertEligibilityRule(models.DegreeEligibilityRule{
+		Department:          strings.TrimSpace(req.Department),
+		RequiredSemesters:   req.RequiredSemesters,
+		MinCGPA:             req.MinCGPA,
+		NoActiveSuspensions: req.NoActiveSuspensions,
+		NoDuplicateDegree:   req.NoDuplicateDegree,
+		UpdatedBy:           actorID,
+		UpdatedAt:           time.Now(),
+	})
+}
+
+// Check evaluates a student against their department's rule without issuing anything
+func (e *EligibilityService) Check(studentID string, viewer models.User) (*models.DegreeEligibilityResult, error) {
+	isSelf := viewer.StudentID != "" && viewer.StudentID == studentID
+	if !isSelf && !viewer.CanPerformAction("can_view_all_credentials") && !viewer.CanPerformAction("can_issue_marksheet") {
+		return nil, fmt.Errorf("%w: eligibility is visible to the student and examination staff", ErrPermissionDenied)
+	}
+	return degreeEligibility(e.store, studentID, "")
+}
+
+// checkDegreeEligibility fails with ErrNotEligible listing every unmet condition.
+// supersedes names a degree being reissued, which does not count against the student.
+func (c *CertificateService) checkDegreeEligibility(studentID, supersedes string) error {
+	result, err := degreeEligibility(c.store, studentID, supersedes)
+	if err != nil {
+		return err
+	}
+	if !result.Eligible {
+		return fmt.Errorf("%w: %s", ErrNotEligible, strings.Join(result.Unmet, "; "))
+	}
+	return nil
+}
+
+// eligibilityRule picks the department's rule, then the institution default, then the built-in default
+func eligibilityRule(s store.Store, department string) models.DegreeEligibilityRule {
+	if department != "" {
+		if rule, err := s.GetEligibilityRule(department); err == nil {
+			return rule
+		}
+	}
+	if rule, err := s.GetEligibilityRule(""); err == nil {
+		return rule
+	}
+	return defaultEligibilityRule
+}
+
+func degreeEligibility(s store.Store, studentID, supersedes string) (*models.DegreeEligibilityResult, error) {
+	student, err := s.GetUserByStudentID(studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found: %w", err)
+	}
+	certs, err := s.ListCertificatesByStudent(studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve certificates: %w", err)
+	}
+
+	rule := eligibilityRule(s, student.Department)
+	result := &models.DegreeEligibilityResult{
+		StudentID:  studentID,
+		Department: student.Department,
+		Rule:       rule,
+		Conditions: []models.EligibilityCondition{},
+	}
+	check := func(condition string, met bool, detail string) {
+		result.Conditions = append(result.Conditions, models.EligibilityCondition{Condition: condition, Met: met, Detail: detail})
+		if !met {
+			result.Unmet = append(result.Unmet, detail)
+		}
+	}
+
+	// A student without any current marksheet has no transcript; both marksheet conditions then fail
+	summary, _ := summarizeMarksheets(student, certs)
+
+	if rule.RequiredSemesters > 0 {
+		present := map[int]bool{}
+		if summary != nil {
+			for _, entry := range summary.Entries {
+				present[entry.SemesterNumber] = true
+			}
+		}
+		var missing []string
+		for sem := 1; sem <= rule.RequiredSemesters; sem++ {
+			if !present[sem] {
+				missing = append(missing, describeMissingSemester(certs, sem))
+			}
+		}
+		if len(missing) == 0 {
+			check("semester_marksheets", true, fmt.Sprintf("all %d semester marksheets are present", rule.RequiredSemesters))
+		} else {
+			check("semester_marksheets", false, fmt.Sprintf("missing current marksheets for %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	if rule.MinCGPA > 0 {
+		switch {
+		case summary == nil:
+			check("min_cgpa", false, fmt.Sprintf("no marksheets to compute CGPA against the minimum of %.2f", rule.MinCGPA))
+		case summary.CumulativeCGPA < rule.MinCGPA:
+			check("min_cgpa", false, fmt.Sprintf("CGPA %.2f is below the minimum of %.2f", summary.CumulativeCGPA, rule.MinCGPA))
+		default:
+			check("min_cgpa", true, fmt.Sprintf("CGPA %.2f meets the minimum of %.2f", summary.CumulativeCGPA, rule.MinCGPA))
+		}
+	}
+
+	if rule.NoActiveSuspensions {
+		var held []string
+		for _, cert := range certs {
+			if cert.Status != models.CertStatusSuspended || cert.CertID == supersedes {
+				continue
+			}
+			reason := ""
+			if cert.Suspension != nil {
+				reason = " (" + cert.Suspension.Reason + ")"
+			}
+			held = append(held, fmt.Sprintf("%s %s%s", cert.CertType, cert.CertID, reason))
+		}
+		if len(held) == 0 {
+			check("no_active_suspensions", true, "no certificates are on hold")
+		} else {
+			check("no_active_suspensions", false, fmt.Sprintf("certificates on hold: %s", strings.Join(held, ", ")))
+		}
+	}
+
+	if rule.NoDuplicateDegree {
+		var existing []string
+		for _, cert := range certs {
+			if cert.CertType != models.CredentialTypeDegree || cert.CertID == supersedes {
+				continue
+			}
+			if cert.Status == models.CertStatusRevoked || cert.Status == models.CertStatusSuperseded {
+				continue
+			}
+			existing = append(existing, cert.CertID)
+		}
+		if len(existing) == 0 {
+			check("no_duplicate_degree", true, "no degree has been issued yet")
+		} else {
+			check("no_duplicate_degree", false, fmt.Sprintf("a degree has already been issued (%s), reissue it instead", strings.Join(existing, ", ")))
+		}
+	}
+
+	result.Eligible = len(result.Unmet) == 0
+	return result, nil
+}
+
+// describeMissingSemester names a semester and says why its marksheet does not count, if one exists
+func describeMissingSemester(certs []models.Certificate, sem int) string {
+	for _, cert := range certs {
+		if cert.CertType != models.CredentialTypeMarksheet || semesterNumber(cert.Metadata.Semester) != sem {
+			continue
+		}
+		switch cert.Status {
+		case models.CertStatusRevoked:
+			return fmt.Sprintf("semester %d (marksheet %s revoked)", sem, cert.CertID)
+		case models.CertStatusSuspended:
+			return fmt.Sprintf("semester %d (marksheet %s suspended)", sem, cert.CertID)
+		}
+	}
+	return fmt.Sprintf("semester %d", sem)
+}
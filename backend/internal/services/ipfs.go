@@ -2,19 +2,22 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
+	"net/url"
 	"time"
 
 	"blockcred-backend/internal/config"
 )
 
+// IPFSService stores content on IPFS through the Pinata pinning API
 type IPFSService struct {
 	config config.Config
 	client *http.Client
+	dep    *Dependency
 }
 
 type PinataResponse struct {
@@ -33,37 +36,35 @@ type PinataError struct {
 func NewIPFSService(cfg config.Config) *IPFSService {
 	return &IPFSService{
 		config: cfg,
+		// Attempt deadlines come from the dependency guard; this only bounds a stuck connection
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: 5 * time.Minute,
 		},
+		dep: dependencyFor(cfg, "pinata"),
 	}
 }
 
 // UploadFile uploads a file to IPFS via Pinata and returns the CID
 func (s *IPFSService) UploadFile(fileData []byte, fileName string, metadata map[string]interface{}) (string, error) {
-	if s.config.PinataAPIKey == "" || s.config.PinataAPISecret == "" {
-		return "", fmt.Errorf("Pinata API credentials not configured - please set PINATA_API_KEY and PINATA_API_SECRET environment variables")
-	}
-	
+	return s.UploadFileContext(context.Background(), fileData, fileName, metadata)
+}
+
+// UploadFileContext is UploadFile bounded by ctx. Uploads are retried: pinning the same bytes twice yields the same CID.
+func (s *IPFSService) UploadFileContext(ctx context.Context, fileData []byte, fileName string, metadata map[string]interface{}) (string, error) {
 	// Validate input
 	if len(fileData) == 0 {
 		return "", fmt.Errorf("file data is empty")
 	}
-	if fileName == "" {
-		return "", fmt.Errorf("file name is required")
-	}
-
-	// Create multipart form data
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
+	return s.upload(ctx, bytesSource(fileData), fileName, metadata)
+}
 
-	// Add file
-	fw, err := w.CreateFormFile("file", fileName)
-	if err != nil {
-		return "", fmt.Errorf("failed to create form file: %w", err)
+// upload streams a file to pinFileToIPFS and checks the CID Pinata assigns against the one computed locally
+func (s *IPFSService) upload(ctx context.Context, src ContentSource, fileName string, metadata map[string]interface{}) (string, error) {
+	if s.config.PinataAPIKey == "" || s.config.PinataAPISecret == "" {
+		return "", fmt.Errorf("Pinata API credentials not configured - please set PINATA_API_KEY and PINATA_API_SECRET environment variables")
 	}
-	if _, err := fw.Write(fileData); err != nil {
-		return "", fmt.Errorf("failed to write file data: %w", err)
+	if fileName == "" {
+		return "", fmt.Errorf("file name is required")
 	}
 
 	// Add metadata with proper Pinata format
@@ -75,10 +76,6 @@ func (s *IPFSService) UploadFile(fileData []byte, fileName string, metadata map[
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal metadata: %w", err)
 	}
-	
-	if err := w.WriteField("pinataMetadata", string(metadataJSON)); err != nil {
-		return "", fmt.Errorf("failed to write metadata: %w", err)
-	}
 
 	// Add options
 	options := map[string]interface{}{
@@ -88,41 +85,23 @@ func (s *IPFSService) UploadFile(fileData []byte, fileName string, metadata map[
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal options: %w", err)
 	}
-	
-	if err := w.WriteField("pinataOptions", string(optionsJSON)); err != nil {
-		return "", fmt.Errorf("failed to write options: %w", err)
-	}
-
-	w.Close()
 
-	// Create request
-	req, err := http.NewRequest("POST", "https://api.pinata.cloud/pinning/pinFileToIPFS", &b)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", w.FormDataContentType())
-	req.Header.Set("pinata_api_key", s.config.PinataAPIKey)
-	req.Header.Set("pinata_secret_api_key", s.config.PinataAPISecret)
-
-	// Send request
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var pinataErr PinataError
-		if err := json.Unmarshal(body, &pinataErr); err != nil {
-			return "", fmt.Errorf("Pinata API error (status %d): %s", resp.StatusCode, string(body))
+	fields := [][2]string{{"pinataMetadata", string(metadataJSON)}, {"pinataOptions", string(optionsJSON)}}
+	body, err := s.send(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		form, contentType, err := multipartStream(src, fileName, fields)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", s.config.PinataAPIURL+"/pinning/pinFileToIPFS", form)
+		if err != nil {
+			form.Close()
+			return nil, err
 		}
-		return "", fmt.Errorf("Pinata API error: %s - %s", pinataErr.Error.Reason, pinataErr.Error.Details)
+		req.Header.Set("Content-Type", contentType)
+		return req, nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	var pinataResp PinataResponse
@@ -130,6 +109,12 @@ func (s *IPFSService) UploadFile(fileData []byte, fileName string, metadata map[
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	// Do not take Pinata's word for the CID; a mismatch means the pinned bytes are not ours
+	if err := checkCID("Pinata", pinataResp.IpfsHash, src.CID); err != nil {
+		s.Unpin(pinataResp.IpfsHash)
+		return "", err
+	}
+
 	return pinataResp.IpfsHash, nil
 }
 
@@ -138,8 +123,47 @@ func (s *IPFSService) GetFileURL(cid string) string {
 	return s.config.PinataGatewayURL + cid
 }
 
+// FetchFile downloads content from the configured gateway
+func (s *IPFSService) FetchFile(cid string) ([]byte, error) {
+	return s.FetchFileContext(context.Background(), cid)
+}
+
+// FetchFileContext is FetchFile bounded by ctx
+func (s *IPFSService) FetchFileContext(ctx context.Context, cid string) ([]byte, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+
+	var data []byte
+	err := s.dep.Do(ctx, true, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", s.GetFileURL(cid), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return upstreamTransportError("pinata", fmt.Errorf("failed to fetch from IPFS gateway: %w", err))
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return upstreamStatusError("pinata", resp.StatusCode, fmt.Errorf("IPFS gateway returned status %d for %s", resp.StatusCode, cid))
+		}
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return upstreamTransportError("pinata", fmt.Errorf("failed to read from IPFS gateway: %w", err))
+		}
+		return nil
+	})
+	return data, err
+}
+
 // PinJSON uploads JSON data to IPFS via Pinata
 func (s *IPFSService) PinJSON(data interface{}, name string) (string, error) {
+	return s.PinJSONContext(context.Background(), data, name)
+}
+
+// PinJSONContext is PinJSON bounded by ctx
+func (s *IPFSService) PinJSONContext(ctx context.Context, data interface{}, name string) (string, error) {
 	if s.config.PinataAPIKey == "" || s.config.PinataAPISecret == "" {
 		return "", fmt.Errorf("Pinata API credentials not configured")
 	}
@@ -165,40 +189,139 @@ func (s *IPFSService) PinJSON(data interface{}, name string) (string, error) {
 		return "", fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	// Create request
-	req, err := http.NewRequest("POST", "https://api.pinata.cloud/pinning/pinJSONToIPFS", bytes.NewBuffer(jsonBody))
+	body, err := s.send(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", s.config.PinataAPIURL+"/pinning/pinJSONToIPFS", bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("pinata_api_key", s.config.PinataAPIKey)
-	req.Header.Set("pinata_secret_api_key", s.config.PinataAPISecret)
+	var pinataResp PinataResponse
+	if err := json.Unmarshal(body, &pinataResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
 
-	// Send request
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+	return pinataResp.IpfsHash, nil
+}
+
+// ContentStore implementation
+
+func (s *IPFSService) Name() string {
+	return "pinata"
+}
+
+func (s *IPFSService) Put(ctx context.Context, data []byte, name string, metadata map[string]interface{}) (string, error) {
+	return s.UploadFileContext(ctx, data, name, metadata)
+}
+
+func (s *IPFSService) PutStream(ctx context.Context, src ContentSource, name string, metadata map[string]interface{}) (string, error) {
+	if src.Size == 0 {
+		return "", fmt.Errorf("file data is empty")
 	}
-	defer resp.Body.Close()
+	return s.upload(ctx, src, name, metadata)
+}
 
-	body, err := io.ReadAll(resp.Body)
+func (s *IPFSService) Get(ctx context.Context, cid string) ([]byte, error) {
+	return s.FetchFileContext(ctx, cid)
+}
+
+func (s *IPFSService) URL(cid string) string {
+	return s.GetFileURL(cid)
+}
+
+// Pin asks Pinata to fetch and pin content that is already on the IPFS network
+func (s *IPFSService) Pin(cid string) error {
+	body, err := json.Marshal(map[string]interface{}{"hashToPin": cid})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return fmt.Errorf("failed to marshal pin request: %w", err)
 	}
+	_, err = s.pinataRequest("POST", "/pinning/pinByHash", body)
+	return err
+}
+
+func (s *IPFSService) Unpin(cid string) error {
+	_, err := s.pinataRequest("DELETE", "/pinning/unpin/"+url.PathEscape(cid), nil)
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var pinataErr PinataError
-		if err := json.Unmarshal(body, &pinataErr); err != nil {
-			return "", fmt.Errorf("Pinata API error (status %d): %s", resp.StatusCode, string(body))
+func (s *IPFSService) Stat(cid string) (*ContentStat, error) {
+	body, err := s.pinataRequest("GET", "/data/pinList?status=pinned&hashContains="+url.QueryEscape(cid), nil)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Rows []struct {
+			IpfsPinHash string `json:"ipfs_pin_hash"`
+			Size        int64  `json:"size"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pin list: %w", err)
+	}
+	for _, row := range list.Rows {
+		if row.IpfsPinHash == cid {
+			return &ContentStat{CID: cid, Size: row.Size, Pinned: true}, nil
 		}
-		return "", fmt.Errorf("Pinata API error: %s - %s", pinataErr.Error.Reason, pinataErr.Error.Details)
 	}
+	return &ContentStat{CID: cid}, nil
+}
 
-	var pinataResp PinataResponse
-	if err := json.Unmarshal(body, &pinataResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+func (s *IPFSService) pinataRequest(method, path string, body []byte) ([]byte, error) {
+	if s.config.PinataAPIKey == "" || s.config.PinataAPISecret == "" {
+		return nil, fmt.Errorf("Pinata API credentials not configured")
 	}
 
-	return pinataResp.IpfsHash, nil
+	// Pinning by hash and unpinning are safe to repeat
+	return s.send(context.Background(), true, func(ctx context.Context) (*http.Request, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, s.config.PinataAPIURL+path, reader)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+}
+
+// send makes an authenticated Pinata API call through the dependency guard. newRequest builds
+// a fresh request for every attempt; the response body is returned for 200 responses.
+func (s *IPFSService) send(ctx context.Context, idempotent bool, newRequest func(ctx context.Context) (*http.Request, error)) ([]byte, error) {
+	var respBody []byte
+	err := s.dep.Do(ctx, idempotent, func(ctx context.Context) error {
+		req, err := newRequest(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("pinata_api_key", s.config.PinataAPIKey)
+		req.Header.Set("pinata_secret_api_key", s.config.PinataAPISecret)
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return upstreamTransportError("pinata", fmt.Errorf("failed to send request: %w", err))
+		}
+		defer resp.Body.Close()
+
+		if respBody, err = io.ReadAll(resp.Body); err != nil {
+			return upstreamTransportError("pinata", fmt.Errorf("failed to read response: %w", err))
+		}
+		if resp.StatusCode != http.StatusOK {
+			var pinataErr PinataError
+			if err := json.Unmarshal(respBody, &pinataErr); err != nil || pinataErr.Error.Reason == "" {
+				return upstreamStatusError("pinata", resp.StatusCode, fmt.Errorf("Pinata API error (status %d): %s", resp.StatusCode, string(respBody)))
+			}
+			return upstreamStatusError("pinata", resp.StatusCode, fmt.Errorf("Pinata API error: %s - %s", pinataErr.Error.Reason, pinataErr.Error.Details))
+		}
+		return nil
+	})
+	return respBody, err
 }
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"encoding/base64"
 	"fmt"
 	"log"
 )
@@ -25,17 +24,6 @@ func (s *IPFSService) DebugIPFSUpload(fileData []byte, fileName string, metadata
 	}
 	log.Printf("✅ File data size: %d bytes", len(fileData))
 	
-	// Check if file data is base64 encoded
-	if isBase64Encoded(fileData) {
-		log.Printf("📝 File data appears to be base64 encoded, decoding...")
-		decoded, err := base64.StdEncoding.DecodeString(string(fileData))
-		if err != nil {
-			return "", fmt.Errorf("❌ Failed to decode base64 file data: %w", err)
-		}
-		fileData = decoded
-		log.Printf("✅ Decoded file data size: %d bytes", len(fileData))
-	}
-	
 	// Check metadata
 	if metadata == nil {
 		log.Printf("⚠️  No metadata provided")
@@ -55,13 +43,6 @@ func (s *IPFSService) DebugIPFSUpload(fileData []byte, fileName string, metadata
 	return cid, nil
 }
 
-// isBase64Encoded checks if the data appears to be base64 encoded
-func isBase64Encoded(data []byte) bool {
-	// Simple heuristic: check if it's a valid base64 string
-	_, err := base64.StdEncoding.DecodeString(string(data))
-	return err == nil && len(data) > 0
-}
-
 // TestPinataConnection tests the connection to Pinata API
 func (s *IPFSService) TestPinataConnection() error {
 	log.Printf("🔍 Testing Pinata API connection...")
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// ErrKeyNotManaged is returned for purposes that have no key in this deployment
+var ErrKeyNotManaged = errors.New("no signing key is configured for this purpose")
+
+// ErrRotationUnsupported is returned when a key can only be replaced through configuration
+var ErrRotationUnsupported = errors.New("key cannot be rotated here")
+
+// ManagedKey is the signing key of one purpose along with the public halves of the keys it replaced
+type ManagedKey struct {
+	mu      sync.RWMutex
+	purpose string
+	current string
+	signer  Signer
+	records map[string]models.SigningKey
+	public  map[string]crypto.PublicKey
+}
+
+// Current returns the ID and signer of the key used for new signatures
+func (k *ManagedKey) Current() (string, Signer) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current, k.signer
+}
+
+// PublicKey resolves a key ID. Retired keys only verify signatures made before their retirement.
+func (k *ManagedKey) PublicKey(kid string, signedAt time.Time) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	pub, ok := k.public[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s key %q", k.purpose, kid)
+	}
+	if retired := k.records[kid].RetiredAt; retired != nil && signedAt.After(*retired) {
+		return nil, fmt.Errorf("key %s was retired on %s", kid, retired.Format(time.RFC3339))
+	}
+	return pub, nil
+}
+
+func (k *ManagedKey) keys() []models.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]models.SigningKey, 0, len(k.records))
+	for _, record := range k.records {
+		keys = append(keys, record)
+	}
+	return keys
+}
+
+// KeyManager keeps the inventory of the institution's signing keys and rotates them. Each purpose
+// starts from the key in configuration; keys created by rotation are recorded in the store and
+// take precedence until configuration names a key the inventory has not seen.
+type KeyManager struct {
+	mu          sync.Mutex // Serializes rotations
+	cfg         config.Config
+	store       store.Store
+	keys        map[string]*ManagedKey
+	credentials *InstitutionKeys
+	fixed       map[string]string // Purposes whose key is pinned by configuration, with the reason
+}
+
+// NewKeyManager loads the tokens key, the blockchain key when PRIVATE_KEY is set and the documents
+// key when PDF signing is on. Credential keys are kept by InstitutionKeys and only listed here.
+func NewKeyManager(cfg config.Config, s store.Store, credentials *InstitutionKeys) (*KeyManager, error) {
+	m := &KeyManager{
+		cfg:         cfg,
+		store:       s,
+		keys:        map[string]*ManagedKey{},
+		credentials: credentials,
+		fixed:       map[string]string{},
+	}
+	records, err := s.ListSigningKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	var tokens Signer
+	if cfg.TokenSigningKey != "" {
+		if tokens, err = LoadSigner(cfg, cfg.TokenSigningKey, KeyAlgorithmEd25519); err != nil {
+			return nil, fmt.Errorf("TOKEN_SIGNING_KEY: %w", err)
+		}
+	} else {
+		// Development fallback: anyone who knows JWT_SECRET can mint session tokens
+		log.Printf("⚠️  TOKEN_SIGNING_KEY not set, deriving the session token key from JWT_SECRET")
+		seed := sha256.Sum256([]byte("token-signing-key:" + cfg.JWTSecret))
+		tokens, _ = newMemorySigner(ed25519.NewKeyFromSeed(seed[:]))
+	}
+	if err := m.load(models.KeyPurposeTokens, tokens, records); err != nil {
+		return nil, err
+	}
+
+	if cfg.PrivateKey != "" {
+		blockchain, err := LoadSigner(cfg, cfg.PrivateKey, KeyAlgorithmSecp256k1)
+		if err != nil {
+			return nil, fmt.Errorf("PRIVATE_KEY: %w", err)
+		}
+		if blockchain.Algorithm() != KeyAlgorithmSecp256k1 {
+			return nil, fmt.Errorf("PRIVATE_KEY must be a secp256k1 key")
+		}
+		if err := m.load(models.KeyPurposeBlockchain, blockchain, records); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.SignPDFs {
+		var documents Signer
+		if cfg.PDFSigningKey != "" {
+			if documents, err = LoadSigner(cfg, cfg.PDFSigningKey, ""); err != nil {
+				return nil, fmt.Errorf("PDF_SIGNING_KEY: %w", err)
+			}
+		} else {
+			documents, _ = newMemorySigner(deriveP256Key("pdf-signing-key:" + cfg.JWTSecret))
+		}
+		if documents.Algorithm() == KeyAlgorithmEd25519 || documents.Algorithm() == KeyAlgorithmSecp256k1 {
+			return nil, fmt.Errorf("PDF_SIGNING_KEY must be a P-256 or RSA key")
+		}
+		// A certified key cannot be swapped without a new certificate from the CA
+		if cfg.PDFSigningCert != "" {
+			m.fixed[models.KeyPurposeDocuments] = "the document signing key is certified by PDF_SIGNING_CERT; install a new certificate and key instead"
+		}
+		if err := m.load(models.KeyPurposeDocuments, documents, records); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Key returns the managed key of a purpose, or nil when the deployment has none
+func (m *KeyManager) Key(purpose string) *ManagedKey {
+	return m.keys[purpose]
+}
+
+// load restores a purpose's key history. The configured key becomes active when the inventory has
+// never seen it, or when the purpose is pinned to configuration; otherwise the active key recorded
+// by the last rotation is reopened from its keystore file or token.
+func (m *KeyManager) load(purpose string, configured Signer, records []models.SigningKey) error {
+	fingerprint, err := publicKeyFingerprint(configured.Public())
+	if err != nil {
+		return err
+	}
+
+	k := &ManagedKey{purpose: purpose, records: map[string]models.SigningKey{}, public: map[string]crypto.PublicKey{}}
+	var active *models.SigningKey
+	known := false
+	for i := range records {
+		record := records[i]
+		if record.Purpose != purpose {
+			continue
+		}
+		pub, err := parsePublicKey(record.Algorithm, record.PublicKey)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", record.KeyID, err)
+		}
+		k.records[record.KeyID] = record
+		k.public[record.KeyID] = pub
+		if record.Status == models.KeyStatusActive {
+			active = &records[i]
+		}
+		known = known || record.Fingerprint == fingerprint
+	}
+	m.keys[purpose] = k
+
+	_, pinned := m.fixed[purpose]
+	switch {
+	case active == nil || !known || (pinned && active.Fingerprint != fingerprint):
+		_, err := m.activate(k, configured, "config", "")
+		return err
+	case active.Fingerprint == fingerprint:
+		k.current, k.signer = active.KeyID, configured
+		return nil
+	}
+
+	if active.Reference == "" {
+		return fmt.Errorf("active %s key %s was held in memory and cannot be reopened; configure it again", purpose, active.KeyID)
+	}
+	signer, err := LoadSigner(m.cfg, active.Reference, "")
+	if err != nil {
+		return fmt.Errorf("active %s key %s: %w", purpose, active.KeyID, err)
+	}
+	if reopened, _ := publicKeyFingerprint(signer.Public()); reopened != active.Fingerprint {
+		return fmt.Errorf("active %s key %s no longer matches %s", purpose, active.KeyID, active.Reference)
+	}
+	k.current, k.signer = active.KeyID, signer
+	return nil
+}
+
+// activate records signer as the purpose's active key and retires the previous one
+func (m *KeyManager) activate(k *ManagedKey, signer Signer, actorID, reason string) (*models.SigningKey, error) {
+	der, err := marshalPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, _ := publicKeyFingerprint(signer.Public())
+	record := models.SigningKey{
+		Purpose:        k.purpose,
+		KeyID:          fmt.Sprintf("%s-%d", k.purpose, len(k.records)+1),
+		Backend:        signer.Backend(),
+		Reference:      signer.Reference(),
+		Algorithm:      signer.Algorithm(),
+		PublicKey:      der,
+		Fingerprint:    fingerprint,
+		Status:         models.KeyStatusActive,
+		CreatedBy:      actorID,
+		CreatedAt:      time.Now(),
+		RotationReason: reason,
+	}
+	if signer.Algorithm() == KeyAlgorithmSecp256k1 {
+		record.Address, _ = ethereumAddress(signer)
+	}
+	created, err := m.store.CreateSigningKey(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s key: %w", k.purpose, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for kid, other := range k.records {
+		if other.Status == models.KeyStatusActive {
+			other.Status = models.KeyStatusRetired
+			other.RetiredAt = &created.CreatedAt
+			if _, err := m.store.UpdateSigningKey(other.ID.Hex(), other); err != nil {
+				return nil, fmt.Errorf("failed to retire key %s: %w", other.KeyID, err)
+			}
+			k.records[kid] = other
+		}
+	}
+	k.records[created.KeyID] = created
+	k.public[created.KeyID] = signer.Public()
+	k.current, k.signer = created.KeyID, signer
+	return &created, nil
+}
+
+// Inventory lists every signing key, active and retired, for administrators
+func (m *KeyManager) Inventory(actorID string) ([]models.SigningKey, error) {
+	if err := m.requireAdmin(actorID, "view the key inventory"); err != nil {
+		return nil, err
+	}
+
+	var keys []models.SigningKey
+	for _, k := range m.keys {
+		keys = append(keys, k.keys()...)
+	}
+	if m.credentials != nil {
+		for _, key := range m.credentials.Keys() {
+			keys = append(keys, credentialInventoryEntry(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Purpose != keys[j].Purpose {
+			return keys[i].Purpose < keys[j].Purpose
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Rotate replaces the active key of a purpose. Signatures and tokens made with the old key keep
+// verifying; new ones use the replacement.
+func (m *KeyManager) Rotate(purpose, actorID, reason string) (*models.SigningKey, error) {
+	if err := m.requireAdmin(actorID, "rotate signing keys"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("rotation reason is required")
+	}
+
+	if purpose == models.KeyPurposeCredentials && m.credentials != nil {
+		key, err := m.credentials.Rotate(actorID, reason)
+		if err != nil {
+			return nil, err
+		}
+		entry := credentialInventoryEntry(*key)
+		return &entry, nil
+	}
+	k, ok := m.keys[purpose]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotManaged, purpose)
+	}
+	if why, pinned := m.fixed[purpose]; pinned {
+		return nil, fmt.Errorf("%w: %s", ErrRotationUnsupported, why)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous, current := k.Current()
+	next, err := generateSigner(m.cfg, current, fmt.Sprintf("blockcred-%s-%d", purpose, len(k.keys())+1))
+	if err != nil {
+		return nil, err
+	}
+	created, err := m.activate(k, next, actorID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔑 %s signing key rotated: %s -> %s (%s)", purpose, previous, created.KeyID, created.Backend)
+	if purpose == models.KeyPurposeBlockchain {
+		log.Printf("⚠️  Blockchain transactions are now sent from %s; the account must be funded and authorized as an issuer", created.Address)
+	}
+	return created, nil
+}
+
+func (m *KeyManager) requireAdmin(actorID, action string) error {
+	actor, err := m.store.GetUserByID(actorID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if !actor.CanPerformAction("can_manage_users") {
+		return fmt.Errorf("%w: only administrators can %s", ErrPermissionDenied, action)
+	}
+	return nil
+}
+
+// credentialInventoryEntry presents an institution DID key in the inventory format
+func credentialInventoryEntry(key models.InstitutionKey) models.SigningKey {
+	backend := key.Backend
+	if backend == "" {
+		backend = models.KeyBackendMemory
+	}
+	entry := models.SigningKey{
+		ID:             key.ID,
+		Purpose:        models.KeyPurposeCredentials,
+		KeyID:          key.KeyID,
+		Backend:        backend,
+		Reference:      key.Reference,
+		Algorithm:      KeyAlgorithmEd25519,
+		Status:         key.Status,
+		CreatedBy:      key.CreatedBy,
+		CreatedAt:      key.CreatedAt,
+		RetiredAt:      key.RetiredAt,
+		RotationReason: key.RotationReason,
+	}
+	if pub, err := decodeMultikey(key.PublicKeyMultibase); err == nil {
+		entry.PublicKey, _ = marshalPublicKey(pub)
+		entry.Fingerprint, _ = publicKeyFingerprint(pub)
+	}
+	return entry
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// testBlockchainKey is a well-known development account key; it holds nothing on any real network
+const testBlockchainKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func testKeyConfig(t *testing.T) config.Config {
+	return config.Config{
+		JWTSecret:        "test-secret",
+		InstitutionDID:   "did:web:example.edu",
+		TokenTTLHours:    1,
+		ChainID:          1337,
+		KeystoreDir:      t.TempDir(),
+		KeystorePassword: "pw",
+	}
+}
+
+func TestKeystoreSignerRoundTrip(t *testing.T) {
+	generate := map[string]func() (crypto.Signer, error){
+		KeyAlgorithmEd25519: func() (crypto.Signer, error) {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			return key, err
+		},
+		KeyAlgorithmP256: func() (crypto.Signer, error) {
+			return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		},
+		KeyAlgorithmSecp256k1: func() (crypto.Signer, error) {
+			return ethcrypto.GenerateKey()
+		},
+	}
+	for algorithm, newKey := range generate {
+		algorithm, newKey := algorithm, newKey
+		t.Run(algorithm, func(t *testing.T) {
+			t.Parallel() // Each keystore operation runs scrypt
+			cfg := testKeyConfig(t)
+			key, err := newKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(cfg.KeystoreDir, "key.json")
+			if err := writeKeystore(path, key, cfg.KeystorePassword); err != nil {
+				t.Fatal(err)
+			}
+
+			signer, err := LoadSigner(cfg, "keystore:"+path, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signer.Algorithm() != algorithm || signer.Backend() != models.KeyBackendKeystore || signer.Reference() != "keystore:"+path {
+				t.Fatalf("loaded %s key from %s (%s)", signer.Algorithm(), signer.Backend(), signer.Reference())
+			}
+			if !publicKeysEqual(signer.Public(), key.Public()) {
+				t.Fatal("keystore returned a different key")
+			}
+
+			msg := []byte("blockcred keystore round trip")
+			digest := sha256.Sum256(msg)
+			switch pub := key.Public().(type) {
+			case ed25519.PublicKey:
+				sig, err := signMessage(signer, msg)
+				if err != nil || !ed25519.Verify(pub, msg, sig) {
+					t.Fatalf("signature does not verify (err %v)", err)
+				}
+			case *ecdsa.PublicKey:
+				if algorithm == KeyAlgorithmSecp256k1 {
+					// Web3 Secret Storage files carry the account, so geth and Besu tooling can use them
+					data, _ := os.ReadFile(path)
+					var file keystoreFile
+					json.Unmarshal(data, &file)
+					want := ethcrypto.PubkeyToAddress(*pub)
+					if !strings.EqualFold("0x"+file.Address, want.Hex()) {
+						t.Fatalf("keystore address %s, want %s", file.Address, want.Hex())
+					}
+					sig, err := signEthereumHash(signer, digest[:])
+					if err != nil {
+						t.Fatal(err)
+					}
+					recovered, err := ethcrypto.SigToPub(digest[:], sig)
+					if err != nil || ethcrypto.PubkeyToAddress(*recovered) != want {
+						t.Fatalf("signature recovers a different account (err %v)", err)
+					}
+					break
+				}
+				sig, err := signMessage(signer, msg)
+				if err != nil || !ecdsa.VerifyASN1(pub, digest[:], sig) {
+					t.Fatalf("signature does not verify (err %v)", err)
+				}
+			}
+
+			cfg.KeystorePassword = "wrong"
+			if _, err := LoadSigner(cfg, "keystore:"+path, ""); err == nil {
+				t.Fatal("keystore opened with the wrong password")
+			}
+		})
+	}
+}
+
+func TestRotatedTokenKeyKeepsOldTokensValid(t *testing.T) {
+	cfg := testKeyConfig(t)
+	st := store.NewMemoryStore()
+	admin := testAdmin(t, st)
+
+	keys, err := NewKeyManager(cfg, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewTokenIssuer(cfg, keys.Key(models.KeyPurposeTokens))
+	oldToken, err := tokens.Issue("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKid, _ := keys.Key(models.KeyPurposeTokens).Current()
+
+	rotated, err := keys.Rotate(models.KeyPurposeTokens, admin.ID.Hex(), "scheduled rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.KeyID == oldKid || rotated.Backend != models.KeyBackendKeystore {
+		t.Fatalf("rotation produced key %s on %s", rotated.KeyID, rotated.Backend)
+	}
+	if _, err := os.Stat(strings.TrimPrefix(rotated.Reference, "keystore:")); err != nil {
+		t.Fatalf("rotated key was not written to the keystore: %v", err)
+	}
+	newToken, err := tokens.Issue("user-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart reopens the rotated key from its keystore file and still knows the retired one
+	reloaded, err := NewKeyManager(cfg, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid, _ := reloaded.Key(models.KeyPurposeTokens).Current(); kid != rotated.KeyID {
+		t.Fatalf("active key after restart = %s, want %s", kid, rotated.KeyID)
+	}
+
+	for _, issuer := range []*TokenIssuer{tokens, NewTokenIssuer(cfg, reloaded.Key(models.KeyPurposeTokens))} {
+		for token, want := range map[string]string{oldToken: "user-1", newToken: "user-2"} {
+			if got, err := issuer.Verify(token); err != nil || got != want {
+				t.Fatalf("token for %s: got %q, %v", want, got, err)
+			}
+		}
+	}
+}
+
+func TestSendTxSignsWithManagedBlockchainKey(t *testing.T) {
+	var mu sync.Mutex
+	var raw []string
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{}
+		switch req.Method {
+		case "eth_getTransactionCount":
+			result = "0x5"
+		case "eth_gasPrice":
+			result = "0x3b9aca00"
+		case "eth_sendRawTransaction":
+			mu.Lock()
+			raw = append(raw, req.Params[0].(string))
+			mu.Unlock()
+			result = "0x" + strings.Repeat("ab", 32)
+		default:
+			t.Errorf("unexpected RPC call %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer rpc.Close()
+
+	cfg := testKeyConfig(t)
+	cfg.BlockchainRPCURL = rpc.URL
+	cfg.PrivateKey = testBlockchainKey
+	st := store.NewMemoryStore()
+	keys, err := NewKeyManager(cfg, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := NewBesuBlockchainService(cfg, keys.Key(models.KeyPurposeBlockchain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// send submits a transaction and decodes the raw transaction the node received
+	send := func() (string, *types.Transaction) {
+		t.Helper()
+		_, from, _, err := chain.sendTx(context.Background(), besuValidatorAddress, anchorBurnAddress, strings.Repeat("cd", 32), 30000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		data, err := hex.DecodeString(strings.TrimPrefix(raw[len(raw)-1], "0x"))
+		mu.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		return from, tx
+	}
+
+	configured, _ := ethcrypto.HexToECDSA(testBlockchainKey)
+	before := ethcrypto.PubkeyToAddress(configured.PublicKey)
+	from, tx := send()
+	if from != before.Hex() || senderOf(t, tx, cfg.ChainID) != before {
+		t.Fatalf("transaction sent from %s, signed by %s, want the PRIVATE_KEY account %s", from, senderOf(t, tx, cfg.ChainID).Hex(), before.Hex())
+	}
+	if tx.Nonce() != 5 || *tx.To() != common.HexToAddress(anchorBurnAddress) || tx.Gas() != 30000 {
+		t.Fatalf("transaction nonce %d, to %s, gas %d", tx.Nonce(), tx.To().Hex(), tx.Gas())
+	}
+
+	// After a rotation the service signs with the replacement without being rebuilt
+	rotated, err := keys.Rotate(models.KeyPurposeBlockchain, testAdmin(t, st).ID.Hex(), "compromised")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := common.HexToAddress(rotated.Address)
+	if after == before {
+		t.Fatal("rotation kept the same account")
+	}
+	from, tx = send()
+	if from != after.Hex() || senderOf(t, tx, cfg.ChainID) != after {
+		t.Fatalf("transaction sent from %s, signed by %s, want the rotated account %s", from, senderOf(t, tx, cfg.ChainID).Hex(), after.Hex())
+	}
+}
+
+func senderOf(t *testing.T, tx *types.Transaction, chainID int64) common.Address {
+	t.Helper()
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(chainID)), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sender
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/multibase"
+	"blockcred-backend/internal/store"
+)
+
+// ed25519MulticodecPrefix marks an Ed25519 public key in Multikey encoding
+var ed25519MulticodecPrefix = []byte{0xed, 0x01}
+
+// InstitutionKeys holds the institution's Ed25519 signing keys, addressed by verification method ID.
+// Rotated keys keep their public half so credentials signed before rotation still verify.
+type InstitutionKeys struct {
+	mu      sync.RWMutex
+	cfg     config.Config
+	store   store.Store
+	did     string
+	name    string
+	wrapKey []byte // Seals private seeds of rotated-in keys at rest; nil when VC_SIGNING_KEY is on a token
+	current string
+	signer  Signer
+	records map[string]models.InstitutionKey
+	public  map[string]ed25519.PublicKey
+}
+
+func NewInstitutionKeys(cfg config.Config, s store.Store) (*InstitutionKeys, error) {
+	var configured Signer
+	var err error
+	if cfg.VCSigningKey != "" {
+		if configured, err = LoadSigner(cfg, cfg.VCSigningKey, KeyAlgorithmEd25519); err != nil {
+			return nil, fmt.Errorf("VC_SIGNING_KEY: %w", err)
+		}
+		if configured.Algorithm() != KeyAlgorithmEd25519 {
+			return nil, fmt.Errorf("VC_SIGNING_KEY must be an Ed25519 key")
+		}
+	} else {
+		// Development fallback: stable across restarts, but anyone who knows JWT_SECRET can forge credentials
+		log.Printf("⚠️  VC_SIGNING_KEY not set, deriving the credential signing key from JWT_SECRET")
+		sum := sha256.Sum256([]byte("vc-signing-key:" + cfg.JWTSecret))
+		configured, _ = newMemorySigner(ed25519.NewKeyFromSeed(sum[:]))
+	}
+
+	k := &InstitutionKeys{
+		cfg:     cfg,
+		store:   s,
+		did:     cfg.InstitutionDID,
+		name:    cfg.InstitutionName,
+		records: map[string]models.InstitutionKey{},
+		public:  map[string]ed25519.PublicKey{},
+	}
+	// Keys on a token have no seed to derive a wrapping key from; their successors are generated
+	// on the token instead of being sealed
+	if priv, ok := configured.(*keySigner).Signer.(ed25519.PrivateKey); ok {
+		wrap := sha256.Sum256(append([]byte("institution-key-wrap:"), priv.Seed()...))
+		k.wrapKey = wrap[:]
+	}
+	if err := k.load(configured); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// load restores the key history, bootstrapping key-1 from the configured key on first start
+func (k *InstitutionKeys) load(configured Signer) error {
+	records, err := k.store.ListInstitutionKeys()
+	if err != nil {
+		return fmt.Errorf("failed to load institution keys: %w", err)
+	}
+
+	if len(records) == 0 {
+		record, err := k.store.CreateInstitutionKey(models.InstitutionKey{
+			KeyID:              k.did + "#key-1",
+			PublicKeyMultibase: encodeMultikey(configured.Public().(ed25519.PublicKey)),
+			Backend:            configured.Backend(),
+			Reference:          configured.Reference(),
+			Status:             models.KeyStatusActive,
+			CreatedBy:          "config",
+			CreatedAt:          time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record institution key: %w", err)
+		}
+		records = []models.InstitutionKey{record}
+	}
+
+	for _, record := range records {
+		pub, err := decodeMultikey(record.PublicKeyMultibase)
+		if err != nil {
+			return fmt.Errorf("institution key %s: %w", record.KeyID, err)
+		}
+		k.records[record.KeyID] = record
+		k.public[record.KeyID] = pub
+
+		if record.Status != models.KeyStatusActive {
+			continue
+		}
+		// key-1 comes from configuration; later keys carry their own sealed seed or live on the token
+		signer := configured
+		switch {
+		case len(record.SealedSeed) > 0:
+			if k.wrapKey == nil {
+				return fmt.Errorf("institution key %s is sealed, but VC_SIGNING_KEY has no seed to unseal it", record.KeyID)
+			}
+			seed, err := k.unseal(record.SealedSeed)
+			if err != nil {
+				return fmt.Errorf("failed to unseal institution key %s: %w", record.KeyID, err)
+			}
+			signer, _ = newMemorySigner(ed25519.NewKeyFromSeed(seed))
+		case record.Backend == models.KeyBackendPKCS11 && record.Reference != configured.Reference():
+			if signer, err = LoadSigner(k.cfg, record.Reference, ""); err != nil {
+				return fmt.Errorf("institution key %s: %w", record.KeyID, err)
+			}
+		}
+		if !pub.Equal(signer.Public()) {
+			return fmt.Errorf("institution key %s does not match VC_SIGNING_KEY", record.KeyID)
+		}
+		k.current, k.signer = record.KeyID, signer
+	}
+
+	if k.signer == nil {
+		return fmt.Errorf("no active institution signing key")
+	}
+	return nil
+}
+
+// DID returns the institution's decentralized identifier
+func (k *InstitutionKeys) DID() string {
+	return k.did
+}
+
+// Name returns the institution's display name
+func (k *InstitutionKeys) Name() string {
+	return k.name
+}
+
+// CurrentKeyID returns the verification method ID of the key used for new signatures
+func (k *InstitutionKeys) CurrentKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// SignWith signs msg with the given key, which must still be the current one
+func (k *InstitutionKeys) SignWith(kid string, msg []byte) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if kid != k.current {
+		return nil, fmt.Errorf("signing key %s has been rotated", kid)
+	}
+	return signMessage(k.signer, msg)
+}
+
+// PublicKey resolves a verification method ID to its public key. Retired keys only verify
+// signatures made before their retirement.
+func (k *InstitutionKeys) PublicKey(kid string, signedAt time.Time) (ed25519.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	pub, ok := k.public[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown verification method %q", kid)
+	}
+	if retired := k.records[kid].RetiredAt; retired != nil && signedAt.After(*retired) {
+		return nil, fmt.Errorf("key %s was retired on %s, before this credential was signed", kid, retired.Format(time.RFC3339))
+	}
+	return pub, nil
+}
+
+// Keys returns the key history, oldest first
+func (k *InstitutionKeys) Keys() []models.InstitutionKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]models.InstitutionKey, 0, len(k.records))
+	for _, record := range k.records {
+		keys = append(keys, record)
+	}
+	sortInstitutionKeys(keys)
+	return keys
+}
+
+// Rotate generates a new signing key and retires the current one
+func (k *InstitutionKeys) Rotate(actorID, reason string) (*models.InstitutionKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keyNumber := len(k.records) + 1
+	var signer Signer
+	var sealed []byte
+	if k.signer.Backend() == models.KeyBackendPKCS11 {
+		var err error
+		if signer, err = generateSigner(k.cfg, k.signer, fmt.Sprintf("blockcred-credentials-key-%d", keyNumber)); err != nil {
+			return nil, err
+		}
+	} else {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		if sealed, err = k.seal(priv.Seed()); err != nil {
+			return nil, err
+		}
+		signer, _ = newMemorySigner(priv)
+	}
+	pub := signer.Public().(ed25519.PublicKey)
+
+	now := time.Now()
+	created, err := k.store.CreateInstitutionKey(models.InstitutionKey{
+		KeyID:              fmt.Sprintf("%s#key-%d", k.did, keyNumber),
+		PublicKeyMultibase: encodeMultikey(pub),
+		SealedSeed:         sealed,
+		Backend:            signer.Backend(),
+		Reference:          signer.Reference(),
+		Status:             models.KeyStatusActive,
+		CreatedBy:          actorID,
+		CreatedAt:          now,
+		RotationReason:     reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save new key: %w", err)
+	}
+
+	previous := k.records[k.current]
+	previous.Status = models.KeyStatusRetired
+	previous.RetiredAt = &now
+	previous.SealedSeed = nil // Only the public half is needed from now on
+	if _, err := k.store.UpdateInstitutionKey(previous.ID.Hex(), previous); err != nil {
+		return nil, fmt.Errorf("failed to retire key %s: %w", previous.KeyID, err)
+	}
+
+	k.records[previous.KeyID] = previous
+	k.records[created.KeyID] = created
+	k.public[created.KeyID] = pub
+	k.current, k.signer = created.KeyID, signer
+
+	log.Printf("🔑 Institution signing key rotated: %s -> %s", previous.KeyID, created.KeyID)
+	return &created, nil
+}
+
+func (k *InstitutionKeys) seal(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(k.wrapKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (k *InstitutionKeys) unseal(sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(k.wrapKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed key too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encodeMultikey returns the publicKeyMultibase form of an Ed25519 public key
+func encodeMultikey(pub ed25519.PublicKey) string {
+	return multibase.Encode(append(append([]byte{}, ed25519MulticodecPrefix...), pub...))
+}
+
+func decodeMultikey(s string) (ed25519.PublicKey, error) {
+	raw, err := multibase.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != len(ed25519MulticodecPrefix)+ed25519.PublicKeySize || raw[0] != ed25519MulticodecPrefix[0] || raw[1] != ed25519MulticodecPrefix[1] {
+		return nil, fmt.Errorf("public key is not an Ed25519 multikey")
+	}
+	return ed25519.PublicKey(raw[len(ed25519MulticodecPrefix):]), nil
+}
+
+func sortInstitutionKeys(keys []models.InstitutionKey) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// keystoreFile is the Web3 Secret Storage (version 3) layout Ethereum clients use, so blockchain
+// keys can be moved to and from geth or Besu tooling. Other keys add an algorithm field and
+// encrypt their PKCS #8 encoding instead of a raw secp256k1 scalar.
+type keystoreFile struct {
+	Address   string              `json:"address,omitempty"`
+	Algorithm string              `json:"algorithm,omitempty"`
+	Crypto    keystore.CryptoJSON `json:"crypto"`
+	ID        string              `json:"id"`
+	Version   int                 `json:"version"`
+}
+
+// readKeystore decrypts a keystore file with password
+func readKeystore(path, password string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var file keystoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid keystore %s: %w", path, err)
+	}
+	if file.Version != 3 {
+		return nil, fmt.Errorf("keystore %s has unsupported version %d", path, file.Version)
+	}
+	if password == "" {
+		return nil, fmt.Errorf("KEYSTORE_PASSWORD must be set to open %s", path)
+	}
+
+	plain, err := keystore.DecryptDataV3(file.Crypto, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	if file.Algorithm == "" || file.Algorithm == KeyAlgorithmSecp256k1 {
+		key, err := ethcrypto.ToECDSA(plain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in keystore %s: %w", path, err)
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(plain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key in keystore %s: %w", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T in keystore %s", key, path)
+	}
+	return signer, nil
+}
+
+// writeKeystore encrypts key with password using the standard scrypt parameters
+func writeKeystore(path string, key crypto.Signer, password string) error {
+	algorithm, err := keyAlgorithm(key.Public())
+	if err != nil {
+		return err
+	}
+	file := keystoreFile{Version: 3}
+	var plain []byte
+	if algorithm == KeyAlgorithmSecp256k1 {
+		ecKey := key.(*ecdsa.PrivateKey)
+		plain = ethcrypto.FromECDSA(ecKey)
+		file.Address = fmt.Sprintf("%x", ethcrypto.PubkeyToAddress(ecKey.PublicKey))
+	} else {
+		if plain, err = x509.MarshalPKCS8PrivateKey(key); err != nil {
+			return fmt.Errorf("failed to encode key: %w", err)
+		}
+		file.Algorithm = algorithm
+	}
+
+	if file.Crypto, err = keystore.EncryptDataV3(plain, []byte(password), keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("failed to generate keystore id: %w", err)
+	}
+	id[6], id[8] = id[6]&0x0f|0x40, id[8]&0x3f|0x80 // Random (version 4) UUID
+	file.ID = fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ManifestRecord is one manifest row keyed by normalized column header
+type ManifestRecord map[string]string
+
+// ManifestRow is a data row with its row number in the source file, as a spreadsheet shows it
+type ManifestRow struct {
+	Number int
+	Record ManifestRecord
+}
+
+// sheetRow is a row of cells read from a manifest, numbered from 1 like spreadsheet rows
+type sheetRow struct {
+	number int
+	cells  []string
+}
+
+// ParseManifest reads a CSV or XLSX manifest; the first row holds the column headers.
+// Blank rows are skipped, so rows keep their source numbers for error reports.
+func ParseManifest(fileName string, data []byte) ([]ManifestRow, error) {
+	var rows []sheetRow
+	var err error
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		rows, err = readCSV(data)
+	case ".xlsx":
+		rows, err = readXLSX(data)
+	default:
+		return nil, fmt.Errorf("unsupported manifest format %q: use .csv or .xlsx", path.Ext(fileName))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("manifest has no data rows")
+	}
+
+	headers := make([]string, len(rows[0].cells))
+	for i, h := range rows[0].cells {
+		headers[i] = normalizeHeader(h)
+	}
+
+	records := make([]ManifestRow, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if isBlankRow(row.cells) {
+			continue
+		}
+		record := ManifestRecord{}
+		for i, value := range row.cells {
+			if i < len(headers) && headers[i] != "" {
+				record[headers[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, ManifestRow{Number: row.number, Record: record})
+	}
+	return records, nil
+}
+
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(h)
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSV numbers records by the line they start on, since the reader skips empty lines that a
+// spreadsheet would still show as rows
+func readCSV(data []byte) ([]sheetRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var rows []sheetRow
+	for {
+		cells, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV manifest: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, sheetRow{number: line, cells: cells})
+	}
+}
+
+// Minimal SpreadsheetML structures: only what is needed to read cell text from the first sheet
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"` // Row number; rows without cells are left out of the sheet
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([]sheetRow, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX manifest: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+	strs := make([]string, len(shared.Items))
+	for i, item := range shared.Items {
+		if len(item.Runs) == 0 {
+			strs[i] = item.Text
+			continue
+		}
+		var sb strings.Builder
+		for _, run := range item.Runs {
+			sb.WriteString(run.Text)
+		}
+		strs[i] = sb.String()
+	}
+
+	sheetFile, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, fmt.Errorf("XLSX manifest has no worksheet")
+	}
+	var sheet xlsxSheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]sheetRow, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		number := r.Ref
+		if number == 0 && len(rows) > 0 {
+			number = rows[len(rows)-1].number + 1
+		} else if number == 0 {
+			number = 1
+		}
+		row := []string{}
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(c.Value)
+				if err == nil && idx >= 0 && idx < len(strs) {
+					row[col] = strs[idx]
+				}
+			case "inlineStr":
+				row[col] = c.Inline
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, sheetRow{number: number, cells: row})
+	}
+	return rows, nil
+}
+
+// firstSheetPath resolves the first sheet through the workbook relationships
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var wb xlsxWorkbook
+	var rels xlsxRelationships
+	wbFile, ok := files["xl/workbook.xml"]
+	relsFile, relsOK := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !relsOK || decodeZipXML(wbFile, &wb) != nil || decodeZipXML(relsFile, &rels) != nil || len(wb.Sheets) == 0 {
+		return fallback
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].RID {
+			target := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(target, "xl/") {
+				target = "xl/" + target
+			}
+			return target
+		}
+	}
+	return fallback
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero-based column index
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+)
+
+// Failures of external dependencies, matched with errors.Is so handlers can answer 503, 504 or 502
+var (
+	ErrUpstreamUnavailable = errors.New("upstream service unavailable")
+	ErrUpstreamTimeout     = errors.New("upstream service timed out")
+	ErrUpstreamFailed      = errors.New("upstream service failed")
+)
+
+// UpstreamError is a failed call to an external dependency
+type UpstreamError struct {
+	Dependency string
+	Kind       error // One of ErrUpstreamUnavailable, ErrUpstreamTimeout or ErrUpstreamFailed
+	Status     int   // HTTP status returned by the dependency, if it answered
+	Retryable  bool
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UpstreamError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// upstreamTransportError classifies an error raised before a dependency answered
+func upstreamTransportError(dependency string, err error) *UpstreamError {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &UpstreamError{Dependency: dependency, Kind: ErrUpstreamTimeout, Retryable: true, Err: err}
+	case errors.Is(err, context.Canceled):
+		return &UpstreamError{Dependency: dependency, Kind: ErrUpstreamFailed, Err: err}
+	case neverSent(err):
+		return &UpstreamError{Dependency: dependency, Kind: ErrUpstreamUnavailable, Retryable: true, Err: err}
+	}
+	return &UpstreamError{Dependency: dependency, Kind: ErrUpstreamFailed, Retryable: true, Err: err}
+}
+
+// upstreamStatusError classifies an unsuccessful HTTP response. Client errors other than
+// rate limiting are the caller's fault, so they are neither retried nor held against the dependency.
+func upstreamStatusError(dependency string, status int, err error) *UpstreamError {
+	e := &UpstreamError{Dependency: dependency, Kind: ErrUpstreamFailed, Status: status, Err: err}
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		e.Kind, e.Retryable = ErrUpstreamUnavailable, true
+	case status == http.StatusGatewayTimeout:
+		e.Kind, e.Retryable = ErrUpstreamTimeout, true
+	case status >= 500:
+		e.Retryable = true
+	}
+	return e
+}
+
+// neverSent reports whether a request failed before any of it reached the dependency,
+// which makes even a non-idempotent call safe to repeat
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Dependency guards the calls to one external service: each attempt gets its own deadline,
+// retryable failures are repeated with exponential backoff and jitter, and a circuit breaker
+// fails calls fast once the service keeps failing.
+type Dependency struct {
+	name        string
+	maxAttempts int
+	timeout     time.Duration
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	threshold   int
+	cooldown    time.Duration
+
+	mu        sync.Mutex
+	failures  int // Consecutive failures
+	openedAt  *time.Time
+	probing   bool // A half-open trial call is in flight
+	lastError string
+}
+
+var (
+	dependenciesMu sync.Mutex
+	dependencies   = make(map[string]*Dependency)
+)
+
+// dependencyFor returns the shared guard for a named dependency, so every client of the same
+// service trips the same breaker
+func dependencyFor(cfg config.Config, name string) *Dependency {
+	dependenciesMu.Lock()
+	defer dependenciesMu.Unlock()
+
+	if d, ok := dependencies[name]; ok {
+		return d
+	}
+	d := &Dependency{
+		name:        name,
+		maxAttempts: int(max(cfg.CallAttempts, 1)),
+		timeout:     time.Duration(max(cfg.CallTimeoutSecs, 1)) * time.Second,
+		baseDelay:   200 * time.Millisecond,
+		maxDelay:    5 * time.Second,
+		threshold:   int(max(cfg.BreakerThreshold, 1)),
+		cooldown:    time.Duration(max(cfg.BreakerResetSecs, 1)) * time.Second,
+	}
+	dependencies[name] = d
+	return d
+}
+
+// Do runs call until it succeeds, fails for good or runs out of attempts. Calls that are not
+// idempotent are only repeated when the previous attempt never reached the dependency.
+func (d *Dependency) Do(ctx context.Context, idempotent bool, call func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = d.allow(); err != nil {
+			return err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		err = call(attemptCtx)
+		cancel()
+		if err == nil {
+			d.record(nil)
+			return nil
+		}
+		var upstream *UpstreamError
+		if !errors.As(err, &upstream) {
+			// Not a dependency failure: the service answered, the call itself was refused
+			d.record(nil)
+			return err
+		}
+		d.record(upstream)
+
+		if !upstream.Retryable || (!idempotent && !neverSent(upstream.Err)) || attempt >= d.maxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return upstreamTransportError(d.name, ctx.Err())
+		case <-time.After(d.backoff(attempt)):
+		}
+	}
+}
+
+// backoff is the delay before the next attempt: exponential, capped, with full jitter
+func (d *Dependency) backoff(attempt int) time.Duration {
+	delay := d.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > d.maxDelay {
+		delay = d.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// allow rejects calls while the breaker is open. After the cooldown a single trial call goes through.
+func (d *Dependency) allow() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.openedAt == nil {
+		return nil
+	}
+	retryAt := d.openedAt.Add(d.cooldown)
+	if time.Now().Before(retryAt) || d.probing {
+		return &UpstreamError{
+			Dependency: d.name,
+			Kind:       ErrUpstreamUnavailable,
+			Err:        fmt.Errorf("%s is unavailable after repeated failures, next attempt after %s", d.name, retryAt.Format(time.RFC3339)),
+		}
+	}
+	d.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a call. Client errors do not count against the dependency.
+func (d *Dependency) record(err *UpstreamError) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.probing = false
+	if err == nil || (err.Status >= 400 && err.Status < 500 && !err.Retryable) {
+		d.failures, d.openedAt = 0, nil
+		return
+	}
+	d.failures++
+	d.lastError = err.Error()
+	if d.failures >= d.threshold || d.openedAt != nil {
+		now := time.Now()
+		if d.openedAt == nil {
+			fmt.Printf("⚠️  Warning: circuit for %s opened after %d consecutive failures: %v\n", d.name, d.failures, err)
+		}
+		d.openedAt = &now
+	}
+}
+
+// Status reports the breaker state
+func (d *Dependency) Status() models.DependencyStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := models.DependencyStatus{
+		Name:                d.name,
+		State:               models.CircuitClosed,
+		ConsecutiveFailures: d.failures,
+		LastError:           d.lastError,
+	}
+	if d.openedAt != nil {
+		retryAt := d.openedAt.Add(d.cooldown)
+		status.OpenedAt, status.RetryAt = d.openedAt, &retryAt
+		status.State = models.CircuitOpen
+		if !time.Now().Before(retryAt) {
+			status.State = models.CircuitHalfOpen
+		}
+	}
+	return status
+}
+
+// DependencyStatuses reports the breaker of every dependency called so far
+func DependencyStatuses() []models.DependencyStatus {
+	dependenciesMu.Lock()
+	defer dependenciesMu.Unlock()
+
+	statuses := make([]models.DependencyStatus, 0, len(dependencies))
+	for _, d := range dependencies {
+		statuses = append(statuses, d.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
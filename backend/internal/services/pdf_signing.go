@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/cms"
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pdf"
+)
+
+// PAdES baseline levels applied to certificate PDFs
+const (
+	PAdESBaselineB = "PAdES-B-B"
+	PAdESBaselineT = "PAdES-B-T" // B-B plus an RFC 3161 timestamp over the signature
+)
+
+// PDFSigner applies the institution's PAdES signature to certificate PDFs, so readers such as
+// Acrobat show who issued the document and that it has not been altered since
+type PDFSigner struct {
+	mu         sync.Mutex
+	keys       *ManagedKey
+	certified  *cms.Signer                  // Built from PDF_SIGNING_CERT; nil in self-signed mode
+	selfSigned map[string]*x509.Certificate // Self-signed certificates by key ID, issued as keys rotate
+	name       string
+	tsa        TimestampAuthority // Nil for PAdES-B-B
+}
+
+// NewPDFSigner loads the document signing certificate for the documents key. It returns nil when
+// SIGN_PDFS is off.
+func NewPDFSigner(cfg config.Config, keys *ManagedKey) (*PDFSigner, error) {
+	if !cfg.SignPDFs {
+		return nil, nil
+	}
+
+	p := &PDFSigner{keys: keys, selfSigned: map[string]*x509.Certificate{}, name: cfg.InstitutionName}
+	switch {
+	case cfg.PDFSigningCert != "" && cfg.PDFSigningKey != "":
+		_, key := keys.Current()
+		signer, err := loadSigningCertificate(cfg.PDFSigningCert, key)
+		if err != nil {
+			return nil, err
+		}
+		p.certified = &signer
+	case cfg.PDFSigningCert != "" || cfg.PDFSigningKey != "":
+		return nil, fmt.Errorf("PDF_SIGNING_CERT and PDF_SIGNING_KEY must be set together")
+	default:
+		// Development fallback: readers will show the signature as untrusted, and unless the key
+		// has been rotated anyone who knows JWT_SECRET can sign as the institution
+		log.Printf("⚠️  PDF_SIGNING_CERT not set, signing PDFs with a self-signed certificate")
+	}
+
+	tsa, err := NewTimestampAuthority(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.tsa = tsa
+	return p, nil
+}
+
+// currentSigner returns the certificate and key for new signatures, issuing a self-signed
+// certificate the first time a rotated key is used
+func (p *PDFSigner) currentSigner() (cms.Signer, error) {
+	if p.certified != nil {
+		return *p.certified, nil
+	}
+	kid, key := p.keys.Current()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cert, ok := p.selfSigned[kid]
+	if !ok {
+		var err error
+		if cert, err = selfSignedCertificate(key, p.name); err != nil {
+			return cms.Signer{}, err
+		}
+		p.selfSigned[kid] = cert
+	}
+	return cms.Signer{Key: key, Certificate: cert}, nil
+}
+
+// Sign appends a visible institutional signature to a PDF and describes it for the certificate record
+func (p *PDFSigner) Sign(data []byte, reason string) ([]byte, *models.DocumentSignature, error) {
+	signer, err := p.currentSigner()
+	if err != nil {
+		return nil, nil, err
+	}
+	fingerprint := sha256.Sum256(signer.Certificate.Raw)
+	record := &models.DocumentSignature{
+		Level:       PAdESBaselineB,
+		Signer:      signer.Certificate.Subject.String(),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+		SignedAt:    time.Now().UTC(),
+	}
+
+	signed, err := pdf.Sign(data, pdf.Signature{Name: p.name, Reason: reason, SignedAt: record.SignedAt}, func(content []byte) ([]byte, error) {
+		return cms.Sign(cms.SignedData{
+			ContentType: cms.OIDData,
+			Content:     content,
+			Detached:    true,
+			Unsigned: func(signature []byte) ([]cms.Attribute, error) {
+				if p.tsa == nil {
+					return nil, nil
+				}
+				// B-T timestamps the signature value, proving the signature existed at that time
+				digest := sha256.Sum256(signature)
+				token, at, err := p.tsa.Timestamp(digest[:])
+				if err != nil {
+					return nil, fmt.Errorf("failed to timestamp signature: %w", err)
+				}
+				record.Level, record.TimestampedAt, record.TSA = PAdESBaselineT, &at, p.tsa.Name()
+				return []cms.Attribute{{Type: cms.OIDTimeStampToken, Value: token}}, nil
+			},
+		}, signer)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return signed, record, nil
+}
+
+// loadSigningCertificate reads a PEM certificate, followed by any intermediates, for key
+func loadSigningCertificate(certPath string, key crypto.Signer) (cms.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return cms.Signer{}, fmt.Errorf("failed to read PDF signing certificate: %w", err)
+	}
+	var chain []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return cms.Signer{}, fmt.Errorf("invalid PDF signing certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return cms.Signer{}, fmt.Errorf("%s holds no PEM certificate", certPath)
+	}
+
+	if !publicKeysEqual(key.Public(), chain[0].PublicKey) {
+		return cms.Signer{}, fmt.Errorf("PDF_SIGNING_KEY does not match the first certificate in PDF_SIGNING_CERT")
+	}
+	return cms.Signer{Key: key, Certificate: chain[0], Chain: chain[1:]}, nil
+}
+
+// parsePrivateKeyPEM accepts PKCS #8, SEC 1 EC and PKCS #1 RSA keys
+func parsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
+
+// deriveP256Key turns a secret into a stable P-256 key for development fallbacks
+func deriveP256Key(secret string) *ecdsa.PrivateKey {
+	curve := elliptic.P256()
+	sum := sha256.Sum256([]byte(secret))
+	d := new(big.Int).SetBytes(sum[:])
+	d.Mod(d, new(big.Int).Sub(curve.Params().N, big.NewInt(1)))
+	d.Add(d, big.NewInt(1))
+
+	key := &ecdsa.PrivateKey{D: d}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d.FillBytes(make([]byte, 32)))
+	return key
+}
+
+// selfSignedCertificate issues a ten-year signing certificate for key
+func selfSignedCertificate(key crypto.Signer, commonName string, extensions ...pkix.Extension) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		ExtraExtensions:       extensions,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create self-signed certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// TimestampAuthority issues RFC 3161 timestamp tokens over SHA-256 digests
+type TimestampAuthority interface {
+	Name() string
+	Timestamp(digest []byte) ([]byte, time.Time, error)
+}
+
+// NewTimestampAuthority returns the authority named by TSA_URL: nil when it is empty, the built-in
+// stand-in for "local", and otherwise an RFC 3161 HTTP service
+func NewTimestampAuthority(cfg config.Config) (TimestampAuthority, error) {
+	switch strings.ToLower(cfg.TimestampURL) {
+	case "":
+		return nil, nil
+	case "local":
+		return NewLocalTimestampAuthority(cfg.InstitutionName)
+	}
+	if !strings.HasPrefix(cfg.TimestampURL, "http://") && !strings.HasPrefix(cfg.TimestampURL, "https://") {
+		return nil, fmt.Errorf("TSA_URL must be empty, local or an http(s) URL")
+	}
+	return NewHTTPTimestampAuthority(cfg), nil
+}
+
+// HTTPTimestampAuthority requests tokens from an RFC 3161 service over HTTP
+type HTTPTimestampAuthority struct {
+	url    string
+	client *http.Client
+	dep    *Dependency
+}
+
+func NewHTTPTimestampAuthority(cfg config.Config) *HTTPTimestampAuthority {
+	return &HTTPTimestampAuthority{
+		url:    cfg.TimestampURL,
+		client: &http.Client{},
+		dep:    dependencyFor(cfg, "tsa"),
+	}
+}
+
+func (t *HTTPTimestampAuthority) Name() string {
+	return t.url
+}
+
+// Timestamp sends a TimeStampReq with a fresh nonce and checks the token answers it
+func (t *HTTPTimestampAuthority) Timestamp(digest []byte) ([]byte, time.Time, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 63))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	query := cms.TimestampRequest(digest, nonce)
+
+	var reply []byte
+	err = t.dep.Do(context.Background(), true, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(query))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/timestamp-query")
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return upstreamTransportError("tsa", fmt.Errorf("failed to reach timestamp authority: %w", err))
+		}
+		defer resp.Body.Close()
+		if reply, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err != nil {
+			return upstreamTransportError("tsa", fmt.Errorf("failed to read timestamp response: %w", err))
+		}
+		if resp.StatusCode != http.StatusOK {
+			return upstreamStatusError("tsa", resp.StatusCode, fmt.Errorf("timestamp authority returned status %d", resp.StatusCode))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return cms.ParseTimestampResponse(reply, digest, nonce)
+}
+
+// localTSAPolicy identifies tokens from the built-in authority. It is a placeholder arc: tokens
+// from the stand-in are for development and carry no policy a relying party would recognise.
+var localTSAPolicy = asn1.ObjectIdentifier{1, 2, 3, 4, 1}
+
+var (
+	oidExtKeyUsage  = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidTimeStamping = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}
+)
+
+// LocalTimestampAuthority is an in-process stand-in for an RFC 3161 service. Its key and
+// self-signed certificate are created at startup, so tokens only show that the clock of this
+// server vouched for the signature time.
+type LocalTimestampAuthority struct {
+	mu     sync.Mutex
+	signer cms.Signer
+	serial *big.Int
+}
+
+func NewLocalTimestampAuthority(institution string) (*LocalTimestampAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate timestamp key: %w", err)
+	}
+	// RFC 3161 requires timeStamping to be the certificate's only extended key usage, marked critical
+	eku := pkix.Extension{Id: oidExtKeyUsage, Critical: true, Value: cms.Sequence(cms.OID(oidTimeStamping))}
+	cert, err := selfSignedCertificate(key, institution+" Local Timestamp Authority", eku)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalTimestampAuthority{
+		signer: cms.Signer{Key: key, Certificate: cert},
+		serial: big.NewInt(time.Now().UnixNano()),
+	}, nil
+}
+
+func (t *LocalTimestampAuthority) Name() string {
+	return "local"
+}
+
+func (t *LocalTimestampAuthority) Timestamp(digest []byte) ([]byte, time.Time, error) {
+	t.mu.Lock()
+	t.serial.Add(t.serial, big.NewInt(1))
+	serial := new(big.Int).Set(t.serial)
+	t.mu.Unlock()
+
+	genTime := time.Now().UTC().Truncate(time.Second)
+	info := cms.TSTInfo{Policy: localTSAPolicy, Digest: digest, SerialNumber: serial, GenTime: genTime}
+	token, err := cms.Sign(cms.SignedData{ContentType: cms.OIDTSTInfo, Content: info.Marshal()}, t.signer)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return token, genTime, nil
+}
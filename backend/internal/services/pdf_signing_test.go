@@ -0,0 +1,177 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"blockcred-backend/internal/cms"
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pdf"
+	"blockcred-backend/internal/store"
+)
+
+// The CMS structures are decoded here with encoding/asn1 rather than the cms package, so the test
+// checks the signature the way an independent reader would
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type cmsSignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo struct {
+		EContentType asn1.ObjectIdentifier
+		EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+	}
+	Certificates asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos  asn1.RawValue
+}
+
+type cmsSignerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    asn1.RawValue
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm asn1.RawValue
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type cmsAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+var byteRangePattern = regexp.MustCompile(`/ByteRange \[(\d+) (\d+) (\d+) (\d+)\]`)
+
+func TestPDFSignatureVerifiesWithX509(t *testing.T) {
+	cfg := config.Config{
+		JWTSecret:       "test-secret",
+		InstitutionName: "Test Institution",
+		SignPDFs:        true,
+		TimestampURL:    "local",
+	}
+	keys, err := NewKeyManager(cfg, store.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewPDFSigner(cfg, keys.Key(models.KeyPurposeDocuments))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixture, err := os.ReadFile("testdata/fixture.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, record, err := signer.Sign(fixture, "Certificate issued")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(signed, fixture) {
+		t.Fatal("signing rewrote the original revision")
+	}
+	if record.Level != PAdESBaselineT || record.TSA != "local" {
+		t.Fatalf("signature level %s from TSA %q, want %s from local", record.Level, record.TSA, PAdESBaselineT)
+	}
+	if _, err := pdf.Open(signed); err != nil {
+		t.Fatalf("signed PDF does not parse: %v", err)
+	}
+
+	// The byte range covers the whole file except the hex /Contents string
+	matches := byteRangePattern.FindAllSubmatch(signed, -1)
+	if len(matches) != 1 {
+		t.Fatalf("found %d /ByteRange entries, want 1", len(matches))
+	}
+	var r [4]int
+	for i := range r {
+		r[i], _ = strconv.Atoi(string(matches[0][i+1]))
+	}
+	if r[0] != 0 || r[2]+r[3] != len(signed) || signed[r[1]] != '<' || signed[r[2]-1] != '>' {
+		t.Fatalf("byte range %v does not frame /Contents in a %d-byte file", r, len(signed))
+	}
+	covered := append(append([]byte{}, signed[r[0]:r[0]+r[1]]...), signed[r[2]:r[2]+r[3]]...)
+	der, err := hex.DecodeString(string(signed[r[1]+1 : r[2]-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ci cmsContentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil || !ci.ContentType.Equal(cms.OIDSignedData) {
+		t.Fatalf("/Contents is not a SignedData ContentInfo: %v", err)
+	}
+	var sd cmsSignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatal(err)
+	}
+	if len(sd.EncapContentInfo.EContent.Bytes) != 0 {
+		t.Fatal("PAdES signatures must be detached")
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil || len(certs) == 0 {
+		t.Fatalf("no signer certificate embedded: %v", err)
+	}
+	fingerprint := sha256.Sum256(certs[0].Raw)
+	if hex.EncodeToString(fingerprint[:]) != record.Fingerprint {
+		t.Fatal("embedded certificate does not match the recorded fingerprint")
+	}
+
+	var si cmsSignerInfo
+	if _, err := asn1.Unmarshal(sd.SignerInfos.Bytes, &si); err != nil {
+		t.Fatal(err)
+	}
+	// The signature is computed over the signed attributes encoded as a SET rather than [0]
+	signedAttrs := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+	if err := certs[0].CheckSignature(x509.ECDSAWithSHA256, signedAttrs, si.Signature); err != nil {
+		t.Fatalf("signature does not verify against the signer certificate: %v", err)
+	}
+
+	digest := sha256.Sum256(covered)
+	messageDigest := attributeValue(t, si.SignedAttrs.Bytes, cms.OIDMessageDigest)
+	var got []byte
+	if _, err := asn1.Unmarshal(messageDigest, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, digest[:]) {
+		t.Fatal("messageDigest does not match the bytes in /ByteRange")
+	}
+
+	// B-T: the timestamp token covers the signature value
+	info, err := cms.ParseTimestampToken(attributeValue(t, si.UnsignedAttrs.Bytes, cms.OIDTimeStampToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatureDigest := sha256.Sum256(si.Signature)
+	if !bytes.Equal(info.Digest, signatureDigest[:]) {
+		t.Fatal("timestamp token does not cover the signature value")
+	}
+	if !info.GenTime.Equal(*record.TimestampedAt) {
+		t.Fatalf("token time %s, recorded %s", info.GenTime, record.TimestampedAt)
+	}
+}
+
+// attributeValue returns the DER of the single value of the attribute of type oid in a SET OF Attribute
+func attributeValue(t *testing.T, attrs []byte, oid asn1.ObjectIdentifier) []byte {
+	t.Helper()
+	for rest := attrs; len(rest) > 0; {
+		var attr cmsAttribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			t.Fatal(err)
+		}
+		if attr.Type.Equal(oid) {
+			return attr.Values.Bytes
+		}
+	}
+	t.Fatalf("attribute %s not found", oid)
+	return nil
+}
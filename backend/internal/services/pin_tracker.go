@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// PinTracker keeps every certificate file on the primary content store and each replica named in
+// REPLICA_STORES. It records where each CID is held, and its sweeps re-pin content a provider has
+// lost from a replica that still has it, so a lapsed pinning account does not take files down with it.
+type PinTracker struct {
+	store     store.Store
+	providers []ContentStore // The primary store comes first
+	sweeping  sync.Mutex
+
+	mu        sync.RWMutex
+	lastSweep *time.Time
+}
+
+func NewPinTracker(cfg config.Config, s store.Store, primary ContentStore) (*PinTracker, error) {
+	providers := []ContentStore{primary}
+	seen := map[string]bool{primary.Name(): true}
+	for _, name := range strings.Split(cfg.ReplicaStores, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		replicaCfg := cfg
+		replicaCfg.ContentStore = name
+		replica, err := NewContentStore(replicaCfg)
+		if err != nil {
+			return nil, fmt.Errorf("replica store %q: %w", name, err)
+		}
+		if seen[replica.Name()] {
+			log.Printf("⚠️  Replica store %s is already in use, skipping it", replica.Name())
+			continue
+		}
+		seen[replica.Name()] = true
+		providers = append(providers, replica)
+	}
+	return &PinTracker{store: s, providers: providers}, nil
+}
+
+// Replicate records a freshly stored file on the primary and copies it to every replica
+func (p *PinTracker) Replicate(certID, cid string, src ContentSource) {
+	now := time.Now()
+	p.save(models.PinRecord{CID: cid, CertID: certID, Provider: p.providers[0].Name(), Status: models.PinStatusPinned,
+		Size: src.Size, LastCheckedAt: now, LastPinnedAt: &now})
+
+	for _, replica := range p.providers[1:] {
+		rec := models.PinRecord{CID: cid, CertID: certID, Provider: replica.Name(), LastCheckedAt: now}
+		if err := p.put(replica, cid, certID, src); err != nil {
+			rec.Status, rec.LastError = models.PinStatusError, err.Error()
+		} else {
+			rec.Status, rec.Size, rec.LastPinnedAt = models.PinStatusPinned, src.Size, &now
+		}
+		p.save(rec)
+	}
+}
+
+// FetchReplica reads a file from the first replica that returns the content its CID names
+func (p *PinTracker) FetchReplica(ctx context.Context, cid string) ([]byte, error) {
+	for _, replica := range p.providers[1:] {
+		data, err := replica.Get(ctx, cid)
+		if err != nil {
+			continue
+		}
+		if isCIDv1(cid) && ComputeCID(data) != cid {
+			continue
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("no replica holds %s", cid)
+}
+
+// Sweep checks every tracked file on every provider and repairs missing pins. Overlapping sweeps are skipped.
+func (p *PinTracker) Sweep() {
+	if !p.sweeping.TryLock() {
+		return
+	}
+	defer p.sweeping.Unlock()
+
+	certs, err := p.store.ListCertificates()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: pin sweep could not list certificates: %v\n", err)
+		return
+	}
+
+	checked, repaired, unavailable := 0, 0, 0
+	seen := make(map[string]bool)
+	for _, cert := range certs {
+		if cert.IPFSCID == "" || seen[cert.IPFSCID] {
+			continue
+		}
+		seen[cert.IPFSCID] = true
+		checked++
+
+		fixed, available := p.check(cert.CertID, cert.IPFSCID)
+		repaired += fixed
+		if !available {
+			unavailable++
+		}
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	p.lastSweep = &now
+	p.mu.Unlock()
+	log.Printf("📌 Pin sweep: %d CIDs on %d providers checked, %d pins repaired, %d CIDs unavailable", checked, len(p.providers), repaired, unavailable)
+}
+
+// RequestSweep starts a sweep in the background on behalf of an administrator
+func (p *PinTracker) RequestSweep(user models.User) error {
+	if !user.CanPerformAction("can_manage_users") {
+		return fmt.Errorf("%w: only administrators can run pin checks", ErrPermissionDenied)
+	}
+	go p.Sweep()
+	return nil
+}
+
+// check refreshes the records of one CID, re-pinning it wherever it is missing. It returns the number
+// of providers repaired and whether any provider holds the content afterwards.
+func (p *PinTracker) check(certID, cid string) (int, bool) {
+	previous := make(map[string]models.PinRecord)
+	if records, err := p.store.ListPinRecordsByCID(cid); err == nil {
+		for _, rec := range records {
+			previous[rec.Provider] = rec
+		}
+	}
+
+	now := time.Now()
+	records := make([]models.PinRecord, len(p.providers))
+	var source ContentStore
+	for i, provider := range p.providers {
+		rec := previous[provider.Name()]
+		rec.CID, rec.CertID, rec.Provider, rec.LastCheckedAt, rec.LastError = cid, certID, provider.Name(), now, ""
+
+		stat, err := provider.Stat(cid)
+		switch {
+		case err != nil:
+			rec.Status, rec.LastError = models.PinStatusError, err.Error()
+		case !stat.Pinned:
+			rec.Status = models.PinStatusMissing
+		default:
+			rec.Status, rec.Size = models.PinStatusPinned, stat.Size
+			if source == nil {
+				source = provider
+			}
+		}
+		records[i] = rec
+	}
+
+	repaired := 0
+	if source != nil {
+		var src *ContentSource
+		for i, provider := range p.providers {
+			if records[i].Status == models.PinStatusPinned {
+				continue
+			}
+			if src == nil {
+				data, err := source.Get(context.Background(), cid)
+				if err != nil {
+					records[i].LastError = fmt.Sprintf("could not read from %s: %v", source.Name(), err)
+					break
+				}
+				fetched := bytesSource(data)
+				src = &fetched
+			}
+			if err := p.put(provider, cid, certID, *src); err != nil {
+				records[i].Status, records[i].LastError = models.PinStatusError, err.Error()
+				continue
+			}
+			records[i].Status, records[i].Size, records[i].LastError = models.PinStatusPinned, src.Size, ""
+			records[i].LastPinnedAt, records[i].LastRepairedAt = &now, &now
+			repaired++
+		}
+	}
+
+	for _, rec := range records {
+		p.save(rec)
+	}
+	return repaired, source != nil
+}
+
+// put stores content on a provider, insisting that it keeps the identifier already on record
+func (p *PinTracker) put(provider ContentStore, cid, certID string, src ContentSource) error {
+	if isCIDv1(cid) {
+		if err := checkCID("replica source", cid, src.CID); err != nil {
+			return err
+		}
+	}
+	stored, err := putContent(context.Background(), provider, src, certID, map[string]interface{}{"cert_id": certID})
+	if err != nil {
+		return err
+	}
+	if stored != cid {
+		return fmt.Errorf("%s stored the file as %s instead of %s", provider.Name(), stored, cid)
+	}
+	return nil
+}
+
+func (p *PinTracker) save(rec models.PinRecord) {
+	if _, err := p.store.UpsertPinRecord(rec); err != nil {
+		fmt.Printf("⚠️  Warning: failed to record pin of %s on %s: %v\n", rec.CID, rec.Provider, err)
+	}
+}
+
+// Health aggregates the pin records of the configured providers
+func (p *PinTracker) Health(user models.User) (*models.PinHealth, error) {
+	if !user.CanPerformAction("can_view_all_credentials") {
+		return nil, fmt.Errorf("%w: user cannot view pin health", ErrPermissionDenied)
+	}
+	records, err := p.store.ListPinRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	health := &models.PinHealth{Providers: make([]models.ProviderPinHealth, len(p.providers))}
+	index := make(map[string]int)
+	for i, provider := range p.providers {
+		health.Providers[i].Provider = provider.Name()
+		index[provider.Name()] = i
+	}
+
+	pinnedOn := make(map[string]int)
+	for _, rec := range records {
+		i, ok := index[rec.Provider]
+		if !ok {
+			continue // Provider is no longer configured
+		}
+		if _, ok := pinnedOn[rec.CID]; !ok {
+			pinnedOn[rec.CID] = 0
+		}
+		provider := &health.Providers[i]
+		switch rec.Status {
+		case models.PinStatusPinned:
+			provider.Pinned++
+			pinnedOn[rec.CID]++
+		case models.PinStatusMissing:
+			provider.Missing++
+		default:
+			provider.Errors++
+		}
+		if provider.LastCheckedAt == nil || rec.LastCheckedAt.After(*provider.LastCheckedAt) {
+			checkedAt := rec.LastCheckedAt
+			provider.LastCheckedAt = &checkedAt
+		}
+	}
+
+	health.TrackedCIDs = len(pinnedOn)
+	for _, pinned := range pinnedOn {
+		switch {
+		case pinned == 0:
+			health.Unavailable++
+		case pinned < len(p.providers):
+			health.Degraded++
+		default:
+			health.FullyReplicated++
+		}
+	}
+
+	p.mu.RLock()
+	health.LastSweepAt = p.lastSweep
+	p.mu.RUnlock()
+	return health, nil
+}
+
+// PinStatus lists where a certificate's file is held, to the users who may see the certificate
+func (c *CertificateService) PinStatus(certID string, user models.User) (*models.CertificatePinStatus, error) {
+	cert, err := c.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, ErrCertificateNotFound
+	}
+	if !c.canAccessCertificate(user, cert) {
+		return nil, fmt.Errorf("%w: user cannot view this certificate", ErrPermissionDenied)
+	}
+
+	records, err := c.store.ListPinRecordsByCID(cert.IPFSCID)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.CertificatePinStatus{CertID: cert.CertID, CID: cert.IPFSCID, Replicas: []models.PinRecord{}}
+	for _, rec := range records {
+		status.Replicas = append(status.Replicas, rec)
+		if rec.Status == models.PinStatusPinned {
+			status.Available = true
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"blockcred-backend/internal/config"
+)
+
+// ScanResult is a malware scanner's verdict on one document
+type ScanResult struct {
+	Clean  bool
+	Threat string // Signature name reported for infected documents
+}
+
+// DocumentScanner checks documents for malware before they are issued
+type DocumentScanner interface {
+	Name() string
+	Scan(src ContentSource) (*ScanResult, error)
+}
+
+// NewDocumentScanner returns the scanner named by DOCUMENT_SCANNER
+func NewDocumentScanner(cfg config.Config) (DocumentScanner, error) {
+	switch strings.ToLower(cfg.DocumentScanner) {
+	case "", "none":
+		return NoopScanner{}, nil
+	case "clamav", "clamd":
+		return NewClamAVScanner(cfg), nil
+	}
+	return nil, fmt.Errorf("unknown document scanner %q (expected none or clamav)", cfg.DocumentScanner)
+}
+
+// NoopScanner passes every document. It stands in for a real scanner in development and tests.
+type NoopScanner struct{}
+
+func (NoopScanner) Name() string {
+	return "none"
+}
+
+func (NoopScanner) Scan(src ContentSource) (*ScanResult, error) {
+	return &ScanResult{Clean: true}, nil
+}
+
+// clamChunkSize is the size of each INSTREAM chunk sent to clamd
+const clamChunkSize = 64 << 10
+
+// ClamAVScanner streams documents to a clamd daemon with the INSTREAM command
+type ClamAVScanner struct {
+	address string
+	dep     *Dependency
+}
+
+func NewClamAVScanner(cfg config.Config) *ClamAVScanner {
+	return &ClamAVScanner{
+		address: cfg.ClamAVAddress,
+		dep:     dependencyFor(cfg, "clamav"),
+	}
+}
+
+func (c *ClamAVScanner) Name() string {
+	return "clamav"
+}
+
+// Scan sends the document as length-prefixed chunks and parses clamd's one-line verdict
+func (c *ClamAVScanner) Scan(src ContentSource) (*ScanResult, error) {
+	var reply string
+	err := c.dep.Do(context.Background(), true, func(ctx context.Context) error {
+		var err error
+		reply, err = c.instream(ctx, src)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case verdict == "OK":
+		return &ScanResult{Clean: true}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &ScanResult{Threat: strings.TrimSuffix(verdict, " FOUND")}, nil
+	}
+	// clamd refused the scan itself, e.g. a document over its StreamMaxLength
+	return nil, fmt.Errorf("ClamAV could not scan the document: %s", verdict)
+}
+
+func (c *ClamAVScanner) instream(ctx context.Context, src ContentSource) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", upstreamTransportError("clamav", fmt.Errorf("failed to reach ClamAV: %w", err))
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	r, err := src.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open document: %w", err)
+	}
+	defer r.Close()
+
+	w := bufio.NewWriter(conn)
+	w.WriteString("zINSTREAM\x00")
+	buf := make([]byte, clamChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			binary.Write(w, binary.BigEndian, uint32(n))
+			w.Write(buf[:n])
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return "", fmt.Errorf("failed to read document: %w", readErr)
+		}
+	}
+	binary.Write(w, binary.BigEndian, uint32(0))
+	sendErr := w.Flush()
+
+	// clamd answers before closing when it rejects a stream part way, so a failed send still has a reply
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if reply == "" {
+		if sendErr != nil {
+			return "", upstreamTransportError("clamav", fmt.Errorf("failed to send document to ClamAV: %w", sendErr))
+		}
+		return "", upstreamTransportError("clamav", fmt.Errorf("failed to read ClamAV reply: %w", err))
+	}
+	return strings.TrimRight(reply, "\x00"), nil
+}
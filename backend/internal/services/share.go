@@ -0,0 +1,260 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrShareLinkGone is returned when a share link has been revoked, has expired or has used up its views
+var ErrShareLinkGone = errors.New("share link unavailable")
+
+// ErrSharePassword is returned when a password-protected share link is opened without the right password
+var ErrSharePassword = errors.New("share link password required")
+
+const (
+	defaultShareLinkTTL = 30 * 24 * time.Hour
+	maxShareLinkCerts   = 50
+)
+
+// ShareService manages student-controlled verification links and their access log
+type ShareService struct {
+	store        store.Store
+	certificates *CertificateService
+	apiURL       string
+}
+
+func NewShareService(cfg config.Config, s store.Store, certificates *CertificateService) *ShareService {
+	return &ShareService{
+		store:        s,
+		certificates: certificates,
+		apiURL:       cfg.PublicAPIURL,
+	}
+}
+
+// ShareAccessor describes who is opening a share link
+type ShareAccessor struct {
+	IP        string
+	UserAgent string
+	Verifier  *models.User // Nil for anonymous verifiers
+}
+
+// CreateLink shares one or more of the student's own certificates
+func (s *ShareService) CreateLink(user models.User, req models.CreateShareLinkRequest) (*models.CreatedShareLink, error) {
+	if user.StudentID == "" {
+		return nil, fmt.Errorf("%w: only students can create share links", ErrPermissionDenied)
+	}
+
+	certIDs := uniqueStrings(req.CertIDs)
+	if len(certIDs) == 0 {
+		return nil, fmt.Errorf("cert_ids is required")
+	}
+	if len(certIDs) > maxShareLinkCerts {
+		return nil, fmt.Errorf("a share link can hold at most %d certificates", maxShareLinkCerts)
+	}
+	for _, certID := range certIDs {
+		cert, err := s.store.GetCertificateByCertID(certID)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %s not found", certID)
+		}
+		if cert.StudentID != user.StudentID {
+			return nil, fmt.Errorf("%w: certificate %s belongs to another student", ErrPermissionDenied, certID)
+		}
+	}
+	if req.MaxViews < 0 {
+		return nil, fmt.Errorf("max_views cannot be negative")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(defaultShareLinkTTL)
+	switch {
+	case req.ExpiresAt != nil:
+		if !req.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("expires_at must be in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	case req.ExpiresInHours < 0:
+		return nil, fmt.Errorf("expires_in_hours cannot be negative")
+	case req.ExpiresInHours > 0:
+		expiresAt = now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	link := models.ShareLink{
+		TokenHash: shareTokenHash(token),
+		StudentID: user.StudentID,
+		OwnerID:   user.ID.Hex(),
+		CertIDs:   certIDs,
+		Label:     req.Label,
+		ExpiresAt: &expiresAt,
+		MaxViews:  req.MaxViews,
+		CreatedAt: now,
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash share password: %w", err)
+		}
+		link.PasswordHash = string(hash)
+		link.HasPassword = true
+	}
+
+	created, err := s.store.CreateShareLink(link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save share link: %w", err)
+	}
+	return &models.CreatedShareLink{
+		Link:  created,
+		Token: token,
+		URL:   s.apiURL + "/share/" + token,
+	}, nil
+}
+
+// ListLinks returns the student's share links, newest first
+func (s *ShareService) ListLinks(user models.User) ([]models.ShareLink, error) {
+	if user.StudentID == "" {
+		return nil, fmt.Errorf("%w: only students have share links", ErrPermissionDenied)
+	}
+	return s.store.ListShareLinksByStudent(user.StudentID)
+}
+
+// RevokeLink disables a link immediately
+func (s *ShareService) RevokeLink(id string, user models.User) (*models.ShareLink, error) {
+	link, err := s.ownedLink(id, user)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt == nil {
+		now := time.Now()
+		link.RevokedAt = &now
+		if link, err = s.store.UpdateShareLink(id, link); err != nil {
+			return nil, fmt.Errorf("failed to revoke share link: %w", err)
+		}
+	}
+	return &link, nil
+}
+
+// AccessLog lists every attempt to open the link, so the student can see who checked their credentials
+func (s *ShareService) AccessLog(id string, user models.User) ([]models.ShareAccess, error) {
+	if _, err := s.ownedLink(id, user); err != nil {
+		return nil, err
+	}
+	return s.store.ListShareAccessByLink(id)
+}
+
+func (s *ShareService) ownedLink(id string, user models.User) (models.ShareLink, error) {
+	link, err := s.store.GetShareLinkByID(id)
+	if err != nil {
+		return models.ShareLink{}, err
+	}
+	if link.OwnerID != user.ID.Hex() && !user.CanPerformAction("can_manage_users") {
+		return models.ShareLink{}, fmt.Errorf("%w: share link belongs to another student", ErrPermissionDenied)
+	}
+	return link, nil
+}
+
+// Open serves the read-only verification view behind a share link and logs the access
+func (s *ShareService) Open(token, password string, accessor ShareAccessor) (*models.SharedView, error) {
+	link, err := s.store.GetShareLinkByTokenHash(shareTokenHash(token))
+	if err != nil {
+		return nil, fmt.Errorf("share link not found")
+	}
+
+	now := time.Now()
+	switch {
+	case link.RevokedAt != nil:
+		s.logAccess(link, accessor, models.ShareAccessRevoked, now)
+		return nil, fmt.Errorf("%w: link has been revoked", ErrShareLinkGone)
+	case link.ExpiresAt != nil && now.After(*link.ExpiresAt):
+		s.logAccess(link, accessor, models.ShareAccessExpired, now)
+		return nil, fmt.Errorf("%w: link expired on %s", ErrShareLinkGone, link.ExpiresAt.Format(time.RFC3339))
+	case link.MaxViews > 0 && link.Views >= link.MaxViews:
+		s.logAccess(link, accessor, models.ShareAccessExhausted, now)
+		return nil, fmt.Errorf("%w: link has reached its view limit", ErrShareLinkGone)
+	}
+
+	// Wrong passwords are logged but do not use up a view
+	if link.HasPassword && bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		s.logAccess(link, accessor, models.ShareAccessPassword, now)
+		return nil, ErrSharePassword
+	}
+
+	viewed, err := s.store.RecordShareLinkView(link.ID.Hex(), now)
+	if err != nil {
+		s.logAccess(link, accessor, models.ShareAccessExhausted, now)
+		return nil, fmt.Errorf("%w: link has reached its view limit", ErrShareLinkGone)
+	}
+	link = viewed
+	s.logAccess(link, accessor, models.ShareAccessGranted, now)
+
+	view := &models.SharedView{
+		Label:        link.Label,
+		StudentID:    link.StudentID,
+		ExpiresAt:    link.ExpiresAt,
+		Certificates: make([]models.SharedCertificateView, 0, len(link.CertIDs)),
+	}
+	if link.MaxViews > 0 {
+		remaining := link.MaxViews - link.Views
+		view.RemainingViews = &remaining
+	}
+	for _, certID := range link.CertIDs {
+		cert, err := s.store.GetCertificateByCertID(certID)
+		if err != nil {
+			continue
+		}
+		verification, err := s.certificates.VerifyCertificate(certID)
+		if err != nil {
+			return nil, err
+		}
+		view.Certificates = append(view.Certificates, models.SharedCertificateView{
+			CertID:       cert.CertID,
+			CertType:     cert.CertType,
+			Status:       cert.Status,
+			IssuedAt:     cert.IssuedAt,
+			FileHash:     cert.FileHash,
+			TxHash:       cert.TxHash,
+			BlockNumber:  cert.BlockNumber,
+			IPFSURL:      cert.IPFSURL,
+			Metadata:     cert.Metadata,
+			Verification: verification,
+		})
+	}
+	return view, nil
+}
+
+func (s *ShareService) logAccess(link models.ShareLink, accessor ShareAccessor, outcome models.ShareAccessOutcome, at time.Time) {
+	entry := models.ShareAccess{
+		LinkID:     link.ID.Hex(),
+		Outcome:    outcome,
+		IP:         accessor.IP,
+		UserAgent:  accessor.UserAgent,
+		AccessedAt: at,
+	}
+	if accessor.Verifier != nil {
+		entry.VerifierID = accessor.Verifier.ID.Hex()
+		entry.VerifierName = accessor.Verifier.Name
+	}
+	if _, err := s.store.CreateShareAccess(entry); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to record share link access: %v\n", err)
+	}
+}
+
+func shareTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
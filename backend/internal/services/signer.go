@@ -0,0 +1,346 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pkcs11"
+)
+
+// Signing key algorithms
+const (
+	KeyAlgorithmEd25519   = "Ed25519"
+	KeyAlgorithmP256      = "P-256"
+	KeyAlgorithmSecp256k1 = "secp256k1"
+	KeyAlgorithmRSA       = "RSA"
+)
+
+// Signer is a signing key together with where it lives. Keys may be held in memory, in a
+// password-encrypted keystore file or on a PKCS #11 token, in which case they never leave it.
+type Signer interface {
+	crypto.Signer
+	Algorithm() string
+	Backend() string   // models.KeyBackendMemory, KeyBackendKeystore or KeyBackendPKCS11
+	Reference() string // Keystore path or PKCS #11 URI; never includes a password or PIN
+}
+
+type keySigner struct {
+	crypto.Signer
+	algorithm string
+	backend   string
+	reference string
+	session   *pkcs11.Session // Token session of PKCS #11 keys, where rotated keys are generated
+	module    string
+}
+
+func (k *keySigner) Algorithm() string { return k.algorithm }
+func (k *keySigner) Backend() string   { return k.backend }
+func (k *keySigner) Reference() string { return k.reference }
+
+// newMemorySigner wraps a key held in process memory
+func newMemorySigner(key crypto.Signer) (Signer, error) {
+	algorithm, err := keyAlgorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &keySigner{Signer: key, algorithm: algorithm, backend: models.KeyBackendMemory}, nil
+}
+
+// LoadSigner resolves a key setting such as PRIVATE_KEY. It accepts
+//   - pkcs11:<RFC 7512 URI>, with the module and PIN from the URI or PKCS11_MODULE and PKCS11_PIN
+//   - keystore:<path> to a Web3 Secret Storage file, decrypted with KEYSTORE_PASSWORD
+//   - the path of a PEM private key
+//   - a hex key of hexAlgorithm: a secp256k1 private key or an Ed25519 seed. An empty hexAlgorithm
+//     only allows the forms above.
+func LoadSigner(cfg config.Config, spec, hexAlgorithm string) (Signer, error) {
+	switch {
+	case strings.HasPrefix(spec, "pkcs11:"):
+		uri, err := pkcs11.ParseURI(spec)
+		if err != nil {
+			return nil, err
+		}
+		return loadTokenSigner(cfg, uri)
+	case strings.HasPrefix(spec, "keystore:"):
+		path := strings.TrimPrefix(spec, "keystore:")
+		key, err := readKeystore(path, cfg.KeystorePassword)
+		if err != nil {
+			return nil, err
+		}
+		algorithm, err := keyAlgorithm(key.Public())
+		if err != nil {
+			return nil, err
+		}
+		return &keySigner{Signer: key, algorithm: algorithm, backend: models.KeyBackendKeystore, reference: spec}, nil
+	}
+
+	if info, err := os.Stat(spec); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		key, err := parsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", spec, err)
+		}
+		return newMemorySigner(key)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(spec, "0x"))
+	if err != nil || hexAlgorithm == "" {
+		return nil, fmt.Errorf("key must be a pkcs11: URI, keystore: path or PEM file")
+	}
+	switch hexAlgorithm {
+	case KeyAlgorithmSecp256k1:
+		key, err := ethcrypto.ToECDSA(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 key: %w", err)
+		}
+		return newMemorySigner(key)
+	case KeyAlgorithmEd25519:
+		if len(raw) != ed25519.SeedSize {
+			return nil, fmt.Errorf("Ed25519 seed must be %d hex-encoded bytes", ed25519.SeedSize)
+		}
+		return newMemorySigner(ed25519.NewKeyFromSeed(raw))
+	}
+	return nil, fmt.Errorf("unsupported hex key algorithm %s", hexAlgorithm)
+}
+
+// loadTokenSigner logs in to the token named by uri and finds its key
+func loadTokenSigner(cfg config.Config, uri pkcs11.URI) (Signer, error) {
+	session, module, err := openToken(cfg, uri)
+	if err != nil {
+		return nil, err
+	}
+	key, err := session.FindKey(uri.Object, uri.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", uri, err)
+	}
+	algorithm, err := keyAlgorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &keySigner{Signer: key, algorithm: algorithm, backend: models.KeyBackendPKCS11, reference: uri.String(), session: session, module: module}, nil
+}
+
+func openToken(cfg config.Config, uri pkcs11.URI) (*pkcs11.Session, string, error) {
+	module := uri.ModulePath
+	if module == "" {
+		module = cfg.PKCS11Module
+	}
+	if module == "" {
+		return nil, "", fmt.Errorf("PKCS11_MODULE must be set to use %s", uri)
+	}
+	pin := uri.PIN
+	if pin == "" {
+		pin = cfg.PKCS11PIN
+	}
+
+	m, err := pkcs11.Open(module)
+	if err != nil {
+		return nil, "", err
+	}
+	session, err := m.Login(uri.Token, pin)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, module, nil
+}
+
+// keystoreMu serializes writes to KEYSTORE_DIR so concurrent rotations pick distinct file names
+var keystoreMu sync.Mutex
+
+// generateSigner creates the successor of current for rotation. Keys on a token are replaced by
+// a new key on the same token; any other key is replaced by a keystore file in KEYSTORE_DIR.
+func generateSigner(cfg config.Config, current Signer, name string) (Signer, error) {
+	if k, ok := current.(*keySigner); ok && k.backend == models.KeyBackendPKCS11 {
+		var keyType pkcs11.KeyType
+		switch k.algorithm {
+		case KeyAlgorithmP256:
+			keyType = pkcs11.KeyP256
+		case KeyAlgorithmSecp256k1:
+			keyType = pkcs11.KeySecp256k1
+		case KeyAlgorithmEd25519:
+			keyType = pkcs11.KeyEd25519
+		default:
+			return nil, fmt.Errorf("cannot generate %s keys on PKCS #11 tokens", k.algorithm)
+		}
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, fmt.Errorf("failed to generate key id: %w", err)
+		}
+		key, err := k.session.GenerateKey(name, id, keyType)
+		if err != nil {
+			return nil, err
+		}
+		uri := pkcs11.URI{Token: k.session.Token, Object: name, ID: id}
+		if k.module != cfg.PKCS11Module {
+			uri.ModulePath = k.module
+		}
+		return &keySigner{Signer: key, algorithm: k.algorithm, backend: models.KeyBackendPKCS11, reference: uri.String(), session: k.session, module: k.module}, nil
+	}
+
+	if cfg.KeystorePassword == "" {
+		return nil, fmt.Errorf("KEYSTORE_PASSWORD must be set to rotate keys that are not on a PKCS #11 token")
+	}
+	key, err := generateKey(current.Algorithm(), current.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	keystoreMu.Lock()
+	defer keystoreMu.Unlock()
+	if err := os.MkdirAll(cfg.KeystoreDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	path := filepath.Join(cfg.KeystoreDir, name+".json")
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore file %s already exists", path)
+	}
+	if err := writeKeystore(path, key, cfg.KeystorePassword); err != nil {
+		return nil, err
+	}
+	return &keySigner{Signer: key, algorithm: current.Algorithm(), backend: models.KeyBackendKeystore, reference: "keystore:" + path}, nil
+}
+
+func generateKey(algorithm string, like crypto.PublicKey) (crypto.Signer, error) {
+	switch algorithm {
+	case KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	case KeyAlgorithmP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmSecp256k1:
+		return ethcrypto.GenerateKey()
+	case KeyAlgorithmRSA:
+		bits := 2048
+		if pub, ok := like.(*rsa.PublicKey); ok && pub.N.BitLen() > bits {
+			bits = pub.N.BitLen()
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+	return nil, fmt.Errorf("unsupported key algorithm %s", algorithm)
+}
+
+// keyAlgorithm names the algorithm of a public key
+func keyAlgorithm(pub crypto.PublicKey) (string, error) {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519, nil
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve.Params().Name {
+		case elliptic.P256().Params().Name:
+			return KeyAlgorithmP256, nil
+		case ethcrypto.S256().Params().Name:
+			return KeyAlgorithmSecp256k1, nil
+		}
+		return "", fmt.Errorf("unsupported curve %s", pub.Curve.Params().Name)
+	}
+	return "", fmt.Errorf("unsupported key type %T", pub)
+}
+
+// marshalPublicKey encodes a public key as PKIX DER, or as an uncompressed point for secp256k1,
+// which PKIX encoders in the standard library do not support
+func marshalPublicKey(pub crypto.PublicKey) ([]byte, error) {
+	if k, ok := pub.(*ecdsa.PublicKey); ok && k.Curve.Params().Name == ethcrypto.S256().Params().Name {
+		return ethcrypto.FromECDSAPub(k), nil
+	}
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+func parsePublicKey(algorithm string, der []byte) (crypto.PublicKey, error) {
+	if algorithm == KeyAlgorithmSecp256k1 {
+		return ethcrypto.UnmarshalPubkey(der)
+	}
+	return x509.ParsePKIXPublicKey(der)
+}
+
+// publicKeyFingerprint is the hex SHA-256 of the encoded public key
+func publicKeyFingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := marshalPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// signMessage signs msg the way each algorithm expects: Ed25519 over the message itself and the
+// others over its SHA-256 digest
+func signMessage(signer crypto.Signer, msg []byte) ([]byte, error) {
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(msg)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// ethereumAddress is the account of a secp256k1 signer
+func ethereumAddress(signer crypto.Signer) (string, error) {
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok || pub.Curve.Params().Name != ethcrypto.S256().Params().Name {
+		return "", errors.New("blockchain signing key must be a secp256k1 key")
+	}
+	return ethcrypto.PubkeyToAddress(*pub).Hex(), nil
+}
+
+// signEthereumHash returns the 65-byte R || S || V signature Ethereum expects. crypto.Signer only
+// yields ASN.1 (r, s), so S is moved to the lower half of the curve order as EIP-2 requires and
+// V is found by recovering the public key.
+func signEthereumHash(signer Signer, hash []byte) ([]byte, error) {
+	if k, ok := signer.(*keySigner); ok {
+		if key, ok := k.Signer.(*ecdsa.PrivateKey); ok {
+			return ethcrypto.Sign(hash, key)
+		}
+	}
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("blockchain signing key must be a secp256k1 key")
+	}
+
+	der, err := signer.Sign(rand.Reader, hash, crypto.Hash(0))
+	if err != nil {
+		return nil, err
+	}
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("malformed ECDSA signature: %w", err)
+	}
+	n := ethcrypto.S256().Params().N
+	if sig.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		sig.S.Sub(n, sig.S)
+	}
+
+	out := make([]byte, 65)
+	sig.R.FillBytes(out[:32])
+	sig.S.FillBytes(out[32:64])
+	want := ethcrypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		out[64] = v
+		if recovered, err := ethcrypto.Ecrecover(hash, out); err == nil && bytes.Equal(recovered, want) {
+			return out, nil
+		}
+	}
+	return nil, errors.New("signature does not recover to the blockchain signing key")
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+)
+
+// TemplateService manages certificate templates and issues certificates rendered from them
+type TemplateService struct {
+	store        store.Store
+	certificates *CertificateService
+	approvals    *ApprovalService
+	verifyURL    string
+}
+
+func NewTemplateService(cfg config.Config, s store.Store, certificates *CertificateService, approvals *ApprovalService) *TemplateService {
+	return &TemplateService{
+		store:        s,
+		certificates: certificates,
+		approvals:    approvals,
+		verifyURL:    cfg.PublicVerifyURL,
+	}
+}
+
+// VerificationURL returns the public verification link encoded in a certificate's QR code
+func (t *TemplateService) VerificationURL(certID string) string {
+	return t.verifyURL + certID
+}
+
+// CreateTemplate stores a new version of the template for an institution and credential type
+func (t *TemplateService) CreateTemplate(req models.CreateTemplateRequest, actorID string) (*models.CertificateTemplate, error) {
+	if err := t.requireManager(actorID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Institution) == "" || req.CertType == "" {
+		return nil, fmt.Errorf("institution and cert_type are required")
+	}
+
+	now := time.Now()
+	tpl := models.CertificateTemplate{
+		Institution: strings.TrimSpace(req.Institution),
+		CertType:    req.CertType,
+		Name:        req.Name,
+		PageSize:    req.PageSize,
+		Landscape:   req.Landscape,
+		Elements:    req.Elements,
+		CreatedBy:   actorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := validateTemplate(tpl); err != nil {
+		return nil, err
+	}
+
+	existing, err := t.store.ListCertificateTemplates(tpl.Institution, tpl.CertType)
+	if err != nil {
+		return nil, err
+	}
+	hasActive := false
+	for _, e := range existing {
+		if e.Version > tpl.Version {
+			tpl.Version = e.Version
+		}
+		hasActive = hasActive || e.Active
+	}
+	tpl.Version++
+
+	created, err := t.store.CreateCertificateTemplate(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save template: %w", err)
+	}
+
+	// The first version becomes active automatically
+	if req.Activate || !hasActive {
+		return t.activate(created)
+	}
+	return &created, nil
+}
+
+// ListTemplates returns template versions, newest first; empty filters match everything
+func (t *TemplateService) ListTemplates(institution string, certType models.CredentialType) ([]models.CertificateTemplate, error) {
+	templates, err := t.store.ListCertificateTemplates(institution, certType)
+	if err != nil {
+		return nil, err
+	}
+	sortTemplates(templates)
+	return templates, nil
+}
+
+func (t *TemplateService) GetTemplate(id string) (*models.CertificateTemplate, error) {
+	tpl, err := t.store.GetCertificateTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+// ActivateTemplate makes a version the one used for new certificates, e.g. to roll back
+func (t *TemplateService) ActivateTemplate(id, actorID string) (*models.CertificateTemplate, error) {
+	if err := t.requireManager(actorID); err != nil {
+		return nil, err
+	}
+	tpl, err := t.store.GetCertificateTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.activate(tpl)
+}
+
+func (t *TemplateService) activate(tpl models.CertificateTemplate) (*models.CertificateTemplate, error) {
+	versions, err := t.store.ListCertificateTemplates(tpl.Institution, tpl.CertType)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Active && v.ID != tpl.ID {
+			v.Active = false
+			v.UpdatedAt = time.Now()
+			if _, err := t.store.UpdateCertificateTemplate(v.ID.Hex(), v); err != nil {
+				return nil, fmt.Errorf("failed to deactivate version %d: %w", v.Version, err)
+			}
+		}
+	}
+
+	tpl.Active = true
+	tpl.UpdatedAt = time.Now()
+	updated, err := t.store.UpdateCertificateTemplate(tpl.ID.Hex(), tpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate template: %w", err)
+	}
+	return &updated, nil
+}
+
+// Preview renders a template with the supplied (or placeholder) data without issuing anything
+func (t *TemplateService) Preview(id string, req models.PreviewTemplateRequest) ([]byte, error) {
+	tpl, err := t.store.GetCertificateTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := req.Metadata
+	if meta.StudentName == "" {
+		meta.StudentName = "Student Name"
+	}
+	if meta.Institution == "" {
+		meta.Institution = tpl.Institution
+	}
+	studentID := req.StudentID
+	if studentID == "" {
+		studentID = "STUDENT-ID"
+	}
+
+	const previewID = "PREVIEW"
+	return renderTemplate(tpl, templateValues(previewID, t.VerificationURL(previewID), studentID, tpl.CertType, meta, time.Now()))
+}
+
+// GenerateCertificate renders the certificate PDF from a template and sends it through issuance.
+// Credential types under an approval policy produce a submitted draft instead of a certificate.
+func (t *TemplateService) GenerateCertificate(ctx context.Context, req models.GenerateCertificateRequest, issuerID string) (*models.Certificate, *models.CertificateDraft, error) {
+	student, err := t.store.GetUserByStudentID(req.StudentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("student not found: %w", err)
+	}
+	issuer, err := t.store.GetUserByID(issuerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("issuer not found: %w", err)
+	}
+	if !t.certificates.canIssueCertificate(issuer.Role, req.CertType) {
+		return nil, nil, fmt.Errorf("%w: issuer cannot issue %s certificates", ErrPermissionDenied, req.CertType)
+	}
+
+	meta := req.Metadata
+	if meta.StudentName == "" {
+		meta.StudentName = student.Name
+	}
+	if meta.StudentEmail == "" {
+		meta.StudentEmail = student.Email
+	}
+	if meta.IssuerName == "" {
+		meta.IssuerName = issuer.Name
+	}
+	if meta.IssuerRole == "" {
+		meta.IssuerRole = issuer.Role
+	}
+	if meta.Institution == "" {
+		meta.Institution = issuer.Institution
+	}
+
+	tpl, err := t.resolveTemplate(req.TemplateID, meta.Institution, req.CertType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The document embeds its own cert ID, so the ID is derived from the render inputs rather than
+	// the file hash. The file hash of the rendered PDF is still what gets anchored on-chain.
+	issuedAt := time.Now()
+	seed, err := json.Marshal(struct {
+		TemplateID string                     `json:"template_id"`
+		Version    int                        `json:"version"`
+		StudentID  string                     `json:"student_id"`
+		CertType   models.CredentialType      `json:"cert_type"`
+		Metadata   models.CertificateMetadata `json:"metadata"`
+	}{tpl.ID.Hex(), tpl.Version, req.StudentID, req.CertType, meta})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal render inputs: %w", err)
+	}
+	certID := t.certificates.blockchainService.ComputeCertID(t.certificates.computeFileHash(seed), req.StudentID, issuedAt)
+
+	document, err := renderTemplate(tpl, templateValues(certID, t.VerificationURL(certID), req.StudentID, req.CertType, meta, issuedAt))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+
+	if meta.AdditionalData == nil {
+		meta.AdditionalData = make(map[string]interface{})
+	}
+	if !tpl.ID.IsZero() {
+		meta.AdditionalData["template_id"] = tpl.ID.Hex()
+	}
+	meta.AdditionalData["template_version"] = tpl.Version
+
+	issueReq := models.IssueCertificateRequest{
+		StudentID: req.StudentID,
+		CertType:  req.CertType,
+		FileData:  document,
+		FileName:  fmt.Sprintf("%s-%s.pdf", req.CertType, req.StudentID),
+		Metadata:  meta,
+	}
+
+	if !t.certificates.requiresApproval(req.CertType) {
+		cert, err := t.certificates.issueCertificate(ctx, issueReq, issuerID, issueOptions{certID: certID, issuedAt: issuedAt, generated: true})
+		return cert, nil, err
+	}
+
+	draft, err := t.approvals.newDraft(issueReq, issuerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	draft.CertID = certID
+	draft.IssuedAt = &issuedAt
+
+	created, err := t.store.CreateCertificateDraft(*draft)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	submitted, err := t.approvals.SubmitDraft(ctx, created.ID.Hex(), issuerID)
+	return nil, submitted, err
+}
+
+// resolveTemplate picks the requested template, else the active one, else the built-in layout
+func (t *TemplateService) resolveTemplate(templateID, institution string, certType models.CredentialType) (models.CertificateTemplate, error) {
+	if templateID != "" {
+		tpl, err := t.store.GetCertificateTemplateByID(templateID)
+		if err != nil {
+			return models.CertificateTemplate{}, err
+		}
+		if tpl.CertType != certType {
+			return models.CertificateTemplate{}, fmt.Errorf("template is for %s certificates, not %s", tpl.CertType, certType)
+		}
+		return tpl, nil
+	}
+
+	versions, err := t.store.ListCertificateTemplates(institution, certType)
+	if err != nil {
+		return models.CertificateTemplate{}, err
+	}
+	for _, v := range versions {
+		if v.Active {
+			return v, nil
+		}
+	}
+	return defaultTemplate(institution, certType), nil
+}
+
+func (t *TemplateService) requireManager(actorID string) error {
+	actor, err := t.store.GetUserByID(actorID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if !actor.CanPerformAction("can_manage_templates") {
+		return fmt.Errorf("%w: managing templates requires can_manage_templates", ErrPermissionDenied)
+	}
+	return nil
+}
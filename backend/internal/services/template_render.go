@@ -0,0 +1,328 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pdf"
+	"blockcred-backend/internal/qrcode"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// templatePlaceholders lists the values available to templates besides additional.<key>
+var templatePlaceholders = map[string]bool{
+	"cert_id": true, "cert_type": true, "cert_title": true, "verification_url": true,
+	"student_id": true, "student_name": true, "student_email": true,
+	"issuer_name": true, "issuer_role": true, "institution": true, "department": true,
+	"course": true, "semester": true, "academic_year": true, "grade": true, "cgpa": true,
+	"valid_from": true, "valid_until": true, "issued_date": true, "description": true,
+	"details": true,
+}
+
+// certTitles are the headings used by the built-in layout
+var certTitles = map[models.CredentialType]string{
+	models.CredentialTypeMarksheet:     "Semester Marksheet",
+	models.CredentialTypeDegree:        "Degree Certificate",
+	models.CredentialTypeBonafide:      "Bonafide Certificate",
+	models.CredentialTypeNOC:           "No Objection Certificate",
+	models.CredentialTypeParticipation: "Certificate of Participation",
+	models.CredentialTypeNFT:           "Certificate of Achievement",
+	models.CredentialTypeTranscript:    "Consolidated Academic Transcript",
+}
+
+const templateDateLayout = "02 January 2006"
+
+// templateValues builds the placeholder values for a certificate
+func templateValues(certID, verifyURL, studentID string, certType models.CredentialType, meta models.CertificateMetadata, issuedAt time.Time) map[string]string {
+	title := certTitles[certType]
+	if title == "" {
+		title = "Certificate"
+	}
+
+	values := map[string]string{
+		"cert_id":          certID,
+		"cert_type":        string(certType),
+		"cert_title":       title,
+		"verification_url": verifyURL,
+		"student_id":       studentID,
+		"student_name":     meta.StudentName,
+		"student_email":    meta.StudentEmail,
+		"issuer_name":      meta.IssuerName,
+		"issuer_role":      string(meta.IssuerRole),
+		"institution":      meta.Institution,
+		"department":       meta.Department,
+		"course":           meta.Course,
+		"semester":         meta.Semester,
+		"academic_year":    meta.AcademicYear,
+		"grade":            meta.Grade,
+		"description":      meta.Description,
+		"issued_date":      issuedAt.Format(templateDateLayout),
+	}
+	if meta.CGPA != 0 {
+		values["cgpa"] = strconv.FormatFloat(meta.CGPA, 'f', 2, 64)
+	}
+	if !meta.ValidFrom.IsZero() {
+		values["valid_from"] = meta.ValidFrom.Format(templateDateLayout)
+	}
+	if !meta.ValidUntil.IsZero() {
+		values["valid_until"] = meta.ValidUntil.Format(templateDateLayout)
+	}
+	for k, v := range meta.AdditionalData {
+		values["additional."+k] = fmt.Sprint(v)
+	}
+
+	// details is a ready-made summary line of whichever academic fields are present
+	var details []string
+	for _, field := range []struct{ label, key string }{
+		{"Course", "course"}, {"Semester", "semester"}, {"Academic Year", "academic_year"},
+		{"Grade", "grade"}, {"CGPA", "cgpa"},
+	} {
+		if values[field.key] != "" {
+			details = append(details, field.label+": "+values[field.key])
+		}
+	}
+	values["details"] = strings.Join(details, "  ·  ")
+
+	return values
+}
+
+// fillPlaceholders substitutes {{name}} markers; unknown names render empty
+func fillPlaceholders(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return values[placeholderPattern.FindStringSubmatch(m)[1]]
+	})
+}
+
+// validateTemplate checks page settings, element geometry, colors and placeholder names
+func validateTemplate(tpl models.CertificateTemplate) error {
+	if _, _, err := pageDimensions(tpl); err != nil {
+		return err
+	}
+	if len(tpl.Elements) == 0 {
+		return fmt.Errorf("template has no elements")
+	}
+
+	hasQR := false
+	for i, el := range tpl.Elements {
+		if _, err := parseColor(el.Color); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		switch el.Type {
+		case models.TemplateElementText:
+			if strings.TrimSpace(el.Text) == "" {
+				return fmt.Errorf("element %d: text is required", i)
+			}
+			for _, m := range placeholderPattern.FindAllStringSubmatch(el.Text, -1) {
+				if !templatePlaceholders[m[1]] && !strings.HasPrefix(m[1], "additional.") {
+					return fmt.Errorf("element %d: unknown placeholder {{%s}}", i, m[1])
+				}
+			}
+			switch el.Align {
+			case "", "left", "center", "right":
+			default:
+				return fmt.Errorf("element %d: align must be left, center or right", i)
+			}
+		case models.TemplateElementLine:
+			if el.Width == 0 && el.Height == 0 {
+				return fmt.Errorf("element %d: line needs a width or height", i)
+			}
+		case models.TemplateElementBox:
+			if el.Width <= 0 || el.Height <= 0 {
+				return fmt.Errorf("element %d: box needs a positive width and height", i)
+			}
+		case models.TemplateElementQR:
+			if el.Width < 48 {
+				return fmt.Errorf("element %d: QR code must be at least 48pt wide", i)
+			}
+			hasQR = true
+		default:
+			return fmt.Errorf("element %d: unknown element type %q", i, el.Type)
+		}
+	}
+	if !hasQR {
+		return fmt.Errorf("template must include a verification QR code element")
+	}
+	return nil
+}
+
+func pageDimensions(tpl models.CertificateTemplate) (float64, float64, error) {
+	var w, h float64
+	switch strings.ToLower(tpl.PageSize) {
+	case "", "a4":
+		w, h = pdf.A4Width, pdf.A4Height
+	case "letter":
+		w, h = pdf.LetterWidth, pdf.LetterHeight
+	default:
+		return 0, 0, fmt.Errorf("unsupported page size %q: use A4 or Letter", tpl.PageSize)
+	}
+	if tpl.Landscape {
+		w, h = h, w
+	}
+	return w, h, nil
+}
+
+func parseColor(hex string) (pdf.Color, error) {
+	if hex == "" {
+		return pdf.Black, nil
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return pdf.Color{}, fmt.Errorf("invalid color %q: use #RRGGBB", hex)
+	}
+	return pdf.Color{
+		R: float64(v>>16&0xff) / 255,
+		G: float64(v>>8&0xff) / 255,
+		B: float64(v&0xff) / 255,
+	}, nil
+}
+
+// renderTemplate draws the template with the given values and returns the PDF bytes
+func renderTemplate(tpl models.CertificateTemplate, values map[string]string) ([]byte, error) {
+	w, h, err := pageDimensions(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New(w, h)
+	doc.Title = values["cert_title"] + " - " + values["student_name"]
+	page := doc.AddPage()
+
+	for _, el := range tpl.Elements {
+		color, _ := parseColor(el.Color)
+		lineWidth := el.LineWidth
+		if lineWidth == 0 {
+			lineWidth = 1
+		}
+
+		switch el.Type {
+		case models.TemplateElementText:
+			drawTextElement(page, el, color, fillPlaceholders(el.Text, values))
+		case models.TemplateElementLine:
+			page.Line(el.X, el.Y, el.X+el.Width, el.Y+el.Height, lineWidth, color)
+		case models.TemplateElementBox:
+			if el.Fill {
+				page.FillRect(el.X, el.Y, el.Width, el.Height, color)
+			} else {
+				page.Rect(el.X, el.Y, el.Width, el.Height, lineWidth, color)
+			}
+		case models.TemplateElementQR:
+			if err := drawQRCode(page, el.X, el.Y, el.Width, values["verification_url"]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+func drawTextElement(page *pdf.Page, el models.TemplateElement, color pdf.Color, text string) {
+	if strings.TrimSpace(text) == "" {
+		return // Optional fields left blank simply disappear
+	}
+	size := el.FontSize
+	if size == 0 {
+		size = 12
+	}
+
+	lines := []string{text}
+	if el.Width > 0 {
+		lines = pdf.WrapText(text, size, el.Bold, el.Width)
+	}
+	for i, line := range lines {
+		x := el.X
+		switch el.Align {
+		case "center":
+			x += (el.Width - pdf.TextWidth(line, size, el.Bold)) / 2
+		case "right":
+			x += el.Width - pdf.TextWidth(line, size, el.Bold)
+		}
+		page.Text(x, el.Y+float64(i)*size*1.25, size, el.Bold, color, line)
+	}
+}
+
+// drawQRCode renders the QR symbol inside a square of the given side, including the quiet zone
+func drawQRCode(page *pdf.Page, x, y, side float64, content string) error {
+	code, err := qrcode.Encode([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to encode verification QR code: %w", err)
+	}
+
+	const quietZone = 4
+	module := side / float64(code.Size+2*quietZone)
+	page.FillRect(x, y, side, side, pdf.Color{R: 1, G: 1, B: 1})
+
+	// Merge horizontal runs of dark modules to keep the content stream small
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; {
+			if !code.Dark(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < code.Size && code.Dark(col, row) {
+				col++
+			}
+			page.FillRect(x+float64(quietZone+start)*module, y+float64(quietZone+row)*module,
+				float64(col-start)*module, module, pdf.Black)
+		}
+	}
+	return nil
+}
+
+// defaultTemplate is the built-in landscape A4 layout used when an institution has no template
+func defaultTemplate(institution string, certType models.CredentialType) models.CertificateTemplate {
+	const (
+		pageW = pdf.A4Height // Landscape
+		navy  = "#1F3A5F"
+		grey  = "#555555"
+	)
+	text := func(y, size float64, bold bool, color, s string) models.TemplateElement {
+		return models.TemplateElement{Type: models.TemplateElementText, X: 90, Y: y, Width: pageW - 180,
+			FontSize: size, Bold: bold, Align: "center", Color: color, Text: s}
+	}
+
+	return models.CertificateTemplate{
+		Institution: institution,
+		CertType:    certType,
+		Name:        "Built-in default",
+		PageSize:    "A4",
+		Landscape:   true,
+		Elements: []models.TemplateElement{
+			{Type: models.TemplateElementBox, X: 24, Y: 24, Width: pageW - 48, Height: pdf.A4Width - 48, LineWidth: 2.5, Color: navy},
+			{Type: models.TemplateElementBox, X: 32, Y: 32, Width: pageW - 64, Height: pdf.A4Width - 64, LineWidth: 0.75, Color: navy},
+			text(92, 24, true, navy, "{{institution}}"),
+			text(116, 13, false, grey, "{{department}}"),
+			text(178, 30, true, "", "{{cert_title}}"),
+			text(226, 14, false, grey, "This is to certify that"),
+			text(266, 26, true, navy, "{{student_name}}"),
+			text(292, 12, false, grey, "Register No. {{student_id}}"),
+			text(330, 13, false, "", "{{description}}"),
+			text(400, 12, false, "", "{{details}}"),
+			{Type: models.TemplateElementText, X: 80, Y: 460, FontSize: 11, Color: grey, Text: "Issued on {{issued_date}}"},
+			{Type: models.TemplateElementLine, X: 80, Y: 505, Width: 200, LineWidth: 0.75},
+			{Type: models.TemplateElementText, X: 80, Y: 522, Width: 200, FontSize: 11, Bold: true, Align: "center", Text: "{{issuer_name}}"},
+			{Type: models.TemplateElementText, X: 80, Y: 536, Width: 200, FontSize: 9, Align: "center", Color: grey, Text: "{{issuer_role}}"},
+			{Type: models.TemplateElementQR, X: pageW - 190, Y: 415, Width: 110},
+			{Type: models.TemplateElementText, X: pageW - 190, Y: 535, Width: 110, FontSize: 8, Align: "center", Color: grey, Text: "Scan to verify"},
+			{Type: models.TemplateElementText, X: 0, Y: pdf.A4Width - 40, Width: pageW, FontSize: 7, Align: "center", Color: grey, Text: "Certificate ID: {{cert_id}}"},
+		},
+	}
+}
+
+// sortTemplates orders versions newest first
+func sortTemplates(templates []models.CertificateTemplate) {
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Institution != templates[j].Institution {
+			return templates[i].Institution < templates[j].Institution
+		}
+		if templates[i].CertType != templates[j].CertType {
+			return templates[i].CertType < templates[j].CertType
+		}
+		return templates[i].Version > templates[j].Version
+	})
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+)
+
+// ErrInvalidToken is returned for session tokens that are malformed, forged or expired
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// jwsAlgorithms maps key algorithms to the JWS "alg" header values used to sign with them
+var jwsAlgorithms = map[string]string{
+	KeyAlgorithmEd25519:   "EdDSA",
+	KeyAlgorithmP256:      "ES256",
+	KeyAlgorithmSecp256k1: "ES256K",
+	KeyAlgorithmRSA:       "RS256",
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+type tokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenIssuer signs session tokens as JWTs with the tokens key. The kid header names the key, so
+// tokens issued before a rotation stay valid until they expire.
+type TokenIssuer struct {
+	keys   *ManagedKey
+	issuer string
+	ttl    time.Duration
+}
+
+func NewTokenIssuer(cfg config.Config, keys *ManagedKey) *TokenIssuer {
+	return &TokenIssuer{
+		keys:   keys,
+		issuer: cfg.InstitutionDID,
+		ttl:    time.Duration(cfg.TokenTTLHours) * time.Hour,
+	}
+}
+
+// Issue returns a signed token for the user
+func (t *TokenIssuer) Issue(userID string) (string, error) {
+	kid, signer := t.keys.Current()
+	now := time.Now()
+	header, _ := json.Marshal(tokenHeader{Algorithm: jwsAlgorithms[signer.Algorithm()], KeyID: kid, Type: "JWT"})
+	claims, _ := json.Marshal(tokenClaims{Issuer: t.issuer, Subject: userID, IssuedAt: now.Unix(), ExpiresAt: now.Add(t.ttl).Unix()})
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature, err := signMessage(signer, []byte(input))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	// JWS carries ECDSA signatures as fixed-size r || s rather than ASN.1
+	if pub, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return "", fmt.Errorf("failed to sign token: %w", err)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		signature = append(sig.R.FillBytes(make([]byte, size)), sig.S.FillBytes(make([]byte, size))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks a token's signature and lifetime and returns the ID of the user it was issued to
+func (t *TokenIssuer) Verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+	var header tokenHeader
+	var claims tokenClaims
+	if decodeTokenPart(parts[0], &header) != nil || decodeTokenPart(parts[1], &claims) != nil {
+		return "", ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	pub, err := t.keys.PublicKey(header.KeyID, time.Unix(claims.IssuedAt, 0))
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	algorithm, _ := keyAlgorithm(pub)
+	if header.Algorithm != jwsAlgorithms[algorithm] || !verifyTokenSignature(pub, []byte(parts[0]+"."+parts[1]), signature) {
+		return "", ErrInvalidToken
+	}
+
+	now := time.Now().Unix()
+	if claims.Issuer != t.issuer || claims.Subject == "" || claims.ExpiresAt <= now || claims.IssuedAt > now+60 {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func verifyTokenSignature(pub crypto.PublicKey, input, signature []byte) bool {
+	digest := sha256.Sum256(input)
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, input, signature)
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest[:], r, s)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/store"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TranscriptService consolidates a student's semester marksheets into one anchored transcript
+type TranscriptService struct {
+	store        store.Store
+	certificates *CertificateService
+	approvals    *ApprovalService
+	verifyURL    string
+}
+
+func NewTranscriptService(cfg config.Config, s store.Store, certificates *CertificateService, approvals *ApprovalService) *TranscriptService {
+	return &TranscriptService{
+		store:        s,
+		certificates: certificates,
+		approvals:    approvals,
+		verifyURL:    cfg.PublicVerifyURL,
+	}
+}
+
+// Summary computes the transcript for a student without issuing anything
+func (t *TranscriptService) Summary(studentID string, viewer models.User) (*models.TranscriptSummary, error) {
+	isSelf := viewer.StudentID != "" && viewer.StudentID == studentID
+	if !isSelf && !viewer.CanPerformAction("can_view_all_credentials") && !viewer.CanPerformAction("can_issue_marksheet") {
+		return nil, fmt.Errorf("%w: transcripts are visible to the student and examination staff", ErrPermissionDenied)
+	}
+	return t.summarize(studentID)
+}
+
+// Generate renders the transcript PDF and issues it as a certificate referencing its marksheets.
+// Transcripts under an approval policy produce a submitted draft instead.
+func (t *TranscriptService) Generate(ctx context.Context, req models.GenerateTranscriptRequest, issuerID string) (*models.Certificate, *models.CertificateDraft, error) {
+	issuer, err := t.store.GetUserByID(issuerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("issuer not found: %w", err)
+	}
+	if !t.certificates.canIssueCertificate(issuer.Role, models.CredentialTypeTranscript) {
+		return nil, nil, fmt.Errorf("%w: issuer cannot issue transcripts", ErrPermissionDenied)
+	}
+
+	summary, err := t.summarize(req.StudentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	certIDs := make([]string, len(summary.Entries))
+	for i, entry := range summary.Entries {
+		certIDs[i] = entry.CertID
+	}
+
+	// The PDF prints its own cert ID, so reserve it from the constituents like generated certificates do
+	issuedAt := time.Now()
+	seed, err := json.Marshal(struct {
+		StudentID string   `json:"student_id"`
+		CertIDs   []string `json:"cert_ids"`
+	}{req.StudentID, certIDs})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal transcript inputs: %w", err)
+	}
+	certID := t.certificates.blockchainService.ComputeCertID(t.certificates.computeFileHash(seed), req.StudentID, issuedAt)
+
+	meta := models.CertificateMetadata{
+		StudentName: summary.StudentName,
+		IssuerName:  issuer.Name,
+		IssuerRole:  issuer.Role,
+		Institution: summary.Institution,
+		Department:  summary.Department,
+		CGPA:        summary.CumulativeCGPA,
+		ValidFrom:   issuedAt,
+		Description: fmt.Sprintf("Consolidated transcript of %d semester marksheets", len(summary.Entries)),
+		AdditionalData: map[string]interface{}{
+			"constituent_cert_ids": certIDs,
+			"total_credits":        summary.TotalCredits,
+			"semesters":            len(summary.Entries),
+			"credit_weighted":      summary.CreditWeighted,
+		},
+	}
+	if student, err := t.store.GetUserByStudentID(req.StudentID); err == nil {
+		meta.StudentEmail = student.Email
+	}
+
+	document, err := renderTranscript(summary, certID, t.verifyURL+certID, issuer.Name, issuedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render transcript: %w", err)
+	}
+
+	issueReq := models.IssueCertificateRequest{
+		StudentID: req.StudentID,
+		CertType:  models.CredentialTypeTranscript,
+		FileData:  document,
+		FileName:  fmt.Sprintf("transcript-%s.pdf", req.StudentID),
+		Metadata:  meta,
+	}
+
+	if !t.certificates.requiresApproval(models.CredentialTypeTranscript) {
+		cert, err := t.certificates.issueCertificate(ctx, issueReq, issuerID, issueOptions{certID: certID, issuedAt: issuedAt, generated: true})
+		return cert, nil, err
+	}
+
+	draft, err := t.approvals.newDraft(issueReq, issuerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	draft.CertID = certID
+	draft.IssuedAt = &issuedAt
+
+	created, err := t.store.CreateCertificateDraft(*draft)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	submitted, err := t.approvals.SubmitDraft(ctx, created.ID.Hex(), issuerID)
+	return nil, submitted, err
+}
+
+// Verify checks a transcript and drills down into each marksheet it was built from
+func (t *TranscriptService) Verify(certID string) (*models.TranscriptVerificationResult, error) {
+	cert, err := t.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return nil, fmt.Errorf("certificate not found")
+	}
+	if cert.CertType != models.CredentialTypeTranscript {
+		return nil, fmt.Errorf("certificate %s is a %s, not a transcript", certID, cert.CertType)
+	}
+
+	check, err := t.certificates.VerifyCertificate(certID)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.TranscriptVerificationResult{
+		Transcript:     check,
+		CumulativeCGPA: cert.Metadata.CGPA,
+		Constituents:   []models.CertificateVerificationResult{},
+	}
+	result.TotalCredits, _ = numberValue(cert.Metadata.AdditionalData["total_credits"])
+	if !check.IsValid {
+		result.Errors = append(result.Errors, "transcript: "+check.ErrorMessage)
+	}
+
+	constituents := stringList(cert.Metadata.AdditionalData["constituent_cert_ids"])
+	if len(constituents) == 0 {
+		result.Errors = append(result.Errors, "transcript does not reference any marksheets")
+	}
+	for _, id := range constituents {
+		c, err := t.certificates.VerifyCertificate(id)
+		if err != nil {
+			return nil, err
+		}
+		result.Constituents = append(result.Constituents, *c)
+		switch {
+		case c.CertType != "" && c.CertType != models.CredentialTypeMarksheet:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s is not a marksheet", id))
+		case c.StudentID != "" && c.StudentID != cert.StudentID:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s belongs to another student", id))
+		case !c.IsValid:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", id, c.ErrorMessage))
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// summarize collects the current marksheet for each semester and computes cumulative figures
+func (t *TranscriptService) summarize(studentID string) (*models.TranscriptSummary, error) {
+	student, err := t.store.GetUserByStudentID(studentID)
+	if err != nil {
+		return nil, fmt.Errorf("student not found: %w", err)
+	}
+	certs, err := t.store.ListCertificatesByStudent(studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve certificates: %w", err)
+	}
+	return summarizeMarksheets(student, certs)
+}
+
+// summarizeMarksheets builds a transcript summary from a student's certificates
+func summarizeMarksheets(student models.User, certs []models.Certificate) (*models.TranscriptSummary, error) {
+	studentID := student.StudentID
+	summary := &models.TranscriptSummary{
+		StudentID:   studentID,
+		StudentName: student.Name,
+		Department:  student.Department,
+		Institution: student.Institution,
+		Entries:     []models.TranscriptEntry{},
+	}
+
+	bySemester := map[string]models.TranscriptEntry{}
+	for _, cert := range certs {
+		if cert.CertType != models.CredentialTypeMarksheet {
+			continue
+		}
+		if cert.Status != models.CertStatusIssued && cert.Status != models.CertStatusVerified {
+			continue // Revoked, suspended and superseded marksheets never count
+		}
+		entry := transcriptEntry(cert)
+		key := entry.Semester
+		if entry.SemesterNumber > 0 {
+			key = strconv.Itoa(entry.SemesterNumber)
+		}
+		if existing, ok := bySemester[key]; ok {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("semester %s has more than one marksheet (%s, %s); using the latest", entry.Semester, existing.CertID, entry.CertID))
+			if existing.IssuedAt.After(entry.IssuedAt) {
+				continue
+			}
+		}
+		bySemester[key] = entry
+		if summary.Department == "" {
+			summary.Department = cert.Metadata.Department
+		}
+		if summary.Institution == "" {
+			summary.Institution = cert.Metadata.Institution
+		}
+	}
+	if len(bySemester) == 0 {
+		return nil, fmt.Errorf("student %s has no current marksheets", studentID)
+	}
+
+	for _, entry := range bySemester {
+		summary.Entries = append(summary.Entries, entry)
+	}
+	sort.Slice(summary.Entries, func(i, j int) bool {
+		a, b := summary.Entries[i], summary.Entries[j]
+		if a.SemesterNumber != b.SemesterNumber {
+			return a.SemesterNumber < b.SemesterNumber
+		}
+		return a.Semester < b.Semester
+	})
+
+	// Credit-weighted CGPA when every semester reports credits, otherwise the plain mean of GPAs
+	summary.CreditWeighted = true
+	var weighted, plain float64
+	for i, entry := range summary.Entries {
+		if entry.SemesterNumber > 0 && i > 0 && entry.SemesterNumber != summary.Entries[i-1].SemesterNumber+1 {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("no marksheet between semesters %d and %d", summary.Entries[i-1].SemesterNumber, entry.SemesterNumber))
+		}
+		if entry.Credits <= 0 {
+			summary.CreditWeighted = false
+		}
+		summary.TotalCredits += entry.Credits
+		weighted += entry.GPA * entry.Credits
+		plain += entry.GPA
+	}
+	if summary.CreditWeighted {
+		summary.CumulativeCGPA = weighted / summary.TotalCredits
+	} else {
+		summary.CumulativeCGPA = plain / float64(len(summary.Entries))
+		summary.Warnings = append(summary.Warnings, "some marksheets do not record credits; CGPA is the unweighted mean of semester GPAs")
+	}
+	summary.CumulativeCGPA = math.Round(summary.CumulativeCGPA*100) / 100
+	return summary, nil
+}
+
+// transcriptEntry reads the semester figures from a marksheet. The semester GPA is taken from
+// additional_data.sgpa (or gpa) when present, else from the CGPA field; credits from
+// additional_data.credits (or credits_earned).
+func transcriptEntry(cert models.Certificate) models.TranscriptEntry {
+	meta := cert.Metadata
+	entry := models.TranscriptEntry{
+		CertID:         cert.CertID,
+		Semester:       meta.Semester,
+		SemesterNumber: semesterNumber(meta.Semester),
+		AcademicYear:   meta.AcademicYear,
+		Course:         meta.Course,
+		Grade:          meta.Grade,
+		GPA:            meta.CGPA,
+		Status:         cert.Status,
+		IssuedAt:       cert.IssuedAt,
+	}
+	for _, key := range []string{"sgpa", "gpa"} {
+		if v, ok := numberValue(meta.AdditionalData[key]); ok {
+			entry.GPA = v
+			break
+		}
+	}
+	for _, key := range []string{"credits", "credits_earned"} {
+		if v, ok := numberValue(meta.AdditionalData[key]); ok {
+			entry.Credits = v
+			break
+		}
+	}
+	return entry
+}
+
+var romanNumerals = map[string]int{
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5, "vi": 6, "vii": 7, "viii": 8, "ix": 9, "x": 10, "xi": 11, "xii": 12,
+}
+
+// semesterNumber understands "5", "Semester 5", "sem-05" and "Semester V"; 0 when unknown
+func semesterNumber(s string) int {
+	for _, field := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_' || r == '.'
+	}) {
+		digits := strings.TrimLeft(field, "abcdefghijklmnopqrstuvwxyz")
+		if n, err := strconv.Atoi(digits); err == nil && digits != "" {
+			return n
+		}
+		if n, ok := romanNumerals[field]; ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// numberValue accepts the numeric shapes additional_data takes after JSON or BSON decoding
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// stringList reads a list of strings stored in additional_data
+func stringList(v interface{}) []string {
+	var items []interface{}
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		items = list
+	case primitive.A:
+		items = list
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
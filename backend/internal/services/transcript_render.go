@@ -0,0 +1,148 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/pdf"
+)
+
+var (
+	transcriptNavy  = pdf.Color{R: 0.12, G: 0.23, B: 0.37}
+	transcriptGrey  = pdf.Color{R: 0.33, G: 0.33, B: 0.33}
+	transcriptShade = pdf.Color{R: 0.93, G: 0.95, B: 0.97}
+)
+
+// transcriptColumns are the table columns as x offset and heading
+var transcriptColumns = []struct {
+	x     float64
+	title string
+}{
+	{48, "Semester"}, {118, "Academic Year"}, {208, "Course"}, {330, "Credits"}, {380, "GPA"}, {424, "Certificate ID"},
+}
+
+const (
+	transcriptRowHeight = 20
+	transcriptTableTop  = 210
+	transcriptPageLimit = pdf.A4Height - 210 // Leave room for totals and the verification block
+)
+
+// renderTranscript draws the consolidated transcript on portrait A4 pages
+func renderTranscript(summary *models.TranscriptSummary, certID, verifyURL, issuerName string, issuedAt time.Time) ([]byte, error) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.Title = "Consolidated Academic Transcript - " + summary.StudentName
+
+	page := doc.AddPage()
+	drawTranscriptHeader(page, summary)
+	y := drawTranscriptTableHead(page, transcriptTableTop)
+
+	for i, entry := range summary.Entries {
+		if y > transcriptPageLimit {
+			page = doc.AddPage()
+			y = drawTranscriptTableHead(page, 60)
+		}
+		if i%2 == 1 {
+			page.FillRect(40, y-14, pdf.A4Width-80, transcriptRowHeight, transcriptShade)
+		}
+		semester := entry.Semester
+		if semester == "" {
+			semester = "-"
+		}
+		cells := []string{semester, entry.AcademicYear, entry.Course, formatCredits(entry.Credits), strconv.FormatFloat(entry.GPA, 'f', 2, 64), shortCertID(entry.CertID)}
+		for c, cell := range cells {
+			page.Text(transcriptColumns[c].x, y, 9, false, pdf.Black, truncateText(cell, 9, columnWidth(c)))
+		}
+		y += transcriptRowHeight
+	}
+
+	page.Line(40, y-10, pdf.A4Width-40, y-10, 0.75, transcriptNavy)
+	page.Text(48, y+6, 10, true, pdf.Black, "Total credits")
+	page.Text(transcriptColumns[3].x, y+6, 10, true, pdf.Black, formatCredits(summary.TotalCredits))
+	y += 24
+	cgpaLabel := "Cumulative CGPA (credit-weighted)"
+	if !summary.CreditWeighted {
+		cgpaLabel = "Cumulative CGPA (mean of semesters)"
+	}
+	page.Text(48, y, 12, true, transcriptNavy, cgpaLabel+": "+strconv.FormatFloat(summary.CumulativeCGPA, 'f', 2, 64))
+
+	// Verification block at the foot of the last page
+	const footY = pdf.A4Height - 170
+	if err := drawQRCode(page, pdf.A4Width-150, footY, 100, verifyURL); err != nil {
+		return nil, err
+	}
+	page.Text(48, footY+20, 10, false, transcriptGrey, "Issued on "+issuedAt.Format(templateDateLayout))
+	page.Line(48, footY+60, 248, footY+60, 0.75, pdf.Black)
+	page.Text(48, footY+75, 10, true, pdf.Black, issuerName)
+	page.Text(48, footY+88, 8, false, transcriptGrey, "Controller of Examinations")
+	for i, line := range pdf.WrapText("Each semester marksheet listed above is an independently anchored certificate. Verifying this transcript lists their full certificate IDs and current status.", 7.5, false, pdf.A4Width-230) {
+		page.Text(48, footY+112+float64(i)*10, 7.5, false, transcriptGrey, line)
+	}
+	page.Text(pdf.A4Width-150, footY+112, 7.5, false, transcriptGrey, "Scan to verify")
+	page.Text(48, pdf.A4Height-30, 7, false, transcriptGrey, "Transcript ID: "+certID)
+
+	return doc.Bytes(), nil
+}
+
+func drawTranscriptHeader(page *pdf.Page, summary *models.TranscriptSummary) {
+	centered := func(y, size float64, bold bool, color pdf.Color, s string) {
+		page.Text((pdf.A4Width-pdf.TextWidth(s, size, bold))/2, y, size, bold, color, s)
+	}
+	page.Rect(24, 24, pdf.A4Width-48, pdf.A4Height-48, 1.5, transcriptNavy)
+	if summary.Institution != "" {
+		centered(70, 18, true, transcriptNavy, summary.Institution)
+	}
+	centered(98, 15, true, pdf.Black, "Consolidated Academic Transcript")
+
+	page.Text(48, 140, 10, true, pdf.Black, "Name")
+	page.Text(140, 140, 10, false, pdf.Black, summary.StudentName)
+	page.Text(48, 156, 10, true, pdf.Black, "Register No.")
+	page.Text(140, 156, 10, false, pdf.Black, summary.StudentID)
+	if summary.Department != "" {
+		page.Text(48, 172, 10, true, pdf.Black, "Department")
+		page.Text(140, 172, 10, false, pdf.Black, summary.Department)
+	}
+}
+
+func drawTranscriptTableHead(page *pdf.Page, y float64) float64 {
+	page.FillRect(40, y-14, pdf.A4Width-80, transcriptRowHeight, transcriptNavy)
+	white := pdf.Color{R: 1, G: 1, B: 1}
+	for _, col := range transcriptColumns {
+		page.Text(col.x, y, 9, true, white, col.title)
+	}
+	return y + transcriptRowHeight + 2
+}
+
+func columnWidth(c int) float64 {
+	if c+1 < len(transcriptColumns) {
+		return transcriptColumns[c+1].x - transcriptColumns[c].x - 6
+	}
+	return pdf.A4Width - 48 - transcriptColumns[c].x
+}
+
+// truncateText shortens s with an ellipsis so it fits in width
+func truncateText(s string, size, width float64) string {
+	if pdf.TextWidth(s, size, false) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"...", size, false) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// shortCertID keeps the table readable while staying unambiguous enough to look up
+func shortCertID(certID string) string {
+	if len(certID) <= 22 {
+		return certID
+	}
+	return certID[:12] + "..." + certID[len(certID)-8:]
+}
+
+func formatCredits(credits float64) string {
+	if credits == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(credits, 'f', -1, 64)
+}
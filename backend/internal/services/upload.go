@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"blockcred-backend/internal/config"
+)
+
+// Errors raised while receiving a certificate document
+var (
+	ErrUploadTooLarge      = errors.New("document exceeds the upload size limit")
+	ErrUnsupportedDocument = errors.New("unsupported document type")
+)
+
+// Upload is a certificate document received as a stream and spooled to disk. Its digests are
+// computed on the way in, so issuance never holds the file in memory just to hash it.
+type Upload struct {
+	FileName    string
+	ContentType string // Sniffed from the content; the client's claim is not trusted
+	Size        int64
+	SHA256      string
+	CID         string
+	path        string
+}
+
+func (u *Upload) Open() (io.ReadCloser, error) {
+	return os.Open(u.path)
+}
+
+func (u *Upload) ReadAll() ([]byte, error) {
+	return os.ReadFile(u.path)
+}
+
+// Remove deletes the spool file; callers defer it as soon as Receive succeeds
+func (u *Upload) Remove() {
+	if u != nil && u.path != "" {
+		os.Remove(u.path)
+	}
+}
+
+// replace overwrites the spooled document with a cleaned version and recomputes its digests
+func (u *Upload) replace(data []byte) error {
+	if err := os.WriteFile(u.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to rewrite uploaded file: %w", err)
+	}
+	u.Size = int64(len(data))
+	u.SHA256 = contentSHA256(data)
+	u.CID = ComputeCID(data)
+	return nil
+}
+
+func (u *Upload) source() ContentSource {
+	return ContentSource{Open: u.Open, Size: u.Size, SHA256: u.SHA256, CID: u.CID}
+}
+
+// UploadReceiver accepts certificate documents within the configured size and type limits
+type UploadReceiver struct {
+	dir      string
+	maxBytes int64
+	allowed  map[string]bool
+}
+
+func NewUploadReceiver(cfg config.Config) (*UploadReceiver, error) {
+	if err := os.MkdirAll(cfg.UploadSpoolDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create upload spool directory: %w", err)
+	}
+	allowed := make(map[string]bool)
+	for _, t := range strings.Split(cfg.UploadTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			allowed[t] = true
+		}
+	}
+	return &UploadReceiver{dir: cfg.UploadSpoolDir, maxBytes: cfg.MaxUploadMB << 20, allowed: allowed}, nil
+}
+
+// MaxBytes is the largest document accepted
+func (u *UploadReceiver) MaxBytes() int64 {
+	return u.maxBytes
+}
+
+// Receive streams a document to a spool file, sniffing its type from the first bytes and hashing it as it goes
+func (u *UploadReceiver) Receive(r io.Reader, fileName string) (*Upload, error) {
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, fmt.Errorf("document is empty")
+	}
+	contentType, err := u.sniff(head)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(u.dir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool document: %w", err)
+	}
+	upload := &Upload{FileName: fileName, ContentType: contentType, path: f.Name()}
+
+	hasher := sha256.New()
+	var cid cidBuilder
+	n, err := io.Copy(io.MultiWriter(f, hasher, &cid), io.LimitReader(br, u.maxBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		upload.Remove()
+		return nil, fmt.Errorf("failed to spool document: %w", err)
+	}
+	if n > u.maxBytes {
+		upload.Remove()
+		return nil, fmt.Errorf("%w of %d MB", ErrUploadTooLarge, u.maxBytes>>20)
+	}
+
+	upload.Size = n
+	upload.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	upload.CID = cid.Sum()
+	return upload, nil
+}
+
+// Check applies the same limits to a document that arrived in memory
+func (u *UploadReceiver) Check(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("document is empty")
+	}
+	if int64(len(data)) > u.maxBytes {
+		return fmt.Errorf("%w of %d MB", ErrUploadTooLarge, u.maxBytes>>20)
+	}
+	_, err := u.sniff(data)
+	return err
+}
+
+func (u *UploadReceiver) sniff(head []byte) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !u.allowed[mediaType] {
+		return "", fmt.Errorf("%w %s", ErrUnsupportedDocument, mediaType)
+	}
+	return mediaType, nil
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blockcred-backend/internal/config"
+	"blockcred-backend/internal/jcs"
+	"blockcred-backend/internal/models"
+	"blockcred-backend/internal/multibase"
+	"blockcred-backend/internal/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	vcContextV2   = "https://www.w3.org/ns/credentials/v2"
+	vcCryptosuite = "eddsa-jcs-2022"
+	vcStatusType  = "BlockCredCertificateStatus"
+)
+
+// ErrCredentialUnavailable is returned when a certificate cannot be exported in its current state
+var ErrCredentialUnavailable = errors.New("credential unavailable")
+
+// VCService exports certificates as W3C Verifiable Credentials and verifies presented ones
+type VCService struct {
+	store        store.Store
+	certificates *CertificateService
+	keys         *InstitutionKeys
+	verifyURL    string
+	chainID      int64
+}
+
+func NewVCService(cfg config.Config, s store.Store, certificates *CertificateService, keys *InstitutionKeys) *VCService {
+	return &VCService{
+		store:        s,
+		certificates: certificates,
+		keys:         keys,
+		verifyURL:    cfg.PublicVerifyURL,
+		chainID:      cfg.ChainID,
+	}
+}
+
+// SignedCredential returns the certificate as a VC secured with an eddsa-jcs-2022 Data Integrity proof
+func (v *VCService) SignedCredential(certID string, user models.User) (*models.VerifiableCredential, error) {
+	cert, err := v.exportableCertificate(certID, user)
+	if err != nil {
+		return nil, err
+	}
+	vc := v.buildCredential(cert)
+
+	proof, err := v.signDataIntegrity(vc, vc.Context)
+	if err != nil {
+		return nil, err
+	}
+	vc.Proof = proof
+	return vc, nil
+}
+
+// CredentialJWT returns the certificate as a VC-JOSE secured JWT (typ vc+jwt)
+func (v *VCService) CredentialJWT(certID string, user models.User) (string, error) {
+	cert, err := v.exportableCertificate(certID, user)
+	if err != nil {
+		return "", err
+	}
+	return v.signJWT(v.buildCredential(cert))
+}
+
+// signDataIntegrity produces an eddsa-jcs-2022 proof over an unsecured credential
+func (v *VCService) signDataIntegrity(credential interface{}, context []string) (*models.DataIntegrityProof, error) {
+	proof := models.DataIntegrityProof{
+		Context:            context,
+		Type:               "DataIntegrityProof",
+		Cryptosuite:        vcCryptosuite,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		ProofPurpose:       "assertionMethod",
+		VerificationMethod: v.keys.CurrentKeyID(),
+	}
+
+	hashData, err := dataIntegrityHash(credential, proof)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := v.keys.SignWith(proof.VerificationMethod, hashData)
+	if err != nil {
+		return nil, err
+	}
+	proof.ProofValue = multibase.Encode(sig)
+	return &proof, nil
+}
+
+// signJWT secures a credential as a compact JWS with the credential as payload
+func (v *VCService) signJWT(credential interface{}) (string, error) {
+	return v.signJWS(map[string]string{"typ": "vc+jwt", "cty": "vc"}, credential)
+}
+
+// signJWS signs claims as a compact EdDSA JWS with the current institution key
+func (v *VCService) signJWS(header map[string]string, claims interface{}) (string, error) {
+	kid := v.keys.CurrentKeyID()
+	fields := map[string]string{"alg": "EdDSA", "kid": kid}
+	for k, val := range header {
+		fields[k] = val
+	}
+	headerJSON, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := v.keys.SignWith(kid, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// exportableCertificate checks access and that the certificate is in a state that may be exported
+func (v *VCService) exportableCertificate(certID string, user models.User) (models.Certificate, error) {
+	cert, err := v.store.GetCertificateByCertID(certID)
+	if err != nil {
+		return models.Certificate{}, fmt.Errorf("certificate not found: %w", err)
+	}
+	if !v.certificates.canAccessCertificate(user, cert) {
+		return models.Certificate{}, fmt.Errorf("%w: certificate belongs to another student", ErrPermissionDenied)
+	}
+	if cert.Status != models.CertStatusIssued && cert.Status != models.CertStatusVerified {
+		return models.Certificate{}, fmt.Errorf("%w: certificate is %s", ErrCredentialUnavailable, cert.Status)
+	}
+	return cert, nil
+}
+
+func (v *VCService) buildCredential(cert models.Certificate) *models.VerifiableCredential {
+	meta := cert.Metadata
+	subject := models.VCSubject{
+		StudentID: cert.StudentID,
+		Name:      meta.StudentName,
+		Email:     meta.StudentEmail,
+		Achievement: models.VCAchievementClaims{
+			CertificateType: string(cert.CertType),
+			Institution:     meta.Institution,
+			Department:      meta.Department,
+			Course:          meta.Course,
+			Semester:        meta.Semester,
+			AcademicYear:    meta.AcademicYear,
+			Grade:           meta.Grade,
+			CGPA:            meta.CGPA,
+			Description:     meta.Description,
+		},
+	}
+	subject.ID = v.subjectDID(cert)
+
+	vc := &models.VerifiableCredential{
+		Context:           []string{vcContextV2},
+		ID:                v.verifyURL + cert.CertID,
+		Type:              []string{"VerifiableCredential", "AcademicCertificateCredential"},
+		Issuer:            models.VCIssuer{ID: v.keys.DID(), Name: v.keys.Name()},
+		ValidFrom:         cert.IssuedAt.UTC().Format(time.RFC3339),
+		CredentialSubject: subject,
+		CredentialStatus: &models.VCStatus{
+			ID:            v.verifyURL + cert.CertID,
+			Type:          vcStatusType,
+			CertificateID: cert.CertID,
+		},
+		Evidence: []models.VCEvidence{{
+			Type:            []string{"BlockchainAnchor"},
+			TransactionHash: cert.TxHash,
+			BlockNumber:     cert.BlockNumber,
+			DocumentHash:    cert.FileHash,
+			IPFSCID:         cert.IPFSCID,
+			IPFSURL:         cert.IPFSURL,
+		}},
+	}
+	if !meta.ValidUntil.IsZero() {
+		vc.ValidUntil = meta.ValidUntil.UTC().Format(time.RFC3339)
+	}
+	return vc
+}
+
+// subjectDID returns the student's wallet as a did:ethr when the certificate records one
+func (v *VCService) subjectDID(cert models.Certificate) string {
+	if wallet, ok := cert.Metadata.AdditionalData["student_wallet"].(string); ok && common.IsHexAddress(wallet) {
+		return ethrDID(v.chainID, wallet)
+	}
+	return ""
+}
+
+// Verify checks the signature of a presented credential and the live status of its certificate
+func (v *VCService) Verify(req models.VCVerifyRequest) (*models.VCVerificationResult, error) {
+	result := &models.VCVerificationResult{}
+	var credential map[string]interface{}
+
+	switch {
+	case req.JWT != "":
+		result.Format = "jwt"
+		claims, kid, err := v.verifyJWT(req.JWT)
+		result.VerificationMethod = kid
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.SignatureValid = true
+		}
+		credential = claims
+	case len(req.Credential) > 0:
+		result.Format = "data_integrity"
+		if err := json.Unmarshal(req.Credential, &credential); err != nil {
+			return nil, fmt.Errorf("credential is not a JSON object: %w", err)
+		}
+		kid, err := v.verifyDataIntegrity(credential)
+		result.VerificationMethod = kid
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.SignatureValid = true
+		}
+	default:
+		return nil, fmt.Errorf("either credential or jwt is required")
+	}
+
+	if credential != nil {
+		result.Errors = append(result.Errors, v.checkClaims(credential, result)...)
+	}
+	result.Valid = result.SignatureValid && len(result.Errors) == 0
+	return result, nil
+}
+
+func (v *VCService) verifyDataIntegrity(credential map[string]interface{}) (string, error) {
+	proof, ok := credential["proof"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("credential has no proof")
+	}
+	if proof["type"] != "DataIntegrityProof" || proof["cryptosuite"] != vcCryptosuite {
+		return "", fmt.Errorf("unsupported proof: expected DataIntegrityProof with %s", vcCryptosuite)
+	}
+	kid, _ := proof["verificationMethod"].(string)
+	created, _ := proof["created"].(string)
+	pub, err := v.keys.PublicKey(kid, signingTime(created))
+	if err != nil {
+		return kid, err
+	}
+
+	proofValue, _ := proof["proofValue"].(string)
+	sig, err := multibase.Decode(proofValue)
+	if err != nil {
+		return kid, fmt.Errorf("invalid proofValue: %w", err)
+	}
+
+	unsecured := make(map[string]interface{}, len(credential))
+	for k, val := range credential {
+		if k != "proof" {
+			unsecured[k] = val
+		}
+	}
+	proofConfig := make(map[string]interface{}, len(proof))
+	for k, val := range proof {
+		if k != "proofValue" {
+			proofConfig[k] = val
+		}
+	}
+
+	hashData, err := dataIntegrityHash(unsecured, proofConfig)
+	if err != nil {
+		return kid, err
+	}
+	if !ed25519.Verify(pub, hashData, sig) {
+		return kid, fmt.Errorf("proof signature does not match")
+	}
+	return kid, nil
+}
+
+func (v *VCService) verifyJWT(token string) (map[string]interface{}, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, "", fmt.Errorf("malformed JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(headerJSON, &header) != nil {
+		return nil, "", fmt.Errorf("malformed JWT header")
+	}
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, header.Kid, fmt.Errorf("malformed JWT payload")
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return nil, header.Kid, fmt.Errorf("malformed JWT payload")
+	}
+
+	if header.Alg != "EdDSA" {
+		return claims, header.Kid, fmt.Errorf("unsupported JWT alg %q", header.Alg)
+	}
+	// Credentials carry validFrom; other tokens signed by the institution carry iat
+	signedAt := time.Now()
+	if validFrom, ok := claims["validFrom"].(string); ok {
+		signedAt = signingTime(validFrom)
+	} else if iat, ok := claims["iat"].(float64); ok {
+		signedAt = time.Unix(int64(iat), 0)
+	}
+	pub, err := v.keys.PublicKey(header.Kid, signedAt)
+	if err != nil {
+		return claims, header.Kid, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		return claims, header.Kid, fmt.Errorf("JWT signature does not match")
+	}
+	return claims, header.Kid, nil
+}
+
+// signingTime parses the timestamp a signature claims to have been made at. Unparseable values
+// are treated as now so retired keys cannot be used to back-date new signatures.
+func signingTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
+
+// checkClaims validates issuer, validity period and the certificate behind the credential
+func (v *VCService) checkClaims(credential map[string]interface{}, result *models.VCVerificationResult) []string {
+	var problems []string
+
+	switch issuer := credential["issuer"].(type) {
+	case string:
+		result.Issuer = issuer
+	case map[string]interface{}:
+		result.Issuer, _ = issuer["id"].(string)
+	}
+	if result.Issuer != v.keys.DID() {
+		problems = append(problems, fmt.Sprintf("credential was not issued by %s", v.keys.DID()))
+	}
+
+	if until, ok := credential["validUntil"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, until); err == nil && time.Now().After(t) {
+			problems = append(problems, "credential has expired")
+		}
+	}
+
+	status, _ := credential["credentialStatus"].(map[string]interface{})
+	result.CertID, _ = status["certificateId"].(string)
+	if result.CertID == "" {
+		return append(problems, "credential has no certificate status reference")
+	}
+
+	cert, err := v.store.GetCertificateByCertID(result.CertID)
+	if err != nil {
+		return append(problems, "certificate not found")
+	}
+
+	// The evidence must describe the same document that was anchored on-chain
+	evidence, _ := credential["evidence"].([]interface{})
+	matched := false
+	for _, e := range evidence {
+		if m, ok := e.(map[string]interface{}); ok && m["documentHash"] == cert.FileHash {
+			matched = true
+		}
+	}
+	if !matched {
+		problems = append(problems, "evidence does not match the anchored document hash")
+	}
+
+	check, err := v.certificates.VerifyCertificate(result.CertID)
+	if err != nil {
+		return append(problems, fmt.Sprintf("certificate status check failed: %v", err))
+	}
+	result.Status = check.Status
+	if !check.IsValid {
+		problems = append(problems, check.ErrorMessage)
+	}
+	return problems
+}
+
+// dataIntegrityHash computes SHA-256(JCS(proofConfig)) || SHA-256(JCS(document)) per eddsa-jcs-2022
+func dataIntegrityHash(document, proofConfig interface{}) ([]byte, error) {
+	docJSON, err := jcs.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize credential: %w", err)
+	}
+	proofJSON, err := jcs.Marshal(proofConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize proof: %w", err)
+	}
+
+	proofHash := sha256.Sum256(proofJSON)
+	docHash := sha256.Sum256(docJSON)
+	return append(proofHash[:], docHash[:]...), nil
+}
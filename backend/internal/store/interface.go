@@ -1,6 +1,14 @@
 package store
 
-import "blockcred-backend/internal/models"
+import (
+	"errors"
+	"time"
+
+	"blockcred-backend/internal/models"
+)
+
+// ErrConflict is returned by conditional updates when the record no longer matches the expected state
+var ErrConflict = errors.New("record was changed concurrently")
 
 // Store defines the interface for data storage operations
 type Store interface {
@@ -22,6 +30,92 @@ type Store interface {
 	ListCertificatesByIssuer(issuerID string) ([]models.Certificate, error)
 	UpdateCertificate(certID string, updates models.Certificate) (models.Certificate, error)
 
+	// Approval workflow operations
+	CreateCertificateDraft(draft models.CertificateDraft) (models.CertificateDraft, error)
+	GetCertificateDraftByID(id string) (models.CertificateDraft, error)
+	ListCertificateDrafts() ([]models.CertificateDraft, error)
+	UpdateCertificateDraft(id string, updates models.CertificateDraft) (models.CertificateDraft, error)
+	TransitionCertificateDraft(id string, status models.CertificateStatus, decisions int, updates models.CertificateDraft) (models.CertificateDraft, error)
+	ListApprovalPolicies() ([]models.ApprovalPolicy, error)
+	GetApprovalPolicy(certType models.CredentialType) (models.ApprovalPolicy, error)
+	UpsertApprovalPolicy(policy models.ApprovalPolicy) (models.ApprovalPolicy, error)
+
+	// Bulk issuance operations
+	CreateBulkJob(job models.BulkIssuanceJob) (models.BulkIssuanceJob, error)
+	GetBulkJobByID(id string) (models.BulkIssuanceJob, error)
+	ListBulkJobsByIssuer(issuerID string) ([]models.BulkIssuanceJob, error)
+	UpdateBulkJob(id string, updates models.BulkIssuanceJob) (models.BulkIssuanceJob, error)
+
+	// Certificate template operations
+	CreateCertificateTemplate(tpl models.CertificateTemplate) (models.CertificateTemplate, error)
+	GetCertificateTemplateByID(id string) (models.CertificateTemplate, error)
+	ListCertificateTemplates(institution string, certType models.CredentialType) ([]models.CertificateTemplate, error)
+	UpdateCertificateTemplate(id string, updates models.CertificateTemplate) (models.CertificateTemplate, error)
+
+	// DID operations
+	CreateInstitutionKey(key models.InstitutionKey) (models.InstitutionKey, error)
+	ListInstitutionKeys() ([]models.InstitutionKey, error)
+	UpdateInstitutionKey(id string, updates models.InstitutionKey) (models.InstitutionKey, error)
+	UpsertIssuerDID(issuer models.IssuerDID) (models.IssuerDID, error)
+	GetIssuerDIDByAddress(address string) (models.IssuerDID, error)
+	ListIssuerDIDs() ([]models.IssuerDID, error)
+
+	// Open Badges operations
+	GetOrCreateBadgeAchievement(achievement models.BadgeAchievement) (models.BadgeAchievement, error)
+	GetBadgeAchievementByID(id string) (models.BadgeAchievement, error)
+	ListBadgeAchievements(clubName string) ([]models.BadgeAchievement, error)
+
+	// Blockcerts operations
+	CreateBlockcertsBatch(batch models.BlockcertsBatch) (models.BlockcertsBatch, error)
+	GetLatestBlockcertsBatchByCertID(certID string) (models.BlockcertsBatch, error)
+	ListBlockcertsBatches() ([]models.BlockcertsBatch, error)
+
+	// Share link operations
+	CreateShareLink(link models.ShareLink) (models.ShareLink, error)
+	GetShareLinkByID(id string) (models.ShareLink, error)
+	GetShareLinkByTokenHash(tokenHash string) (models.ShareLink, error)
+	ListShareLinksByStudent(studentID string) ([]models.ShareLink, error)
+	UpdateShareLink(id string, updates models.ShareLink) (models.ShareLink, error)
+	RecordShareLinkView(id string, at time.Time) (models.ShareLink, error)
+	CreateShareAccess(access models.ShareAccess) (models.ShareAccess, error)
+	ListShareAccessByLink(linkID string) ([]models.ShareAccess, error)
+
+	// Verifier access request operations
+	CreateAccessRequest(req models.AccessRequest) (models.AccessRequest, error)
+	GetAccessRequestByID(id string) (models.AccessRequest, error)
+	ListAccessRequestsByStudent(studentID string) ([]models.AccessRequest, error)
+	ListAccessRequestsByVerifier(verifierID string) ([]models.AccessRequest, error)
+	UpdateAccessRequest(id string, updates models.AccessRequest) (models.AccessRequest, error)
+	CreateAccessAuditEntry(entry models.AccessAuditEntry) (models.AccessAuditEntry, error)
+	ListAccessAuditEntries(requestID string) ([]models.AccessAuditEntry, error)
+
+	// Degree eligibility rule operations
+	ListEligibilityRules() ([]models.DegreeEligibilityRule, error)
+	GetEligibilityRule(department string) (models.DegreeEligibilityRule, error)
+	UpsertEligibilityRule(rule models.DegreeEligibilityRule) (models.DegreeEligibilityRule, error)
+
+	// Idempotency key operations
+	CreateIdempotencyRecord(rec models.IdempotencyRecord) (models.IdempotencyRecord, error)
+	GetIdempotencyRecord(userID, key string) (models.IdempotencyRecord, error)
+	UpdateIdempotencyRecord(id string, updates models.IdempotencyRecord) (models.IdempotencyRecord, error)
+	DeleteIdempotencyRecord(id string) error
+
+	// Pin tracking operations
+	UpsertPinRecord(rec models.PinRecord) (models.PinRecord, error)
+	ListPinRecords() ([]models.PinRecord, error)
+	ListPinRecordsByCID(cid string) ([]models.PinRecord, error)
+
+	// Quarantine operations
+	CreateQuarantinedDocument(doc models.QuarantinedDocument) (models.QuarantinedDocument, error)
+	ListQuarantinedDocuments() ([]models.QuarantinedDocument, error)
+	GetQuarantinedDocument(id string) (models.QuarantinedDocument, error)
+	DeleteQuarantinedDocument(id string) error
+
+	// Signing key inventory operations
+	CreateSigningKey(key models.SigningKey) (models.SigningKey, error)
+	ListSigningKeys() ([]models.SigningKey, error)
+	UpdateSigningKey(id string, updates models.SigningKey) (models.SigningKey, error)
+
 	// Credential operations
 	CreateCredential(credential models.Credential) (models.Credential, error)
 	ListCredentials() ([]models.Credential, error)
@@ -14,6 +14,23 @@ type MemoryStore struct {
 	users         []models.User
 	credentials   []models.Credential
 	certificates  []models.Certificate
+	drafts        []models.CertificateDraft
+	policies      []models.ApprovalPolicy
+	bulkJobs      []models.BulkIssuanceJob
+	templates     []models.CertificateTemplate
+	keys          []models.InstitutionKey
+	issuerDIDs    []models.IssuerDID
+	achievements  []models.BadgeAchievement
+	batches       []models.BlockcertsBatch
+	shareLinks    []models.ShareLink
+	shareAccess   []models.ShareAccess
+	accessReqs    []models.AccessRequest
+	accessAudit   []models.AccessAuditEntry
+	eligibility   []models.DegreeEligibilityRule
+	idempotency   []models.IdempotencyRecord
+	pins          []models.PinRecord
+	quarantine    []models.QuarantinedDocument
+	signingKeys   []models.SigningKey
 	nextUserID    int
 	nextCredID    int
 	nextCertID    int
@@ -272,6 +289,878 @@ func (s *MemoryStore) UpdateCertificate(certID string, updates models.Certificat
 	return models.Certificate{}, fmt.Errorf("certificate not found")
 }
 
+// Approval workflow operations
+
+func (s *MemoryStore) CreateCertificateDraft(draft models.CertificateDraft) (models.CertificateDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draft.ID = primitive.NewObjectID()
+	s.drafts = append(s.drafts, draft)
+	return draft, nil
+}
+
+func (s *MemoryStore) GetCertificateDraftByID(id string) (models.CertificateDraft, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID")
+	}
+
+	for _, draft := range s.drafts {
+		if draft.ID == objectID {
+			return draft, nil
+		}
+	}
+	return models.CertificateDraft{}, fmt.Errorf("draft not found")
+}
+
+func (s *MemoryStore) ListCertificateDrafts() ([]models.CertificateDraft, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.CertificateDraft, len(s.drafts))
+	copy(out, s.drafts)
+	return out, nil
+}
+
+func (s *MemoryStore) UpdateCertificateDraft(id string, updates models.CertificateDraft) (models.CertificateDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID")
+	}
+
+	for i, draft := range s.drafts {
+		if draft.ID == objectID {
+			updates.ID = draft.ID
+			updates.CreatedAt = draft.CreatedAt
+			s.drafts[i] = updates
+			return updates, nil
+		}
+	}
+	return models.CertificateDraft{}, fmt.Errorf("draft not found")
+}
+
+// TransitionCertificateDraft replaces a draft only while it still has the given status and number of
+// decisions, so two checkers deciding at once cannot both act on the same state
+func (s *MemoryStore) TransitionCertificateDraft(id string, status models.CertificateStatus, decisions int, updates models.CertificateDraft) (models.CertificateDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID")
+	}
+
+	for i, draft := range s.drafts {
+		if draft.ID == objectID {
+			if draft.Status != status || len(draft.Decisions) != decisions {
+				return models.CertificateDraft{}, ErrConflict
+			}
+			updates.ID = draft.ID
+			updates.CreatedAt = draft.CreatedAt
+			s.drafts[i] = updates
+			return updates, nil
+		}
+	}
+	return models.CertificateDraft{}, fmt.Errorf("draft not found")
+}
+
+func (s *MemoryStore) ListApprovalPolicies() ([]models.ApprovalPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.ApprovalPolicy, len(s.policies))
+	copy(out, s.policies)
+	return out, nil
+}
+
+func (s *MemoryStore) GetApprovalPolicy(certType models.CredentialType) (models.ApprovalPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, policy := range s.policies {
+		if policy.CertType == certType {
+			return policy, nil
+		}
+	}
+	return models.ApprovalPolicy{}, fmt.Errorf("approval policy not found")
+}
+
+func (s *MemoryStore) UpsertApprovalPolicy(policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.policies {
+		if existing.CertType == policy.CertType {
+			policy.ID = existing.ID
+			s.policies[i] = policy
+			return policy, nil
+		}
+	}
+	policy.ID = primitive.NewObjectID()
+	s.policies = append(s.policies, policy)
+	return policy, nil
+}
+
+// Bulk issuance operations
+
+func (s *MemoryStore) CreateBulkJob(job models.BulkIssuanceJob) (models.BulkIssuanceJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.ID = primitive.NewObjectID()
+	s.bulkJobs = append(s.bulkJobs, job)
+	return job, nil
+}
+
+func (s *MemoryStore) GetBulkJobByID(id string) (models.BulkIssuanceJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("invalid job ID")
+	}
+
+	for _, job := range s.bulkJobs {
+		if job.ID == objectID {
+			return job, nil
+		}
+	}
+	return models.BulkIssuanceJob{}, fmt.Errorf("bulk job not found")
+}
+
+func (s *MemoryStore) ListBulkJobsByIssuer(issuerID string) ([]models.BulkIssuanceJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.BulkIssuanceJob
+	for _, job := range s.bulkJobs {
+		if job.IssuerID == issuerID {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) UpdateBulkJob(id string, updates models.BulkIssuanceJob) (models.BulkIssuanceJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("invalid job ID")
+	}
+
+	for i, job := range s.bulkJobs {
+		if job.ID == objectID {
+			updates.ID = job.ID
+			updates.CreatedAt = job.CreatedAt
+			s.bulkJobs[i] = updates
+			return updates, nil
+		}
+	}
+	return models.BulkIssuanceJob{}, fmt.Errorf("bulk job not found")
+}
+
+// Certificate template operations
+
+func (s *MemoryStore) CreateCertificateTemplate(tpl models.CertificateTemplate) (models.CertificateTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tpl.ID = primitive.NewObjectID()
+	s.templates = append(s.templates, tpl)
+	return tpl, nil
+}
+
+func (s *MemoryStore) GetCertificateTemplateByID(id string) (models.CertificateTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("invalid template ID")
+	}
+
+	for _, tpl := range s.templates {
+		if tpl.ID == objectID {
+			return tpl, nil
+		}
+	}
+	return models.CertificateTemplate{}, fmt.Errorf("template not found")
+}
+
+// ListCertificateTemplates filters by institution and cert type; empty values match everything
+func (s *MemoryStore) ListCertificateTemplates(institution string, certType models.CredentialType) ([]models.CertificateTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.CertificateTemplate
+	for _, tpl := range s.templates {
+		if (institution == "" || tpl.Institution == institution) && (certType == "" || tpl.CertType == certType) {
+			result = append(result, tpl)
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) UpdateCertificateTemplate(id string, updates models.CertificateTemplate) (models.CertificateTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("invalid template ID")
+	}
+
+	for i, tpl := range s.templates {
+		if tpl.ID == objectID {
+			updates.ID = tpl.ID
+			updates.CreatedAt = tpl.CreatedAt
+			s.templates[i] = updates
+			return updates, nil
+		}
+	}
+	return models.CertificateTemplate{}, fmt.Errorf("template not found")
+}
+
+// DID operations
+
+func (s *MemoryStore) CreateInstitutionKey(key models.InstitutionKey) (models.InstitutionKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.ID = primitive.NewObjectID()
+	s.keys = append(s.keys, key)
+	return key, nil
+}
+
+func (s *MemoryStore) ListInstitutionKeys() ([]models.InstitutionKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.InstitutionKey, len(s.keys))
+	copy(out, s.keys)
+	return out, nil
+}
+
+func (s *MemoryStore) UpdateInstitutionKey(id string, updates models.InstitutionKey) (models.InstitutionKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.InstitutionKey{}, fmt.Errorf("invalid key ID")
+	}
+
+	for i, key := range s.keys {
+		if key.ID == objectID {
+			updates.ID = key.ID
+			updates.CreatedAt = key.CreatedAt
+			s.keys[i] = updates
+			return updates, nil
+		}
+	}
+	return models.InstitutionKey{}, fmt.Errorf("institution key not found")
+}
+
+// UpsertIssuerDID keeps one mapping per address; re-registration refreshes it
+func (s *MemoryStore) UpsertIssuerDID(issuer models.IssuerDID) (models.IssuerDID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.issuerDIDs {
+		if existing.Address == issuer.Address {
+			issuer.ID = existing.ID
+			s.issuerDIDs[i] = issuer
+			return issuer, nil
+		}
+	}
+
+	issuer.ID = primitive.NewObjectID()
+	s.issuerDIDs = append(s.issuerDIDs, issuer)
+	return issuer, nil
+}
+
+func (s *MemoryStore) GetIssuerDIDByAddress(address string) (models.IssuerDID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, issuer := range s.issuerDIDs {
+		if issuer.Address == address {
+			return issuer, nil
+		}
+	}
+	return models.IssuerDID{}, fmt.Errorf("issuer DID not found")
+}
+
+func (s *MemoryStore) ListIssuerDIDs() ([]models.IssuerDID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.IssuerDID, len(s.issuerDIDs))
+	copy(out, s.issuerDIDs)
+	return out, nil
+}
+
+// Open Badges operations
+
+// GetOrCreateBadgeAchievement returns the achievement for the club event, creating it on first use
+func (s *MemoryStore) GetOrCreateBadgeAchievement(achievement models.BadgeAchievement) (models.BadgeAchievement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.achievements {
+		if existing.ClubName == achievement.ClubName && existing.EventName == achievement.EventName {
+			return existing, nil
+		}
+	}
+
+	achievement.ID = primitive.NewObjectID()
+	s.achievements = append(s.achievements, achievement)
+	return achievement, nil
+}
+
+func (s *MemoryStore) GetBadgeAchievementByID(id string) (models.BadgeAchievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BadgeAchievement{}, fmt.Errorf("invalid achievement ID")
+	}
+
+	for _, achievement := range s.achievements {
+		if achievement.ID == objectID {
+			return achievement, nil
+		}
+	}
+	return models.BadgeAchievement{}, fmt.Errorf("achievement not found")
+}
+
+// ListBadgeAchievements filters by club; an empty club name matches everything
+func (s *MemoryStore) ListBadgeAchievements(clubName string) ([]models.BadgeAchievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.BadgeAchievement
+	for _, achievement := range s.achievements {
+		if clubName == "" || achievement.ClubName == clubName {
+			result = append(result, achievement)
+		}
+	}
+	return result, nil
+}
+
+// Blockcerts operations
+
+func (s *MemoryStore) CreateBlockcertsBatch(batch models.BlockcertsBatch) (models.BlockcertsBatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch.ID = primitive.NewObjectID()
+	s.batches = append(s.batches, batch)
+	return batch, nil
+}
+
+// GetLatestBlockcertsBatchByCertID returns the most recent batch that contains the certificate
+func (s *MemoryStore) GetLatestBlockcertsBatchByCertID(certID string) (models.BlockcertsBatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.batches) - 1; i >= 0; i-- {
+		for _, entry := range s.batches[i].Entries {
+			if entry.CertID == certID {
+				return s.batches[i], nil
+			}
+		}
+	}
+	return models.BlockcertsBatch{}, fmt.Errorf("blockcerts batch not found")
+}
+
+func (s *MemoryStore) ListBlockcertsBatches() ([]models.BlockcertsBatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.BlockcertsBatch, len(s.batches))
+	copy(out, s.batches)
+	return out, nil
+}
+
+// Share link operations
+
+func (s *MemoryStore) CreateShareLink(link models.ShareLink) (models.ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link.ID = primitive.NewObjectID()
+	s.shareLinks = append(s.shareLinks, link)
+	return link, nil
+}
+
+func (s *MemoryStore) GetShareLinkByID(id string) (models.ShareLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID")
+	}
+
+	for _, link := range s.shareLinks {
+		if link.ID == objectID {
+			return link, nil
+		}
+	}
+	return models.ShareLink{}, fmt.Errorf("share link not found")
+}
+
+func (s *MemoryStore) GetShareLinkByTokenHash(tokenHash string) (models.ShareLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, link := range s.shareLinks {
+		if link.TokenHash == tokenHash {
+			return link, nil
+		}
+	}
+	return models.ShareLink{}, fmt.Errorf("share link not found")
+}
+
+func (s *MemoryStore) ListShareLinksByStudent(studentID string) ([]models.ShareLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.ShareLink
+	for i := len(s.shareLinks) - 1; i >= 0; i-- {
+		if s.shareLinks[i].StudentID == studentID {
+			result = append(result, s.shareLinks[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) UpdateShareLink(id string, updates models.ShareLink) (models.ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID")
+	}
+
+	for i, link := range s.shareLinks {
+		if link.ID == objectID {
+			updates.ID = link.ID
+			updates.CreatedAt = link.CreatedAt
+			s.shareLinks[i] = updates
+			return updates, nil
+		}
+	}
+	return models.ShareLink{}, fmt.Errorf("share link not found")
+}
+
+// RecordShareLinkView counts a view unless the link has used up its views
+func (s *MemoryStore) RecordShareLinkView(id string, at time.Time) (models.ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID")
+	}
+
+	for i, link := range s.shareLinks {
+		if link.ID == objectID {
+			if link.MaxViews > 0 && link.Views >= link.MaxViews {
+				return models.ShareLink{}, fmt.Errorf("share link view limit reached")
+			}
+			s.shareLinks[i].Views++
+			s.shareLinks[i].LastAccessedAt = &at
+			return s.shareLinks[i], nil
+		}
+	}
+	return models.ShareLink{}, fmt.Errorf("share link not found")
+}
+
+func (s *MemoryStore) CreateShareAccess(access models.ShareAccess) (models.ShareAccess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	access.ID = primitive.NewObjectID()
+	s.shareAccess = append(s.shareAccess, access)
+	return access, nil
+}
+
+// ListShareAccessByLink returns the access log of a link, most recent first
+func (s *MemoryStore) ListShareAccessByLink(linkID string) ([]models.ShareAccess, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.ShareAccess
+	for i := len(s.shareAccess) - 1; i >= 0; i-- {
+		if s.shareAccess[i].LinkID == linkID {
+			result = append(result, s.shareAccess[i])
+		}
+	}
+	return result, nil
+}
+
+// Verifier access request operations
+
+func (s *MemoryStore) CreateAccessRequest(req models.AccessRequest) (models.AccessRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req.ID = primitive.NewObjectID()
+	s.accessReqs = append(s.accessReqs, req)
+	return req, nil
+}
+
+func (s *MemoryStore) GetAccessRequestByID(id string) (models.AccessRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("invalid access request ID")
+	}
+
+	for _, req := range s.accessReqs {
+		if req.ID == objectID {
+			return req, nil
+		}
+	}
+	return models.AccessRequest{}, fmt.Errorf("access request not found")
+}
+
+func (s *MemoryStore) ListAccessRequestsByStudent(studentID string) ([]models.AccessRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.AccessRequest
+	for i := len(s.accessReqs) - 1; i >= 0; i-- {
+		if s.accessReqs[i].StudentID == studentID {
+			result = append(result, s.accessReqs[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) ListAccessRequestsByVerifier(verifierID string) ([]models.AccessRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.AccessRequest
+	for i := len(s.accessReqs) - 1; i >= 0; i-- {
+		if s.accessReqs[i].VerifierID == verifierID {
+			result = append(result, s.accessReqs[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) UpdateAccessRequest(id string, updates models.AccessRequest) (models.AccessRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("invalid access request ID")
+	}
+
+	for i, req := range s.accessReqs {
+		if req.ID == objectID {
+			updates.ID = req.ID
+			updates.CreatedAt = req.CreatedAt
+			updates.UpdatedAt = time.Now()
+			s.accessReqs[i] = updates
+			return updates, nil
+		}
+	}
+	return models.AccessRequest{}, fmt.Errorf("access request not found")
+}
+
+func (s *MemoryStore) CreateAccessAuditEntry(entry models.AccessAuditEntry) (models.AccessAuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = primitive.NewObjectID()
+	s.accessAudit = append(s.accessAudit, entry)
+	return entry, nil
+}
+
+// ListAccessAuditEntries returns the audit trail of a request in the order it happened
+func (s *MemoryStore) ListAccessAuditEntries(requestID string) ([]models.AccessAuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.AccessAuditEntry
+	for _, entry := range s.accessAudit {
+		if entry.RequestID == requestID {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// Degree eligibility rule operations
+
+func (s *MemoryStore) ListEligibilityRules() ([]models.DegreeEligibilityRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.DegreeEligibilityRule, len(s.eligibility))
+	copy(out, s.eligibility)
+	return out, nil
+}
+
+func (s *MemoryStore) GetEligibilityRule(department string) (models.DegreeEligibilityRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rule := range s.eligibility {
+		if rule.Department == department {
+			return rule, nil
+		}
+	}
+	return models.DegreeEligibilityRule{}, fmt.Errorf("eligibility rule not found")
+}
+
+func (s *MemoryStore) UpsertEligibilityRule(rule models.DegreeEligibilityRule) (models.DegreeEligibilityRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.eligibility {
+		if existing.Department == rule.Department {
+			rule.ID = existing.ID
+			s.eligibility[i] = rule
+			return rule, nil
+		}
+	}
+	rule.ID = primitive.NewObjectID()
+	s.eligibility = append(s.eligibility, rule)
+	return rule, nil
+}
+
+// Idempotency key operations
+
+// CreateIdempotencyRecord reserves a key; an expired record under the same key is replaced
+func (s *MemoryStore) CreateIdempotencyRecord(rec models.IdempotencyRecord) (models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.idempotency {
+		if existing.UserID == rec.UserID && existing.Key == rec.Key {
+			if time.Now().Before(existing.ExpiresAt) {
+				return models.IdempotencyRecord{}, fmt.Errorf("idempotency key already in use")
+			}
+			s.idempotency = append(s.idempotency[:i], s.idempotency[i+1:]...)
+			break
+		}
+	}
+	rec.ID = primitive.NewObjectID()
+	s.idempotency = append(s.idempotency, rec)
+	return rec, nil
+}
+
+func (s *MemoryStore) GetIdempotencyRecord(userID, key string) (models.IdempotencyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rec := range s.idempotency {
+		if rec.UserID == userID && rec.Key == key && time.Now().Before(rec.ExpiresAt) {
+			return rec, nil
+		}
+	}
+	return models.IdempotencyRecord{}, fmt.Errorf("idempotency record not found")
+}
+
+func (s *MemoryStore) UpdateIdempotencyRecord(id string, updates models.IdempotencyRecord) (models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.IdempotencyRecord{}, fmt.Errorf("invalid idempotency record ID")
+	}
+
+	for i, rec := range s.idempotency {
+		if rec.ID == objectID {
+			updates.ID = objectID
+			s.idempotency[i] = updates
+			return updates, nil
+		}
+	}
+	return models.IdempotencyRecord{}, fmt.Errorf("idempotency record not found")
+}
+
+func (s *MemoryStore) DeleteIdempotencyRecord(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid idempotency record ID")
+	}
+
+	for i, rec := range s.idempotency {
+		if rec.ID == objectID {
+			s.idempotency = append(s.idempotency[:i], s.idempotency[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("idempotency record not found")
+}
+
+// Pin tracking operations
+
+// UpsertPinRecord keeps a single record per CID and provider
+func (s *MemoryStore) UpsertPinRecord(rec models.PinRecord) (models.PinRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.pins {
+		if existing.CID == rec.CID && existing.Provider == rec.Provider {
+			rec.ID = existing.ID
+			s.pins[i] = rec
+			return rec, nil
+		}
+	}
+	rec.ID = primitive.NewObjectID()
+	s.pins = append(s.pins, rec)
+	return rec, nil
+}
+
+func (s *MemoryStore) ListPinRecords() ([]models.PinRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.PinRecord, len(s.pins))
+	copy(out, s.pins)
+	return out, nil
+}
+
+func (s *MemoryStore) ListPinRecordsByCID(cid string) ([]models.PinRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []models.PinRecord
+	for _, rec := range s.pins {
+		if rec.CID == cid {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// Quarantine operations
+
+func (s *MemoryStore) CreateQuarantinedDocument(doc models.QuarantinedDocument) (models.QuarantinedDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc.ID = primitive.NewObjectID()
+	s.quarantine = append(s.quarantine, doc)
+	return doc, nil
+}
+
+func (s *MemoryStore) ListQuarantinedDocuments() ([]models.QuarantinedDocument, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.QuarantinedDocument, len(s.quarantine))
+	copy(out, s.quarantine)
+	return out, nil
+}
+
+func (s *MemoryStore) GetQuarantinedDocument(id string) (models.QuarantinedDocument, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.QuarantinedDocument{}, fmt.Errorf("invalid quarantined document ID")
+	}
+
+	for _, doc := range s.quarantine {
+		if doc.ID == objectID {
+			return doc, nil
+		}
+	}
+	return models.QuarantinedDocument{}, fmt.Errorf("quarantined document not found")
+}
+
+func (s *MemoryStore) DeleteQuarantinedDocument(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid quarantined document ID")
+	}
+
+	for i, doc := range s.quarantine {
+		if doc.ID == objectID {
+			s.quarantine = append(s.quarantine[:i], s.quarantine[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("quarantined document not found")
+}
+
+// Signing key inventory operations
+
+func (s *MemoryStore) CreateSigningKey(key models.SigningKey) (models.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.signingKeys {
+		if existing.KeyID == key.KeyID {
+			return models.SigningKey{}, fmt.Errorf("signing key %s already exists", key.KeyID)
+		}
+	}
+	key.ID = primitive.NewObjectID()
+	s.signingKeys = append(s.signingKeys, key)
+	return key, nil
+}
+
+func (s *MemoryStore) ListSigningKeys() ([]models.SigningKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]models.SigningKey, len(s.signingKeys))
+	copy(out, s.signingKeys)
+	return out, nil
+}
+
+func (s *MemoryStore) UpdateSigningKey(id string, updates models.SigningKey) (models.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("invalid signing key ID")
+	}
+
+	for i, key := range s.signingKeys {
+		if key.ID == objectID {
+			updates.ID = key.ID
+			updates.CreatedAt = key.CreatedAt
+			s.signingKeys[i] = updates
+			return updates, nil
+		}
+	}
+	return models.SigningKey{}, fmt.Errorf("signing key not found")
+}
+
 func (s *MemoryStore) Close() error {
 	// Memory store doesn't need cleanup
 	return nil
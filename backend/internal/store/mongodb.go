@@ -19,6 +19,23 @@ type MongoDBStore struct {
 	users        *mongo.Collection
 	credentials  *mongo.Collection
 	certificates *mongo.Collection
+	drafts       *mongo.Collection
+	policies     *mongo.Collection
+	bulkJobs     *mongo.Collection
+	templates    *mongo.Collection
+	keys         *mongo.Collection
+	issuerDIDs   *mongo.Collection
+	achievements *mongo.Collection
+	batches      *mongo.Collection
+	shareLinks   *mongo.Collection
+	shareAccess  *mongo.Collection
+	accessReqs   *mongo.Collection
+	accessAudit  *mongo.Collection
+	eligibility  *mongo.Collection
+	idempotency  *mongo.Collection
+	pins         *mongo.Collection
+	quarantine   *mongo.Collection
+	signingKeys  *mongo.Collection
 }
 
 func NewMongoDBStore(uri, database string) (*MongoDBStore, error) {
@@ -42,6 +59,23 @@ func NewMongoDBStore(uri, database string) (*MongoDBStore, error) {
 		users:        db.Collection("users"),
 		credentials:  db.Collection("credentials"),
 		certificates: db.Collection("certificates"),
+		drafts:       db.Collection("certificate_drafts"),
+		policies:     db.Collection("approval_policies"),
+		bulkJobs:     db.Collection("bulk_jobs"),
+		templates:    db.Collection("certificate_templates"),
+		keys:         db.Collection("institution_keys"),
+		issuerDIDs:   db.Collection("issuer_dids"),
+		achievements: db.Collection("badge_achievements"),
+		batches:      db.Collection("blockcerts_batches"),
+		shareLinks:   db.Collection("share_links"),
+		shareAccess:  db.Collection("share_access_log"),
+		accessReqs:   db.Collection("access_requests"),
+		accessAudit:  db.Collection("access_audit_log"),
+		eligibility:  db.Collection("degree_eligibility_rules"),
+		idempotency:  db.Collection("idempotency_keys"),
+		pins:         db.Collection("pin_records"),
+		quarantine:   db.Collection("quarantined_documents"),
+		signingKeys:  db.Collection("signing_keys"),
 	}
 
 	// Create indexes
@@ -77,6 +111,120 @@ func (s *MongoDBStore) createIndexes() error {
 		return err
 	}
 
+	// Create unique index on cert_type for approval policies
+	_, err = s.policies.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cert_type", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on template versions per institution and cert type
+	_, err = s.templates.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "institution", Value: 1}, {Key: "cert_type", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on address for issuer DIDs
+	_, err = s.issuerDIDs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "address", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on club event for badge achievements
+	_, err = s.achievements.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "club_name", Value: 1}, {Key: "event_name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create index on certificate IDs for Blockcerts batch lookups
+	_, err = s.batches.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "entries.cert_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on token hash for share links
+	_, err = s.shareLinks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token_hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create index on link for the share access log
+	_, err = s.shareAccess.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "link_id", Value: 1}, {Key: "accessed_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create indexes on student and verifier for access requests
+	_, err = s.accessReqs.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "student_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "verifier_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create index on request for the access audit log
+	_, err = s.accessAudit.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "request_id", Value: 1}, {Key: "at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on department for degree eligibility rules
+	_, err = s.eligibility.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "department", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on user and key for idempotency records, expiring them once stale
+	_, err = s.idempotency.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on CID and provider for pin records
+	_, err = s.pins.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cid", Value: 1}, {Key: "provider", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create unique index on key_id for the signing key inventory
+	_, err = s.signingKeys.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
 	// Create index on student_id for credentials
 	_, err = s.credentials.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys: bson.D{{Key: "student_id", Value: 1}},
@@ -382,6 +530,1147 @@ func (s *MongoDBStore) UpdateCertificate(certID string, updates models.Certifica
 	return s.GetCertificateByCertID(certID)
 }
 
+// Approval workflow operations
+
+func (s *MongoDBStore) CreateCertificateDraft(draft models.CertificateDraft) (models.CertificateDraft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.drafts.InsertOne(ctx, draft)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	var created models.CertificateDraft
+	err = s.drafts.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&created)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("failed to retrieve created draft: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *MongoDBStore) GetCertificateDraftByID(id string) (models.CertificateDraft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID: %w", err)
+	}
+
+	var draft models.CertificateDraft
+	err = s.drafts.FindOne(ctx, bson.M{"_id": objectID}).Decode(&draft)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.CertificateDraft{}, fmt.Errorf("draft not found")
+		}
+		return models.CertificateDraft{}, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+func (s *MongoDBStore) ListCertificateDrafts() ([]models.CertificateDraft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.drafts.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drafts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var drafts []models.CertificateDraft
+	if err = cursor.All(ctx, &drafts); err != nil {
+		return nil, fmt.Errorf("failed to decode drafts: %w", err)
+	}
+
+	return drafts, nil
+}
+
+func (s *MongoDBStore) UpdateCertificateDraft(id string, updates models.CertificateDraft) (models.CertificateDraft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID: %w", err)
+	}
+
+	updates.ID = objectID
+	updates.UpdatedAt = time.Now()
+
+	result, err := s.drafts.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.CertificateDraft{}, fmt.Errorf("draft not found")
+	}
+
+	return s.GetCertificateDraftByID(id)
+}
+
+// TransitionCertificateDraft replaces a draft only while it still has the given status and number of
+// decisions, so two checkers deciding at once cannot both act on the same state
+func (s *MongoDBStore) TransitionCertificateDraft(id string, status models.CertificateStatus, decisions int, updates models.CertificateDraft) (models.CertificateDraft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateDraft{}, fmt.Errorf("invalid draft ID: %w", err)
+	}
+
+	updates.ID = objectID
+	updates.UpdatedAt = time.Now()
+
+	filter := bson.M{
+		"_id":    objectID,
+		"status": status,
+		"$expr":  bson.M{"$eq": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$decisions", bson.A{}}}}, decisions}},
+	}
+	opts := options.FindOneAndReplace().SetReturnDocument(options.After)
+
+	var draft models.CertificateDraft
+	err = s.drafts.FindOneAndReplace(ctx, filter, updates, opts).Decode(&draft)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := s.GetCertificateDraftByID(id); getErr != nil {
+				return models.CertificateDraft{}, getErr
+			}
+			return models.CertificateDraft{}, ErrConflict
+		}
+		return models.CertificateDraft{}, fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+func (s *MongoDBStore) ListApprovalPolicies() ([]models.ApprovalPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.policies.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval policies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var policies []models.ApprovalPolicy
+	if err = cursor.All(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("failed to decode approval policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (s *MongoDBStore) GetApprovalPolicy(certType models.CredentialType) (models.ApprovalPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var policy models.ApprovalPolicy
+	err := s.policies.FindOne(ctx, bson.M{"cert_type": certType}).Decode(&policy)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ApprovalPolicy{}, fmt.Errorf("approval policy not found")
+		}
+		return models.ApprovalPolicy{}, fmt.Errorf("failed to get approval policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *MongoDBStore) UpsertApprovalPolicy(policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"cert_type":      policy.CertType,
+		"required_roles": policy.RequiredRoles,
+		"updated_by":     policy.UpdatedBy,
+		"updated_at":     policy.UpdatedAt,
+	}}
+	_, err := s.policies.UpdateOne(ctx, bson.M{"cert_type": policy.CertType}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return models.ApprovalPolicy{}, fmt.Errorf("failed to save approval policy: %w", err)
+	}
+
+	return s.GetApprovalPolicy(policy.CertType)
+}
+
+// Bulk issuance operations
+
+func (s *MongoDBStore) CreateBulkJob(job models.BulkIssuanceJob) (models.BulkIssuanceJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.bulkJobs.InsertOne(ctx, job)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("failed to create bulk job: %w", err)
+	}
+
+	job.ID = result.InsertedID.(primitive.ObjectID)
+	return job, nil
+}
+
+func (s *MongoDBStore) GetBulkJobByID(id string) (models.BulkIssuanceJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	var job models.BulkIssuanceJob
+	err = s.bulkJobs.FindOne(ctx, bson.M{"_id": objectID}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.BulkIssuanceJob{}, fmt.Errorf("bulk job not found")
+		}
+		return models.BulkIssuanceJob{}, fmt.Errorf("failed to get bulk job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (s *MongoDBStore) ListBulkJobsByIssuer(issuerID string) ([]models.BulkIssuanceJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.bulkJobs.Find(ctx, bson.M{"issuer_id": issuerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []models.BulkIssuanceJob
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (s *MongoDBStore) UpdateBulkJob(id string, updates models.BulkIssuanceJob) (models.BulkIssuanceJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("invalid job ID: %w", err)
+	}
+
+	updates.ID = objectID
+	updates.UpdatedAt = time.Now()
+
+	result, err := s.bulkJobs.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.BulkIssuanceJob{}, fmt.Errorf("failed to update bulk job: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.BulkIssuanceJob{}, fmt.Errorf("bulk job not found")
+	}
+
+	return updates, nil
+}
+
+// Certificate template operations
+
+func (s *MongoDBStore) CreateCertificateTemplate(tpl models.CertificateTemplate) (models.CertificateTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.templates.InsertOne(ctx, tpl)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	tpl.ID = result.InsertedID.(primitive.ObjectID)
+	return tpl, nil
+}
+
+func (s *MongoDBStore) GetCertificateTemplateByID(id string) (models.CertificateTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("invalid template ID: %w", err)
+	}
+
+	var tpl models.CertificateTemplate
+	err = s.templates.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tpl)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.CertificateTemplate{}, fmt.Errorf("template not found")
+		}
+		return models.CertificateTemplate{}, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return tpl, nil
+}
+
+// ListCertificateTemplates filters by institution and cert type; empty values match everything
+func (s *MongoDBStore) ListCertificateTemplates(institution string, certType models.CredentialType) ([]models.CertificateTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if institution != "" {
+		filter["institution"] = institution
+	}
+	if certType != "" {
+		filter["cert_type"] = certType
+	}
+
+	cursor, err := s.templates.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "version", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var templates []models.CertificateTemplate
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (s *MongoDBStore) UpdateCertificateTemplate(id string, updates models.CertificateTemplate) (models.CertificateTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("invalid template ID: %w", err)
+	}
+
+	updates.ID = objectID
+	updates.UpdatedAt = time.Now()
+
+	result, err := s.templates.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.CertificateTemplate{}, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.CertificateTemplate{}, fmt.Errorf("template not found")
+	}
+
+	return updates, nil
+}
+
+// DID operations
+
+func (s *MongoDBStore) CreateInstitutionKey(key models.InstitutionKey) (models.InstitutionKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.keys.InsertOne(ctx, key)
+	if err != nil {
+		return models.InstitutionKey{}, fmt.Errorf("failed to create institution key: %w", err)
+	}
+
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return key, nil
+}
+
+func (s *MongoDBStore) ListInstitutionKeys() ([]models.InstitutionKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.keys.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list institution keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []models.InstitutionKey
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode institution keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *MongoDBStore) UpdateInstitutionKey(id string, updates models.InstitutionKey) (models.InstitutionKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.InstitutionKey{}, fmt.Errorf("invalid key ID: %w", err)
+	}
+
+	updates.ID = objectID
+	result, err := s.keys.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.InstitutionKey{}, fmt.Errorf("failed to update institution key: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.InstitutionKey{}, fmt.Errorf("institution key not found")
+	}
+
+	return updates, nil
+}
+
+// UpsertIssuerDID keeps one mapping per address; re-registration refreshes it
+func (s *MongoDBStore) UpsertIssuerDID(issuer models.IssuerDID) (models.IssuerDID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"did":           issuer.DID,
+		"name":          issuer.Name,
+		"role":          issuer.Role,
+		"institution":   issuer.Institution,
+		"registered_by": issuer.RegisteredBy,
+		"registered_at": issuer.RegisteredAt,
+	}}
+	_, err := s.issuerDIDs.UpdateOne(ctx, bson.M{"address": issuer.Address}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return models.IssuerDID{}, fmt.Errorf("failed to upsert issuer DID: %w", err)
+	}
+
+	return s.GetIssuerDIDByAddress(issuer.Address)
+}
+
+func (s *MongoDBStore) GetIssuerDIDByAddress(address string) (models.IssuerDID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var issuer models.IssuerDID
+	err := s.issuerDIDs.FindOne(ctx, bson.M{"address": address}).Decode(&issuer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.IssuerDID{}, fmt.Errorf("issuer DID not found")
+		}
+		return models.IssuerDID{}, fmt.Errorf("failed to get issuer DID: %w", err)
+	}
+
+	return issuer, nil
+}
+
+func (s *MongoDBStore) ListIssuerDIDs() ([]models.IssuerDID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.issuerDIDs.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issuer DIDs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var issuers []models.IssuerDID
+	if err = cursor.All(ctx, &issuers); err != nil {
+		return nil, fmt.Errorf("failed to decode issuer DIDs: %w", err)
+	}
+
+	return issuers, nil
+}
+
+// Open Badges operations
+
+// GetOrCreateBadgeAchievement returns the achievement for the club event, creating it on first use
+func (s *MongoDBStore) GetOrCreateBadgeAchievement(achievement models.BadgeAchievement) (models.BadgeAchievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"club_name": achievement.ClubName, "event_name": achievement.EventName}
+	update := bson.M{"$setOnInsert": bson.M{
+		"description": achievement.Description,
+		"criteria":    achievement.Criteria,
+		"created_by":  achievement.CreatedBy,
+		"created_at":  achievement.CreatedAt,
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result models.BadgeAchievement
+	if err := s.achievements.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		return models.BadgeAchievement{}, fmt.Errorf("failed to get or create achievement: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *MongoDBStore) GetBadgeAchievementByID(id string) (models.BadgeAchievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.BadgeAchievement{}, fmt.Errorf("invalid achievement ID: %w", err)
+	}
+
+	var achievement models.BadgeAchievement
+	err = s.achievements.FindOne(ctx, bson.M{"_id": objectID}).Decode(&achievement)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.BadgeAchievement{}, fmt.Errorf("achievement not found")
+		}
+		return models.BadgeAchievement{}, fmt.Errorf("failed to get achievement: %w", err)
+	}
+
+	return achievement, nil
+}
+
+// ListBadgeAchievements filters by club; an empty club name matches everything
+func (s *MongoDBStore) ListBadgeAchievements(clubName string) ([]models.BadgeAchievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if clubName != "" {
+		filter["club_name"] = clubName
+	}
+
+	cursor, err := s.achievements.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []models.BadgeAchievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, fmt.Errorf("failed to decode achievements: %w", err)
+	}
+
+	return achievements, nil
+}
+
+// Blockcerts operations
+
+func (s *MongoDBStore) CreateBlockcertsBatch(batch models.BlockcertsBatch) (models.BlockcertsBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.batches.InsertOne(ctx, batch)
+	if err != nil {
+		return models.BlockcertsBatch{}, fmt.Errorf("failed to create blockcerts batch: %w", err)
+	}
+
+	batch.ID = result.InsertedID.(primitive.ObjectID)
+	return batch, nil
+}
+
+// GetLatestBlockcertsBatchByCertID returns the most recent batch that contains the certificate
+func (s *MongoDBStore) GetLatestBlockcertsBatchByCertID(certID string) (models.BlockcertsBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var batch models.BlockcertsBatch
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := s.batches.FindOne(ctx, bson.M{"entries.cert_id": certID}, opts).Decode(&batch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.BlockcertsBatch{}, fmt.Errorf("blockcerts batch not found")
+		}
+		return models.BlockcertsBatch{}, fmt.Errorf("failed to get blockcerts batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+func (s *MongoDBStore) ListBlockcertsBatches() ([]models.BlockcertsBatch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.batches.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blockcerts batches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var batches []models.BlockcertsBatch
+	if err = cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode blockcerts batches: %w", err)
+	}
+
+	return batches, nil
+}
+
+// Share link operations
+
+func (s *MongoDBStore) CreateShareLink(link models.ShareLink) (models.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.shareLinks.InsertOne(ctx, link)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	link.ID = result.InsertedID.(primitive.ObjectID)
+	return link, nil
+}
+
+func (s *MongoDBStore) GetShareLinkByID(id string) (models.ShareLink, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID: %w", err)
+	}
+	return s.findShareLink(bson.M{"_id": objectID})
+}
+
+func (s *MongoDBStore) GetShareLinkByTokenHash(tokenHash string) (models.ShareLink, error) {
+	return s.findShareLink(bson.M{"token_hash": tokenHash})
+}
+
+func (s *MongoDBStore) findShareLink(filter bson.M) (models.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var link models.ShareLink
+	err := s.shareLinks.FindOne(ctx, filter).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ShareLink{}, fmt.Errorf("share link not found")
+		}
+		return models.ShareLink{}, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return link, nil
+}
+
+func (s *MongoDBStore) ListShareLinksByStudent(studentID string) ([]models.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.shareLinks.Find(ctx, bson.M{"student_id": studentID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var links []models.ShareLink
+	if err = cursor.All(ctx, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode share links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (s *MongoDBStore) UpdateShareLink(id string, updates models.ShareLink) (models.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID: %w", err)
+	}
+
+	updates.ID = objectID
+	result, err := s.shareLinks.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("failed to update share link: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.ShareLink{}, fmt.Errorf("share link not found")
+	}
+
+	return s.GetShareLinkByID(id)
+}
+
+// RecordShareLinkView counts a view atomically, so concurrent opens cannot exceed max_views
+func (s *MongoDBStore) RecordShareLinkView(id string, at time.Time) (models.ShareLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ShareLink{}, fmt.Errorf("invalid share link ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+		"$or": bson.A{
+			bson.M{"max_views": bson.M{"$exists": false}},
+			bson.M{"max_views": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$views", "$max_views"}}},
+		},
+	}
+	update := bson.M{"$inc": bson.M{"views": 1}, "$set": bson.M{"last_accessed_at": at}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var link models.ShareLink
+	err = s.shareLinks.FindOneAndUpdate(ctx, filter, update, opts).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ShareLink{}, fmt.Errorf("share link view limit reached")
+		}
+		return models.ShareLink{}, fmt.Errorf("failed to record share link view: %w", err)
+	}
+
+	return link, nil
+}
+
+func (s *MongoDBStore) CreateShareAccess(access models.ShareAccess) (models.ShareAccess, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.shareAccess.InsertOne(ctx, access)
+	if err != nil {
+		return models.ShareAccess{}, fmt.Errorf("failed to record share access: %w", err)
+	}
+
+	access.ID = result.InsertedID.(primitive.ObjectID)
+	return access, nil
+}
+
+// ListShareAccessByLink returns the access log of a link, most recent first
+func (s *MongoDBStore) ListShareAccessByLink(linkID string) ([]models.ShareAccess, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.shareAccess.Find(ctx, bson.M{"link_id": linkID}, options.Find().SetSort(bson.D{{Key: "accessed_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share access log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.ShareAccess
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode share access log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Verifier access request operations
+
+func (s *MongoDBStore) CreateAccessRequest(req models.AccessRequest) (models.AccessRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.accessReqs.InsertOne(ctx, req)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("failed to create access request: %w", err)
+	}
+
+	req.ID = result.InsertedID.(primitive.ObjectID)
+	return req, nil
+}
+
+func (s *MongoDBStore) GetAccessRequestByID(id string) (models.AccessRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("invalid access request ID: %w", err)
+	}
+
+	var req models.AccessRequest
+	err = s.accessReqs.FindOne(ctx, bson.M{"_id": objectID}).Decode(&req)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.AccessRequest{}, fmt.Errorf("access request not found")
+		}
+		return models.AccessRequest{}, fmt.Errorf("failed to get access request: %w", err)
+	}
+
+	return req, nil
+}
+
+func (s *MongoDBStore) ListAccessRequestsByStudent(studentID string) ([]models.AccessRequest, error) {
+	return s.listAccessRequests(bson.M{"student_id": studentID})
+}
+
+func (s *MongoDBStore) ListAccessRequestsByVerifier(verifierID string) ([]models.AccessRequest, error) {
+	return s.listAccessRequests(bson.M{"verifier_id": verifierID})
+}
+
+func (s *MongoDBStore) listAccessRequests(filter bson.M) ([]models.AccessRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.accessReqs.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var requests []models.AccessRequest
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, fmt.Errorf("failed to decode access requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (s *MongoDBStore) UpdateAccessRequest(id string, updates models.AccessRequest) (models.AccessRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("invalid access request ID: %w", err)
+	}
+
+	updates.ID = objectID
+	updates.UpdatedAt = time.Now()
+
+	result, err := s.accessReqs.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.AccessRequest{}, fmt.Errorf("failed to update access request: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.AccessRequest{}, fmt.Errorf("access request not found")
+	}
+
+	return s.GetAccessRequestByID(id)
+}
+
+func (s *MongoDBStore) CreateAccessAuditEntry(entry models.AccessAuditEntry) (models.AccessAuditEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.accessAudit.InsertOne(ctx, entry)
+	if err != nil {
+		return models.AccessAuditEntry{}, fmt.Errorf("failed to record access audit entry: %w", err)
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return entry, nil
+}
+
+// ListAccessAuditEntries returns the audit trail of a request in the order it happened
+func (s *MongoDBStore) ListAccessAuditEntries(requestID string) ([]models.AccessAuditEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.accessAudit.Find(ctx, bson.M{"request_id": requestID}, options.Find().SetSort(bson.D{{Key: "at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access audit log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.AccessAuditEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode access audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Degree eligibility rule operations
+
+func (s *MongoDBStore) ListEligibilityRules() ([]models.DegreeEligibilityRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.eligibility.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "department", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eligibility rules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rules []models.DegreeEligibilityRule
+	if err = cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode eligibility rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *MongoDBStore) GetEligibilityRule(department string) (models.DegreeEligibilityRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var rule models.DegreeEligibilityRule
+	err := s.eligibility.FindOne(ctx, bson.M{"department": department}).Decode(&rule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.DegreeEligibilityRule{}, fmt.Errorf("eligibility rule not found")
+		}
+		return models.DegreeEligibilityRule{}, fmt.Errorf("failed to get eligibility rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (s *MongoDBStore) UpsertEligibilityRule(rule models.DegreeEligibilityRule) (models.DegreeEligibilityRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"department":            rule.Department,
+		"required_semesters":    rule.RequiredSemesters,
+		"min_cgpa":              rule.MinCGPA,
+		"no_active_suspensions": rule.NoActiveSuspensions,
+		"no_duplicate_degree":   rule.NoDuplicateDegree,
+		"updated_by":            rule.UpdatedBy,
+		"updated_at":            rule.UpdatedAt,
+	}}
+	_, err := s.eligibility.UpdateOne(ctx, bson.M{"department": rule.Department}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return models.DegreeEligibilityRule{}, fmt.Errorf("failed to save eligibility rule: %w", err)
+	}
+
+	return s.GetEligibilityRule(rule.Department)
+}
+
+// Idempotency key operations
+
+// CreateIdempotencyRecord reserves a key; an expired record the TTL monitor has not removed yet is replaced
+func (s *MongoDBStore) CreateIdempotencyRecord(rec models.IdempotencyRecord) (models.IdempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.idempotency.DeleteOne(ctx, bson.M{"user_id": rec.UserID, "key": rec.Key, "expires_at": bson.M{"$lte": time.Now()}})
+	if err != nil {
+		return models.IdempotencyRecord{}, fmt.Errorf("failed to clear expired idempotency record: %w", err)
+	}
+
+	result, err := s.idempotency.InsertOne(ctx, rec)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.IdempotencyRecord{}, fmt.Errorf("idempotency key already in use")
+		}
+		return models.IdempotencyRecord{}, fmt.Errorf("failed to create idempotency record: %w", err)
+	}
+
+	rec.ID = result.InsertedID.(primitive.ObjectID)
+	return rec, nil
+}
+
+func (s *MongoDBStore) GetIdempotencyRecord(userID, key string) (models.IdempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var rec models.IdempotencyRecord
+	err := s.idempotency.FindOne(ctx, bson.M{"user_id": userID, "key": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&rec)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.IdempotencyRecord{}, fmt.Errorf("idempotency record not found")
+		}
+		return models.IdempotencyRecord{}, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+
+	return rec, nil
+}
+
+func (s *MongoDBStore) UpdateIdempotencyRecord(id string, updates models.IdempotencyRecord) (models.IdempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.IdempotencyRecord{}, fmt.Errorf("invalid idempotency record ID: %w", err)
+	}
+
+	updates.ID = objectID
+	result, err := s.idempotency.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.IdempotencyRecord{}, fmt.Errorf("failed to update idempotency record: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.IdempotencyRecord{}, fmt.Errorf("idempotency record not found")
+	}
+
+	return updates, nil
+}
+
+func (s *MongoDBStore) DeleteIdempotencyRecord(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid idempotency record ID: %w", err)
+	}
+
+	if _, err := s.idempotency.DeleteOne(ctx, bson.M{"_id": objectID}); err != nil {
+		return fmt.Errorf("failed to delete idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Pin tracking operations
+
+func (s *MongoDBStore) UpsertPinRecord(rec models.PinRecord) (models.PinRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"cid":              rec.CID,
+		"cert_id":          rec.CertID,
+		"provider":         rec.Provider,
+		"status":           rec.Status,
+		"size":             rec.Size,
+		"last_error":       rec.LastError,
+		"last_checked_at":  rec.LastCheckedAt,
+		"last_pinned_at":   rec.LastPinnedAt,
+		"last_repaired_at": rec.LastRepairedAt,
+	}}
+	filter := bson.M{"cid": rec.CID, "provider": rec.Provider}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := s.pins.FindOneAndUpdate(ctx, filter, update, opts).Decode(&rec); err != nil {
+		return models.PinRecord{}, fmt.Errorf("failed to save pin record: %w", err)
+	}
+
+	return rec, nil
+}
+
+func (s *MongoDBStore) ListPinRecords() ([]models.PinRecord, error) {
+	return s.findPinRecords(bson.M{})
+}
+
+func (s *MongoDBStore) ListPinRecordsByCID(cid string) ([]models.PinRecord, error) {
+	return s.findPinRecords(bson.M{"cid": cid})
+}
+
+func (s *MongoDBStore) findPinRecords(filter bson.M) ([]models.PinRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.pins.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "cid", Value: 1}, {Key: "provider", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pin records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []models.PinRecord
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode pin records: %w", err)
+	}
+
+	return records, nil
+}
+
+// Quarantine operations
+
+func (s *MongoDBStore) CreateQuarantinedDocument(doc models.QuarantinedDocument) (models.QuarantinedDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.quarantine.InsertOne(ctx, doc)
+	if err != nil {
+		return models.QuarantinedDocument{}, fmt.Errorf("failed to record quarantined document: %w", err)
+	}
+	doc.ID = result.InsertedID.(primitive.ObjectID)
+	return doc, nil
+}
+
+func (s *MongoDBStore) ListQuarantinedDocuments() ([]models.QuarantinedDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.quarantine.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []models.QuarantinedDocument
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode quarantined documents: %w", err)
+	}
+
+	return docs, nil
+}
+
+func (s *MongoDBStore) GetQuarantinedDocument(id string) (models.QuarantinedDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.QuarantinedDocument{}, fmt.Errorf("invalid quarantined document ID: %w", err)
+	}
+
+	var doc models.QuarantinedDocument
+	err = s.quarantine.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.QuarantinedDocument{}, fmt.Errorf("quarantined document not found")
+		}
+		return models.QuarantinedDocument{}, fmt.Errorf("failed to get quarantined document: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (s *MongoDBStore) DeleteQuarantinedDocument(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid quarantined document ID: %w", err)
+	}
+
+	if _, err := s.quarantine.DeleteOne(ctx, bson.M{"_id": objectID}); err != nil {
+		return fmt.Errorf("failed to delete quarantined document: %w", err)
+	}
+	return nil
+}
+
+// Signing key inventory operations
+
+func (s *MongoDBStore) CreateSigningKey(key models.SigningKey) (models.SigningKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := s.signingKeys.InsertOne(ctx, key)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return key, nil
+}
+
+func (s *MongoDBStore) ListSigningKeys() ([]models.SigningKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.signingKeys.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []models.SigningKey
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *MongoDBStore) UpdateSigningKey(id string, updates models.SigningKey) (models.SigningKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("invalid signing key ID: %w", err)
+	}
+
+	updates.ID = objectID
+	result, err := s.signingKeys.ReplaceOne(ctx, bson.M{"_id": objectID}, updates)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("failed to update signing key: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return models.SigningKey{}, fmt.Errorf("signing key not found")
+	}
+
+	return updates, nil
+}
+
 func (s *MongoDBStore) CreateCredential(c models.Credential) (models.Credential, error) {
 	ctx := context.Background()
 	
@@ -438,8 +438,8 @@ const ClubDashboard: React.FC = () => {
                                                             .then(res => res.json())
                                                             .then(data => {
                                                                 if (data.success && data.data.is_valid) {
-                                                                    const studentName = data.data.metadata?.student_name || 'Unknown Student';
-                                                                    const issuerName = data.data.metadata?.issuer_name || 'Unknown Issuer';
+                                                                    const studentName = credential.student_name || credential.metadata?.student_name || 'Unknown Student';
+                                                                    const issuerName = credential.metadata?.issuer_name || 'Unknown Issuer';
                                                                     alert(`✅ Certificate is valid!\n\nStudent: ${studentName}\nIssuer: ${issuerName}\nType: ${data.data.cert_type}\nStatus: ${data.data.status}`);
                                                                 } else {
                                                                     alert(`❌ Certificate verification failed: ${data.message}`);
@@ -89,6 +89,8 @@ export default function StudentDashboard() {
                     cert_id: localCert?.cert_id || result.data.cert_id,
                     ipfs_url: localCert?.ipfs_url || result.data.ipfs_url,
                     tx_hash: localCert?.tx_hash || result.data.tx_hash,
+                    // Public verification does not disclose certificate details
+                    metadata: localCert?.metadata,
                 });
             } else {
                 setVerificationResult({